@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+)
+
+type exprInput struct {
+	interactive bool
+	job         string
+	event       string
+	matrix      []string
+	needsFile   string
+	stepsFile   string
+	inputs      []string
+}
+
+func newExprCommand(input *Input) *cobra.Command {
+	exprInput := new(exprInput)
+	cmd := &cobra.Command{
+		Use:   "expr [expression]",
+		Short: "Evaluate an expression with the contexts of a job and print its value and type, or evaluate expressions interactively with -i",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  newExprRunCommand(input, exprInput),
+	}
+	cmd.Flags().BoolVarP(&exprInput.interactive, "interactive", "i", false, "read expressions from stdin and evaluate them one by one, context paths are completed with Tab")
+	cmd.Flags().StringVarP(&exprInput.job, "job", "j", "", "job to evaluate the expressions for (required if the planned workflows have several jobs)")
+	cmd.Flags().StringVar(&exprInput.event, "event", "", "name of the event that triggered the workflow (defaults to 'push' or the first event of the workflow)")
+	cmd.Flags().StringVarP(&input.eventPath, "eventpath", "e", "", "path to event JSON file")
+	cmd.Flags().StringArrayVar(&exprInput.matrix, "matrix", []string{}, "value of the combination of the matrix of the job to use (e.g. --matrix os=ubuntu-latest), defaults to the first combination")
+	cmd.Flags().StringVar(&exprInput.needsFile, "needs", "", "JSON file with the needs context, the outputs of the needed jobs (e.g. {\"build\": {\"outputs\": {\"version\": \"1.0.0\"}}})")
+	cmd.Flags().StringVar(&exprInput.stepsFile, "steps", "", "JSON file with the steps context, the results of the earlier steps (e.g. {\"build\": {\"outcome\": \"success\", \"outputs\": {\"version\": \"1.0.0\"}}})")
+	cmd.Flags().StringArrayVar(&exprInput.inputs, "input", []string{}, "value of the inputs context (e.g. --input name=value)")
+	return cmd
+}
+
+func newExprRunCommand(input *Input, exprInput *exprInput) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !exprInput.interactive {
+			return fmt.Errorf("expected an expression to evaluate, or -i to evaluate expressions interactively")
+		}
+
+		env, config, err := newExprEnvironment(input, exprInput)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			return printExpression(os.Stdout, newExprInterpreter(input, env, config, os.Stdout), args[0])
+		}
+		return evaluateInteractively(input, env, config)
+	}
+}
+
+// newExprInterpreter creates the interpreter for the contexts, with --trace-expressions it writes the evaluation of the sub-expressions
+func newExprInterpreter(input *Input, env *exprparser.EvaluationEnvironment, config exprparser.Config, w io.Writer) exprparser.Interpreter {
+	if input.traceExpressions {
+		config.Trace = func(trace *exprparser.Trace) {
+			fmt.Fprintln(w, trace)
+		}
+	}
+	return exprparser.NewInterpeter(env, config)
+}
+
+// newExprEnvironment creates the contexts of the chosen job and combination of its matrix
+func newExprEnvironment(input *Input, exprInput *exprInput) (*exprparser.EvaluationEnvironment, exprparser.Config, error) {
+	planner, err := model.NewWorkflowPlanner(input.WorkflowsPath(), input.noWorkflowRecurse)
+	if err != nil {
+		return nil, exprparser.Config{}, workflowError(err)
+	}
+	run, err := exprRun(planner, exprInput)
+	if err != nil {
+		return nil, exprparser.Config{}, err
+	}
+
+	fixture := &runner.ExpressionFixture{
+		Inputs: make(map[string]interface{}),
+	}
+	if fixture.Matrix, err = exprMatrix(run, exprInput.matrix); err != nil {
+		return nil, exprparser.Config{}, err
+	}
+	if exprInput.needsFile != "" {
+		needs := make(map[string]struct {
+			Outputs map[string]string `json:"outputs"`
+		})
+		if err := readJSONFile(exprInput.needsFile, &needs); err != nil {
+			return nil, exprparser.Config{}, err
+		}
+		fixture.Needs = make(map[string]map[string]string)
+		for id, job := range needs {
+			fixture.Needs[id] = job.Outputs
+		}
+	}
+	if exprInput.stepsFile != "" {
+		if err := readJSONFile(exprInput.stepsFile, &fixture.Steps); err != nil {
+			return nil, exprparser.Config{}, err
+		}
+	}
+	for _, in := range exprInput.inputs {
+		kv := strings.SplitN(in, "=", 2)
+		if len(kv) != 2 {
+			return nil, exprparser.Config{}, fmt.Errorf("invalid input '%s', expected name=value", in)
+		}
+		fixture.Inputs[kv[0]] = kv[1]
+	}
+
+	config := loadRunnerConfig(input)
+	config.EventName = exprInput.event
+	if events := run.Workflow.On(); config.EventName == "" && len(events) > 0 {
+		config.EventName = events[0]
+		for _, event := range events {
+			if event == "push" {
+				config.EventName = event
+			}
+		}
+	}
+	config.EventPath = input.EventPath()
+	return runner.NewExpressionEnvironment(config, run, fixture)
+}
+
+// exprRun returns the run of the chosen job, or of the only planned job if none is chosen
+func exprRun(planner model.WorkflowPlanner, exprInput *exprInput) (*model.Run, error) {
+	if exprInput.job != "" {
+		for _, stage := range planner.PlanJob(exprInput.job).Stages {
+			for _, run := range stage.Runs {
+				if run.JobID == exprInput.job {
+					return run, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("job '%s' not found in the workflows", exprInput.job)
+	}
+
+	event := exprInput.event
+	if event == "" {
+		if events := planner.GetEvents(); len(events) > 0 {
+			event = events[0]
+		}
+		if plan := planner.PlanEvent("push"); plan != nil && len(plan.Stages) > 0 {
+			event = "push"
+		}
+	}
+	runs := make([]*model.Run, 0)
+	for _, stage := range planner.PlanEvent(event).Stages {
+		runs = append(runs, stage.Runs...)
+	}
+	if len(runs) == 1 {
+		return runs[0], nil
+	}
+	ids := make([]string, 0, len(runs))
+	for _, run := range runs {
+		ids = append(ids, run.JobID)
+	}
+	sort.Strings(ids)
+	return nil, fmt.Errorf("choose the job to evaluate the expressions for with --job, the workflows of event '%s' have the jobs: %s", event, strings.Join(ids, ", "))
+}
+
+// exprMatrix returns the first combination of the matrix of the job with the given values
+func exprMatrix(run *model.Run, values []string) (map[string]interface{}, error) {
+	want := make(map[string]string)
+	for _, value := range values {
+		kv := strings.SplitN(value, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid matrix value '%s', expected key=value", value)
+		}
+		want[kv[0]] = kv[1]
+	}
+
+	for _, matrix := range run.Job().GetMatrixes() {
+		matches := true
+		for k, v := range want {
+			if value, ok := matrix[k]; !ok || fmt.Sprint(value) != v {
+				matches = false
+			}
+		}
+		if matches {
+			return matrix, nil
+		}
+	}
+	return nil, fmt.Errorf("no combination of the matrix of job '%s' matches %s", run.JobID, strings.Join(values, ", "))
+}
+
+func readJSONFile(path string, v interface{}) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return nil
+}
+
+// printExpression evaluates an expression and prints its value as JSON with its type
+func printExpression(w io.Writer, interpreter exprparser.Interpreter, expression string) error {
+	expression = strings.TrimSpace(expression)
+	if strings.HasPrefix(expression, "${{") {
+		expression = strings.TrimSuffix(strings.TrimPrefix(expression, "${{"), "}}")
+	}
+	value, err := interpreter.Evaluate(expression, false)
+	if err != nil {
+		return err
+	}
+
+	var s string
+	if b, err := json.MarshalIndent(value, "", "  "); err == nil {
+		s = string(b)
+	} else {
+		// e.g. NaN and Infinity
+		s = fmt.Sprint(value)
+	}
+	_, err = fmt.Fprintf(w, "%s (%s)\n", s, exprparser.TypeName(value))
+	return err
+}
+
+// evaluateInteractively evaluates the expressions read from stdin until it is closed, a terminal gets a prompt with a history
+// and completion of context paths
+func evaluateInteractively(input *Input, env *exprparser.EvaluationEnvironment, config exprparser.Config) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		interpreter := newExprInterpreter(input, env, config, os.Stdout)
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				if err := printExpression(os.Stdout, interpreter, line); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+				}
+			}
+		}
+		return scanner.Err()
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = term.Restore(fd, state)
+	}()
+
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, "> ")
+	interpreter := newExprInterpreter(input, env, config, terminal)
+	completer := &exprCompleter{env: env, config: config, terminal: terminal}
+	terminal.AutoCompleteCallback = completer.complete
+	fmt.Fprintln(terminal, "Evaluate expressions with the contexts of the job, complete context paths with Tab and exit with Ctrl+D")
+	for {
+		line, err := terminal.ReadLine()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		} else if line == "exit" {
+			return nil
+		}
+		if err := printExpression(terminal, interpreter, line); err != nil {
+			fmt.Fprintln(terminal, err)
+		}
+	}
+}
+
+// exprCompleter completes the context path before the cursor when Tab is pressed
+type exprCompleter struct {
+	env      *exprparser.EvaluationEnvironment
+	config   exprparser.Config
+	terminal *term.Terminal
+}
+
+func (c *exprCompleter) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+	start := pos
+	for start > 0 && isContextPathChar(line[start-1]) {
+		start--
+	}
+	path := line[start:pos]
+	completions := exprparser.Complete(c.env, c.config, path)
+	if len(completions) == 0 {
+		return "", 0, false
+	}
+
+	completion := completions[0]
+	for _, other := range completions[1:] {
+		i := 0
+		for i < len(completion) && i < len(other) && completion[i] == other[i] {
+			i++
+		}
+		completion = completion[:i]
+	}
+	// the paths are listed if they can't be completed any further
+	if len(completions) > 1 && len(completion) <= len(path) {
+		fmt.Fprintln(c.terminal, strings.Join(completions, "  "))
+		return "", 0, false
+	}
+	// a single object is completed with the dot to complete its properties next
+	if len(completions) == 1 && len(exprparser.Complete(c.env, c.config, completion+".")) > 0 {
+		completion += "."
+	}
+	return line[:start] + completion + line[pos:], start + len(completion), true
+}
+
+func isContextPathChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.'
+}
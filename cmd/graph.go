@@ -1,13 +1,29 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
 	"os"
 
 	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/model"
 )
 
-func drawGraph(plan *model.Plan) error {
+var graphWriters = map[string]func(*model.Graph, io.Writer) error{
+	"dot":     (*model.Graph).WriteDOT,
+	"mermaid": (*model.Graph).WriteMermaid,
+	"json":    (*model.Graph).WriteJSON,
+}
+
+func drawGraph(plan *model.Plan, format string) error {
+	if format != "" {
+		write, ok := graphWriters[format]
+		if !ok {
+			return fmt.Errorf("unknown graph format '%s', expected 'dot', 'mermaid' or 'json'", format)
+		}
+		return write(model.NewGraph(plan), os.Stdout)
+	}
+
 	drawings := make([]*common.Drawing, 0)
 
 	jobPen := common.NewPen(common.StyleSingleLine, 96)
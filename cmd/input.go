@@ -24,6 +24,9 @@ type Input struct {
 	noOutput              bool
 	envfile               string
 	secretfile            string
+	vars                  []string
+	varfile               string
+	environments          []string
 	insecureSecrets       bool
 	defaultBranch         string
 	privileged            bool
@@ -39,6 +42,27 @@ type Input struct {
 	artifactServerPath    string
 	artifactServerPort    string
 	jsonLogger            bool
+	noChain               bool
+	chainDepth            int
+	eventsFile            string
+	reports               []string
+	logDir                string
+	groupOutput           string
+	tui                   bool
+	pauseOnFailure        bool
+	pauseBefore           []string
+	reviewDeployments     bool
+	stateFile             string
+	rerunFailed           bool
+	fromStep              string
+	steps                 []string
+	skipSteps             []string
+	runAlwaysSteps        bool
+	graphFormat           string
+	listFormat            string
+	noLint                bool
+	traceExpressions      bool
+	strictExpressions     bool
 }
 
 func (i *Input) resolve(path string) string {
@@ -65,6 +89,11 @@ func (i *Input) Secretfile() string {
 	return i.resolve(i.secretfile)
 }
 
+// Varfile returns path to the configuration variables
+func (i *Input) Varfile() string {
+	return i.resolve(i.varfile)
+}
+
 // Workdir returns path to workdir
 func (i *Input) Workdir() string {
 	return i.resolve(".")
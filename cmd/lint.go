@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/nektos/act/pkg/lint"
+	"github.com/nektos/act/pkg/model"
+)
+
+func newLintCommand(input *Input) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint [workflow files]",
+		Short: "Check the syntax and expressions of the workflows, the needs of their jobs and the local actions they use",
+		RunE:  newLintRunCommand(input),
+	}
+}
+
+func newLintRunCommand(input *Input) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		files := args
+		if len(files) == 0 {
+			var err error
+			if files, err = lint.WorkflowFiles(input.WorkflowsPath(), input.noWorkflowRecurse); err != nil {
+				return err
+			}
+		}
+
+		errs, err := lintWorkflows(input, files)
+		if err != nil {
+			return err
+		}
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("found %d problems in the workflows", len(errs))
+		}
+		return nil
+	}
+}
+
+// checkWorkflows lints the workflows before they are planned, problems that would make the run fail stop it,
+// the other ones are only logged
+func checkWorkflows(input *Input) error {
+	files, err := lint.WorkflowFiles(input.WorkflowsPath(), input.noWorkflowRecurse)
+	if err != nil {
+		return err
+	}
+	errs, err := lintWorkflows(input, files)
+	if err != nil {
+		return err
+	}
+
+	fatal := 0
+	for _, e := range errs {
+		if e.Fatal() {
+			log.Error(e)
+			fatal++
+		} else {
+			log.Debug(e)
+		}
+	}
+	if fatal > 0 {
+		return fmt.Errorf("found %d errors in the workflows, run 'act lint' for all problems or pass --no-lint to run them anyway", fatal)
+	}
+	return nil
+}
+
+// lintWorkflows lints the workflow files, the problems are reported relative to the current directory
+func lintWorkflows(input *Input, files []string) ([]*lint.Error, error) {
+	errs, err := lint.Lint(input.Workdir(), files)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range errs {
+		e.File = relativePath(e.File)
+	}
+	return errs, nil
+}
+
+// workflowError reports the errors of a workflow relative to the current directory like the problems found by lint
+func workflowError(err error) error {
+	var workflowErr *model.Error
+	var workflowErrs model.Errors
+	if errors.As(err, &workflowErrs) {
+		for _, e := range workflowErrs {
+			e.File = relativePath(e.File)
+		}
+	} else if errors.As(err, &workflowErr) {
+		workflowErr.File = relativePath(workflowErr.File)
+	}
+	return err
+}
+
+// relativePath returns an absolute path relative to the current directory
+func relativePath(path string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(cwd, path); err == nil {
+		return rel
+	}
+	return path
+}
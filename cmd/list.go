@@ -1,13 +1,41 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
 )
 
+// printJobs prints the planned jobs with their platform images, matrix and needs as JSON or YAML
+func printJobs(plan *model.Plan, format string, config *runner.Config) error {
+	jobs, err := runner.ListJobs(config, plan)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(jobs)
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(jobs); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		return fmt.Errorf("unknown list format '%s', expected 'json' or 'yaml'", format)
+	}
+}
+
 func printList(plan *model.Plan) error {
 	type lineInfoDef struct {
 		jobID   string
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/runner"
+)
+
+var reportWriters = map[string]func(*runner.Report, io.Writer) error{
+	"json":  (*runner.Report).WriteJSON,
+	"junit": (*runner.Report).WriteJUnit,
+}
+
+// attachReports collects the results of the runner for --report, the returned executor writes the report files
+func attachReports(input *Input, config *runner.Config) (common.Executor, error) {
+	if len(input.reports) == 0 {
+		return func(ctx context.Context) error { return nil }, nil
+	}
+
+	paths := make(map[string]string)
+	for _, r := range input.reports {
+		format := strings.SplitN(r, "=", 2)
+		if len(format) != 2 || format[1] == "" {
+			return nil, fmt.Errorf("invalid report '%s', expected <format>=<path>", r)
+		}
+		if _, ok := reportWriters[format[0]]; !ok {
+			return nil, fmt.Errorf("unknown report format '%s', expected 'junit' or 'json'", format[0])
+		}
+		paths[format[1]] = format[0]
+	}
+
+	report := runner.NewReport(allSecrets(config))
+	addHooks(config, report)
+
+	return func(ctx context.Context) error {
+		for path, format := range paths {
+			log.Debugf("Writing %s report to %s", format, path)
+			if err := writeReport(report, reportWriters[format], path); err != nil {
+				return fmt.Errorf("failed to write %s report: %w", format, err)
+			}
+		}
+		return nil
+	}, nil
+}
+
+func writeReport(report *runner.Report, write func(*runner.Report, io.Writer) error, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(report, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
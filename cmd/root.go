@@ -3,6 +3,8 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -21,11 +23,22 @@ import (
 	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/model"
 	"github.com/nektos/act/pkg/runner"
+	"github.com/nektos/act/pkg/tui"
 )
 
 // Execute is the entry point to running the CLI
 func Execute(ctx context.Context, version string) {
-	input := new(Input)
+	rootCmd := newRootCommand(ctx, new(Input), version)
+	rootCmd.SetArgs(args())
+
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newRootCommand creates the act command with its flags and subcommands, the names of subcommands must not be names of
+// events because the first argument of act is the event to run
+func newRootCommand(ctx context.Context, input *Input, version string) *cobra.Command {
 	var rootCmd = &cobra.Command{
 		Use:              "act [event name to run]\nIf no event name passed, will default to \"on: push\"",
 		Short:            "Run GitHub actions locally by specifying the event name (e.g. `push`) or an action name directly.",
@@ -37,45 +50,70 @@ func Execute(ctx context.Context, version string) {
 	}
 	rootCmd.Flags().BoolP("watch", "w", false, "watch the contents of the local repo and run when files change")
 	rootCmd.Flags().BoolP("list", "l", false, "list workflows")
+	rootCmd.Flags().StringVar(&input.listFormat, "format", "", "print the planned jobs with their platform image, matrix and needs in this format instead of a table, 'json' or 'yaml' (implies --list)")
 	rootCmd.Flags().BoolP("graph", "g", false, "draw workflows")
+	rootCmd.Flags().StringVar(&input.graphFormat, "graph-format", "", "print the graph of the jobs and their 'needs' in this format instead of drawing it, 'dot', 'mermaid' or 'json' (implies --graph)")
 	rootCmd.Flags().StringP("job", "j", "", "run job")
-	rootCmd.Flags().StringArrayVarP(&input.secrets, "secret", "s", []string{}, "secret to make available to actions with optional value (e.g. -s mysecret=foo or -s mysecret)")
-	rootCmd.Flags().StringArrayVarP(&input.envs, "env", "", []string{}, "env to make available to actions with optional value (e.g. --env myenv=foo or --env myenv)")
-	rootCmd.Flags().StringArrayVarP(&input.platforms, "platform", "P", []string{}, "custom image to use per platform (e.g. -P ubuntu-18.04=nektos/act-environments-ubuntu:18.04)")
-	rootCmd.Flags().BoolVarP(&input.reuseContainers, "reuse", "r", false, "don't remove container(s) on successfully completed workflow(s) to maintain state between runs")
-	rootCmd.Flags().BoolVarP(&input.bindWorkdir, "bind", "b", false, "bind working directory to container, rather than copy")
-	rootCmd.Flags().BoolVarP(&input.forcePull, "pull", "p", false, "pull docker image(s) even if already present")
-	rootCmd.Flags().BoolVarP(&input.forceRebuild, "rebuild", "", false, "rebuild local action docker image(s) even if already present")
+	rootCmd.Flags().BoolVar(&input.noLint, "no-lint", false, "don't check the workflows for syntax errors, invalid expressions and needs cycles before running them")
+	rootCmd.Flags().BoolVar(&input.pauseOnFailure, "pause-on-failure", false, "pause a job when a step fails to inspect its container in a shell, then retry the step, skip it or abort the job")
+	rootCmd.Flags().StringArrayVar(&input.pauseBefore, "pause-before", []string{}, "pause a job before the step with this id to inspect its container in a shell (e.g. --pause-before build)")
+	rootCmd.Flags().BoolVar(&input.reviewDeployments, "review-deployments", false, "ask to approve or reject the deployment of a job to its environment before it runs, like a required reviewer of the environment")
+	rootCmd.Flags().StringVar(&input.stateFile, "state-file", "", "file to write the state of the run to, and to read the previous run from for --rerun-failed and --from-step (defaults to a file in the cache of act for the working directory)")
+	rootCmd.Flags().BoolVar(&input.rerunFailed, "rerun-failed", false, "run only the jobs that did not succeed in the previous run, the outputs of the other jobs are taken from the previous run")
+	rootCmd.Flags().StringVar(&input.fromStep, "from-step", "", "resume jobs from the step with this id, name or index, the results of the earlier steps are taken from the previous run (requires --reuse)")
+	rootCmd.Flags().StringArrayVar(&input.steps, "step", []string{}, "run only the steps with this id, name or index in their job (starting at 0), the other steps are skipped (e.g. --step build --step 2)")
+	rootCmd.Flags().StringArrayVar(&input.skipSteps, "skip-step", []string{}, "skip the steps with this id, name or index in their job (starting at 0)")
+	rootCmd.Flags().BoolVar(&input.runAlwaysSteps, "run-always-steps", false, "run the steps with 'always()' in their condition even if they are not selected with --step or are skipped with --skip-step, e.g. to clean up")
+	rootCmd.Flags().BoolVar(&input.tui, "tui", false, "show an interactive terminal UI with the jobs, their steps and logs while the workflows run")
 	rootCmd.Flags().BoolVarP(&input.autodetectEvent, "detect-event", "", false, "Use first event type from workflow as event that triggered the workflow")
 	rootCmd.Flags().StringVarP(&input.eventPath, "eventpath", "e", "", "path to event JSON file")
-	rootCmd.Flags().StringVar(&input.defaultBranch, "defaultbranch", "", "the name of the main branch")
-	rootCmd.Flags().BoolVar(&input.privileged, "privileged", false, "use privileged mode")
-	rootCmd.Flags().StringVar(&input.usernsMode, "userns", "", "user namespace to use")
-	rootCmd.Flags().BoolVar(&input.useGitIgnore, "use-gitignore", true, "Controls whether paths specified in .gitignore should be copied into container")
-	rootCmd.Flags().StringArrayVarP(&input.containerCapAdd, "container-cap-add", "", []string{}, "kernel capabilities to add to the workflow containers (e.g. --container-cap-add SYS_PTRACE)")
-	rootCmd.Flags().StringArrayVarP(&input.containerCapDrop, "container-cap-drop", "", []string{}, "kernel capabilities to remove from the workflow containers (e.g. --container-cap-drop SYS_PTRACE)")
-	rootCmd.Flags().BoolVar(&input.autoRemove, "rm", false, "automatically remove container(s)/volume(s) after a workflow(s) failure")
+	rootCmd.PersistentFlags().StringArrayVarP(&input.secrets, "secret", "s", []string{}, "secret to make available to actions with optional value (e.g. -s mysecret=foo or -s mysecret)")
+	rootCmd.PersistentFlags().StringArrayVar(&input.vars, "var", []string{}, "configuration variable for the vars context, it replaces the one in --var-file (e.g. --var REGION=eu-west-1)")
+	rootCmd.PersistentFlags().StringArrayVarP(&input.envs, "env", "", []string{}, "env to make available to actions with optional value (e.g. --env myenv=foo or --env myenv)")
+	rootCmd.PersistentFlags().StringArrayVarP(&input.platforms, "platform", "P", []string{}, "custom image to use per platform (e.g. -P ubuntu-18.04=nektos/act-environments-ubuntu:18.04)")
+	rootCmd.PersistentFlags().BoolVarP(&input.reuseContainers, "reuse", "r", false, "don't remove container(s) on successfully completed workflow(s) to maintain state between runs")
+	rootCmd.PersistentFlags().BoolVarP(&input.bindWorkdir, "bind", "b", false, "bind working directory to container, rather than copy")
+	rootCmd.PersistentFlags().BoolVarP(&input.forcePull, "pull", "p", false, "pull docker image(s) even if already present")
+	rootCmd.PersistentFlags().BoolVarP(&input.forceRebuild, "rebuild", "", false, "rebuild local action docker image(s) even if already present")
+	rootCmd.PersistentFlags().StringVar(&input.defaultBranch, "defaultbranch", "", "the name of the main branch")
+	rootCmd.PersistentFlags().BoolVar(&input.privileged, "privileged", false, "use privileged mode")
+	rootCmd.PersistentFlags().StringVar(&input.usernsMode, "userns", "", "user namespace to use")
+	rootCmd.PersistentFlags().BoolVar(&input.useGitIgnore, "use-gitignore", true, "Controls whether paths specified in .gitignore should be copied into container")
+	rootCmd.PersistentFlags().StringArrayVarP(&input.containerCapAdd, "container-cap-add", "", []string{}, "kernel capabilities to add to the workflow containers (e.g. --container-cap-add SYS_PTRACE)")
+	rootCmd.PersistentFlags().StringArrayVarP(&input.containerCapDrop, "container-cap-drop", "", []string{}, "kernel capabilities to remove from the workflow containers (e.g. --container-cap-drop SYS_PTRACE)")
+	rootCmd.PersistentFlags().BoolVar(&input.autoRemove, "rm", false, "automatically remove container(s)/volume(s) after a workflow(s) failure")
+	rootCmd.PersistentFlags().BoolVar(&input.noChain, "no-chain", false, "don't run workflows triggered by 'workflow_run' after the planned workflows completed")
+	rootCmd.PersistentFlags().IntVar(&input.chainDepth, "chain-depth", 3, "maximum number of chained 'workflow_run' levels to run")
 	rootCmd.PersistentFlags().StringVarP(&input.actor, "actor", "a", "nektos/act", "user that triggered the event")
 	rootCmd.PersistentFlags().StringVarP(&input.workflowsPath, "workflows", "W", "./.github/workflows/", "path to workflow file(s)")
 	rootCmd.PersistentFlags().BoolVarP(&input.noWorkflowRecurse, "no-recurse", "", false, "Flag to disable running workflows from subdirectories of specified path in '--workflows'/'-W' flag")
 	rootCmd.PersistentFlags().StringVarP(&input.workdir, "directory", "C", ".", "working directory")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolVar(&input.jsonLogger, "json", false, "Output logs in json format")
+	rootCmd.PersistentFlags().StringVar(&input.eventsFile, "events-file", "", "write the progress of the run as newline delimited JSON events to this file, '-' writes them to stdout and the logs to stderr")
+	rootCmd.PersistentFlags().StringVar(&input.groupOutput, "group-output", "", "write the logs of every job in one block when the job completed ('job', the default) or after every step ('step'), so the logs of parallel jobs don't interleave")
+	rootCmd.PersistentFlags().Lookup("group-output").NoOptDefVal = "job"
+	rootCmd.PersistentFlags().StringVar(&input.logDir, "log-dir", "", "write the logs of every job and the output of every step to files in this directory")
+	rootCmd.PersistentFlags().StringArrayVar(&input.reports, "report", []string{}, "write a report of the job and step results after the run, as JUnit XML or JSON (e.g. --report junit=report.xml or --report json=report.json)")
+	rootCmd.PersistentFlags().BoolVar(&input.traceExpressions, "trace-expressions", false, "log how the sub-expressions of the 'if' conditions of jobs and steps evaluated, with their values and implicit conversions (logged at debug level, see --verbose)")
+	rootCmd.PersistentFlags().BoolVar(&input.strictExpressions, "strict-expressions", false, "fail on expressions using a property that doesn't exist on a context like github, runner, job, strategy, steps.<id> or needs.<id> or an unknown step or job id, e.g. a misspelled github.evnet (the payload in github.event can have any property)")
 	rootCmd.PersistentFlags().BoolVarP(&input.noOutput, "quiet", "q", false, "disable logging of output from steps")
 	rootCmd.PersistentFlags().BoolVarP(&input.dryrun, "dryrun", "n", false, "dryrun mode")
 	rootCmd.PersistentFlags().StringVarP(&input.secretfile, "secret-file", "", ".secrets", "file with list of secrets to read from (e.g. --secret-file .secrets)")
 	rootCmd.PersistentFlags().BoolVarP(&input.insecureSecrets, "insecure-secrets", "", false, "NOT RECOMMENDED! Doesn't hide secrets while printing logs.")
+	rootCmd.PersistentFlags().StringVar(&input.varfile, "var-file", ".vars", "file with the configuration variables for the vars context, a dotenv file or YAML or JSON with an object of variables per deployment environment (e.g. --var-file vars.yml)")
+	rootCmd.PersistentFlags().StringArrayVar(&input.environments, "environment", []string{}, "read the secrets and vars of this deployment environment from the files with its name as extension, for environments chosen by an expression (e.g. --environment production)")
 	rootCmd.PersistentFlags().StringVarP(&input.envfile, "env-file", "", ".env", "environment file to read and use as env in the containers")
 	rootCmd.PersistentFlags().StringVarP(&input.containerArchitecture, "container-architecture", "", "", "Architecture which should be used to run containers, e.g.: linux/amd64. If not specified, will use host default architecture. Requires Docker server API Version 1.41+. Ignored on earlier Docker server platforms.")
 	rootCmd.PersistentFlags().StringVarP(&input.containerDaemonSocket, "container-daemon-socket", "", "/var/run/docker.sock", "Path to Docker daemon socket which will be mounted to containers")
 	rootCmd.PersistentFlags().StringVarP(&input.githubInstance, "github-instance", "", "github.com", "GitHub instance to use. Don't use this if you are not using GitHub Enterprise Server.")
 	rootCmd.PersistentFlags().StringVarP(&input.artifactServerPath, "artifact-server-path", "", "", "Defines the path where the artifact server stores uploads and retrieves downloads from. If not specified the artifact server will not start.")
 	rootCmd.PersistentFlags().StringVarP(&input.artifactServerPort, "artifact-server-port", "", "34567", "Defines the port where the artifact server listens (will only bind to localhost).")
-	rootCmd.SetArgs(args())
-
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
-	}
+	rootCmd.AddCommand(newScheduleCommand(ctx, input))
+	rootCmd.AddCommand(newServeCommand(ctx, input))
+	rootCmd.AddCommand(newLintCommand(input))
+	rootCmd.AddCommand(newExprCommand(input))
+	return rootCmd
 }
 
 func configLocations() []string {
@@ -138,6 +176,10 @@ func setupLogging(cmd *cobra.Command, _ []string) {
 	if verbose {
 		log.SetLevel(log.DebugLevel)
 	}
+	jsonLogger, _ := cmd.Flags().GetBool("json")
+	if jsonLogger {
+		log.SetFormatter(&log.JSONFormatter{})
+	}
 }
 
 func readEnvs(path string, envs map[string]string) bool {
@@ -157,10 +199,6 @@ func readEnvs(path string, envs map[string]string) bool {
 //nolint:gocyclo
 func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
-		if input.jsonLogger {
-			log.SetFormatter(&log.JSONFormatter{})
-		}
-
 		if runtime.GOOS == "darwin" && runtime.GOARCH == "arm64" && input.containerArchitecture == "" {
 			l := log.New()
 			l.SetFormatter(&log.TextFormatter{
@@ -170,27 +208,18 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 			l.Warnf(" \U000026A0 You are using Apple M1 chip and you have not specified container architecture, you might encounter issues while running act. If so, try running it with '--container-architecture linux/amd64'. \U000026A0 \n")
 		}
 
-		log.Debugf("Loading environment from %s", input.Envfile())
-		envs := make(map[string]string)
-		if input.envs != nil {
-			for _, envVar := range input.envs {
-				e := strings.SplitN(envVar, `=`, 2)
-				if len(e) == 2 {
-					envs[e[0]] = e[1]
-				} else {
-					envs[e[0]] = ""
-				}
+		// listing or drawing the jobs doesn't run them, so the workflows are only checked before a run
+		list, _ := cmd.Flags().GetBool("list")
+		graph, _ := cmd.Flags().GetBool("graph")
+		if !input.noLint && !list && !graph && input.listFormat == "" && input.graphFormat == "" {
+			if err := checkWorkflows(input); err != nil {
+				return err
 			}
 		}
-		_ = readEnvs(input.Envfile(), envs)
-
-		log.Debugf("Loading secrets from %s", input.Secretfile())
-		secrets := newSecrets(input.secrets)
-		_ = readEnvs(input.Secretfile(), secrets)
 
 		planner, err := model.NewWorkflowPlanner(input.WorkflowsPath(), input.noWorkflowRecurse)
 		if err != nil {
-			return err
+			return workflowError(err)
 		}
 
 		// Determine the event name
@@ -224,6 +253,11 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 		// check if we should just list the workflows
 		if list, err := cmd.Flags().GetBool("list"); err != nil {
 			return err
+		} else if input.listFormat != "" {
+			config := newRunnerConfig(input)
+			config.EventName = eventName
+			config.EventPath = input.EventPath()
+			return printJobs(plan, input.listFormat, config)
 		} else if list {
 			return printList(plan)
 		}
@@ -231,62 +265,43 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 		// check if we should just print the graph
 		if list, err := cmd.Flags().GetBool("graph"); err != nil {
 			return err
-		} else if list {
-			return drawGraph(plan)
+		} else if list || input.graphFormat != "" {
+			return drawGraph(plan, input.graphFormat)
 		}
 
-		// check to see if the main branch was defined
-		defaultbranch, err := cmd.Flags().GetString("defaultbranch")
+		// run the plan
+		config := newRunnerConfig(input)
+		config.EventName = eventName
+		config.EventPath = input.EventPath()
+		closeEventStream, err := attachEventStream(input, config)
 		if err != nil {
 			return err
 		}
-
-		// Check if platforms flag is set, if not, run default image survey
-		if len(input.platforms) == 0 {
-			cfgFound := false
-			cfgLocations := configLocations()
-			for _, v := range cfgLocations {
-				_, err := os.Stat(v)
-				if os.IsExist(err) {
-					cfgFound = true
-				}
+		defer closeEventStream()
+		writeReports, err := attachReports(input, config)
+		if err != nil {
+			return err
+		}
+		if err := attachGroupedOutput(input, config); err != nil {
+			return err
+		}
+		writeRunState, err := attachRunState(input, config, plan)
+		if err != nil {
+			return err
+		}
+		if err := selectSteps(input, config, plan); err != nil {
+			return err
+		}
+		var ui *tui.UI
+		if input.tui {
+			if input.groupOutput != "" || input.eventsFile == "-" {
+				return fmt.Errorf("--tui can't be combined with --group-output or --events-file -")
 			}
-			if !cfgFound && len(cfgLocations) > 0 {
-				if err := defaultImageSurvey(cfgLocations[0]); err != nil {
-					log.Fatal(err)
-				}
-				input.platforms = readArgsFile(cfgLocations[0])
+			if input.pauseOnFailure || len(input.pauseBefore) > 0 || input.reviewDeployments {
+				return fmt.Errorf("--tui can't be combined with --pause-on-failure, --pause-before or --review-deployments")
 			}
-		}
-
-		// run the plan
-		config := &runner.Config{
-			Actor:                 input.actor,
-			EventName:             eventName,
-			EventPath:             input.EventPath(),
-			DefaultBranch:         defaultbranch,
-			ForcePull:             input.forcePull,
-			ForceRebuild:          input.forceRebuild,
-			ReuseContainers:       input.reuseContainers,
-			Workdir:               input.Workdir(),
-			BindWorkdir:           input.bindWorkdir,
-			LogOutput:             !input.noOutput,
-			JSONLogger:            input.jsonLogger,
-			Env:                   envs,
-			Secrets:               secrets,
-			InsecureSecrets:       input.insecureSecrets,
-			Platforms:             input.newPlatforms(),
-			Privileged:            input.privileged,
-			UsernsMode:            input.usernsMode,
-			ContainerArchitecture: input.containerArchitecture,
-			ContainerDaemonSocket: input.containerDaemonSocket,
-			UseGitIgnore:          input.useGitIgnore,
-			GitHubInstance:        input.githubInstance,
-			ContainerCapAdd:       input.containerCapAdd,
-			ContainerCapDrop:      input.containerCapDrop,
-			AutoRemove:            input.autoRemove,
-			ArtifactServerPath:    input.artifactServerPath,
-			ArtifactServerPort:    input.artifactServerPort,
+			ui = tui.New(os.Stdin, os.Stdout)
+			ui.Attach(config)
 		}
 		r, err := runner.New(config)
 		if err != nil {
@@ -299,17 +314,192 @@ func newRunCommand(ctx context.Context, input *Input) func(*cobra.Command, []str
 		if watch, err := cmd.Flags().GetBool("watch"); err != nil {
 			return err
 		} else if watch {
+			if ui != nil {
+				return fmt.Errorf("--tui can't be combined with --watch")
+			}
 			return watchAndRun(ctx, r.NewPlanExecutor(plan))
 		}
 
-		executor := r.NewPlanExecutor(plan).Finally(func(ctx context.Context) error {
+		executor := r.NewPlanExecutor(plan)
+		if !input.noChain {
+			executor = runner.NewChainedPlanExecutor(config, planner, plan, input.chainDepth)
+		}
+		executor = executor.Finally(writeReports).Finally(writeRunState).Finally(func(ctx context.Context) error {
 			cancel()
 			return nil
 		})
+		if ui != nil {
+			return ui.Run(ctx, r, executor)
+		}
 		return executor(ctx)
 	}
 }
 
+// selectSteps sets the steps to run for --step and --skip-step, every filter has to match a step of the plan
+func selectSteps(input *Input, config *runner.Config, plan *model.Plan) error {
+	for _, filter := range append(append([]string{}, input.steps...), input.skipSteps...) {
+		if !planHasStep(plan, filter) {
+			return fmt.Errorf("no job of the plan has a step with the id, name or index '%s'", filter)
+		}
+	}
+	config.Steps = input.steps
+	config.SkipSteps = input.skipSteps
+	config.RunAlwaysSteps = input.runAlwaysSteps
+	return nil
+}
+
+// attachEventStream writes the events of the runner to --events-file, the returned function closes the file
+func attachEventStream(input *Input, config *runner.Config) (func(), error) {
+	if input.eventsFile == "" {
+		return func() {}, nil
+	}
+
+	var w io.Writer
+	closeFile := func() {}
+	if input.eventsFile == "-" {
+		// keep stdout free of anything but events
+		w = os.Stdout
+		config.LogWriter = os.Stderr
+	} else {
+		f, err := os.Create(input.eventsFile)
+		if err != nil {
+			return nil, err
+		}
+		w = f
+		closeFile = func() {
+			if err := f.Close(); err != nil {
+				log.Errorf("Failed to close events file: %v", err)
+			}
+		}
+	}
+
+	addHooks(config, runner.NewEventStream(w, allSecrets(config)))
+	return closeFile, nil
+}
+
+// attachGroupedOutput buffers the logs of every job for --group-output and writes them in one block when the job or step completed
+func attachGroupedOutput(input *Input, config *runner.Config) error {
+	if input.groupOutput == "" {
+		return nil
+	}
+	if input.groupOutput != "job" && input.groupOutput != "step" {
+		return fmt.Errorf("invalid value '%s' for --group-output, expected 'job' or 'step'", input.groupOutput)
+	}
+
+	var out io.Writer = os.Stdout
+	if config.LogWriter != nil {
+		out = config.LogWriter
+	}
+	output := runner.NewGroupedOutput(out, input.groupOutput == "step")
+	config.LogWriter = output
+	addHooks(config, output)
+	return nil
+}
+
+// addHooks adds hooks to the hooks of the config
+func addHooks(config *runner.Config, hooks runner.Hooks) {
+	if config.Hooks != nil {
+		hooks = runner.MultiHooks(config.Hooks, hooks)
+	}
+	config.Hooks = hooks
+}
+
+// newRunnerConfig creates the runner config shared by all commands running workflows
+func newRunnerConfig(input *Input) *runner.Config {
+	// Check if platforms flag is set, if not, run default image survey
+	if len(input.platforms) == 0 {
+		cfgFound := false
+		cfgLocations := configLocations()
+		for _, v := range cfgLocations {
+			_, err := os.Stat(v)
+			if os.IsExist(err) {
+				cfgFound = true
+			}
+		}
+		if !cfgFound && len(cfgLocations) > 0 {
+			if err := defaultImageSurvey(cfgLocations[0]); err != nil {
+				log.Fatal(err)
+			}
+			input.platforms = readArgsFile(cfgLocations[0])
+		}
+	}
+
+	return loadRunnerConfig(input)
+}
+
+// loadRunnerConfig creates the config of the runner with the env and secrets of the input, without asking for the default image
+func loadRunnerConfig(input *Input) *runner.Config {
+	log.Debugf("Loading environment from %s", input.Envfile())
+	envs := make(map[string]string)
+	if input.envs != nil {
+		for _, envVar := range input.envs {
+			e := strings.SplitN(envVar, `=`, 2)
+			if len(e) == 2 {
+				envs[e[0]] = e[1]
+			} else {
+				envs[e[0]] = ""
+			}
+		}
+	}
+	_ = readEnvs(input.Envfile(), envs)
+
+	log.Debugf("Loading secrets from %s", input.Secretfile())
+	secrets := newSecrets(input.secrets)
+	_ = readEnvs(input.Secretfile(), secrets)
+	environments := environmentNames(input)
+	environmentSecrets := readEnvironmentFiles(input.Secretfile(), environments)
+
+	log.Debugf("Loading vars from %s", input.Varfile())
+	vars, environmentVars, err := newVars(input.vars, input.Varfile())
+	if err != nil {
+		log.Fatal(err)
+	}
+	for name, values := range readEnvironmentFiles(input.Varfile(), environments) {
+		if environmentVars[name] == nil {
+			environmentVars[name] = make(map[string]string)
+		}
+		for k, v := range values {
+			environmentVars[name][k] = v
+		}
+	}
+
+	return &runner.Config{
+		Actor:                 input.actor,
+		DefaultBranch:         input.defaultBranch,
+		ForcePull:             input.forcePull,
+		ForceRebuild:          input.forceRebuild,
+		ReuseContainers:       input.reuseContainers,
+		Workdir:               input.Workdir(),
+		BindWorkdir:           input.bindWorkdir,
+		LogOutput:             !input.noOutput,
+		JSONLogger:            input.jsonLogger,
+		LogDir:                input.logDir,
+		PauseOnFailure:        input.pauseOnFailure,
+		PauseBefore:           input.pauseBefore,
+		ReviewDeployments:     input.reviewDeployments,
+		Env:                   envs,
+		Secrets:               secrets,
+		Vars:                  vars,
+		EnvironmentVars:       environmentVars,
+		EnvironmentSecrets:    environmentSecrets,
+		InsecureSecrets:       input.insecureSecrets,
+		Platforms:             input.newPlatforms(),
+		Privileged:            input.privileged,
+		UsernsMode:            input.usernsMode,
+		ContainerArchitecture: input.containerArchitecture,
+		ContainerDaemonSocket: input.containerDaemonSocket,
+		UseGitIgnore:          input.useGitIgnore,
+		GitHubInstance:        input.githubInstance,
+		ContainerCapAdd:       input.containerCapAdd,
+		ContainerCapDrop:      input.containerCapDrop,
+		AutoRemove:            input.autoRemove,
+		ArtifactServerPath:    input.artifactServerPath,
+		ArtifactServerPort:    input.artifactServerPort,
+		TraceExpressions:      input.traceExpressions,
+		StrictExpressions:     input.strictExpressions,
+	}
+}
+
 func defaultImageSurvey(actrc string) error {
 	var answer string
 	confirmation := &survey.Select{
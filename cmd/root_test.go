@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootCommandEventArgument(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "nightly.yml"), []byte(`
+name: nightly
+on:
+  schedule:
+    - cron: '0 2 * * *'
+jobs:
+  nightly:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo nightly
+`), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "build.yml"), []byte(`
+name: build
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo build
+`), 0600))
+
+	rootCmd := newRootCommand(context.Background(), new(Input), "test")
+	cmd, _, err := rootCmd.Find([]string{"-W", dir, "-l", "schedule"})
+	assert.NoError(t, err)
+	assert.Same(t, rootCmd, cmd)
+	cmd, _, err = rootCmd.Find([]string{"cron", "--count", "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "cron", cmd.Name())
+
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	rootCmd.SetArgs([]string{"-W", dir, "-l", "schedule"})
+	err = rootCmd.Execute()
+	os.Stdout = stdout
+	assert.NoError(t, w.Close())
+	assert.NoError(t, err)
+
+	out, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), "nightly.yml")
+	assert.NotContains(t, string(out), "build.yml")
+}
+
+func TestRootCommandListSkipsLint(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "build.yml"), []byte(`
+name: build
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ unknown() }}
+`), 0600))
+
+	for _, args := range [][]string{{"-l"}, {"-g"}, {"--graph-format", "json"}} {
+		rootCmd := newRootCommand(context.Background(), new(Input), "test")
+		rootCmd.SetArgs(append([]string{"-W", dir}, args...))
+		rootCmd.SetOut(ioutil.Discard)
+		assert.NoError(t, rootCmd.Execute(), args)
+	}
+
+	rootCmd := newRootCommand(context.Background(), new(Input), "test")
+	rootCmd.SetArgs([]string{"-W", dir, "-n"})
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	assert.EqualError(t, rootCmd.Execute(), "found 1 errors in the workflows, run 'act lint' for all problems or pass --no-lint to run them anyway")
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+)
+
+// attachRunState records the state of the run and loads the previous one for --rerun-failed and --from-step,
+// the returned executor writes the state file. Without --state-file, --rerun-failed and --from-step no state is recorded.
+func attachRunState(input *Input, config *runner.Config, plan *model.Plan) (common.Executor, error) {
+	if input.stateFile == "" && !input.rerunFailed && input.fromStep == "" {
+		return func(ctx context.Context) error {
+			return nil
+		}, nil
+	}
+
+	path := input.stateFile
+	if path == "" {
+		path = runner.DefaultRunStatePath(input.Workdir())
+	}
+
+	if input.fromStep != "" {
+		if !input.reuseContainers {
+			return nil, fmt.Errorf("--from-step requires --reuse to keep the changes of the skipped steps in the job container")
+		}
+		if !planHasStep(plan, input.fromStep) {
+			return nil, fmt.Errorf("no job of the plan has a step with id '%s'", input.fromStep)
+		}
+	}
+
+	state := &runner.RunState{}
+	if input.rerunFailed || input.fromStep != "" {
+		previous, err := runner.ReadRunState(path)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no previous run to continue, %s does not exist", path)
+		} else if err != nil {
+			return nil, err
+		}
+		state = previous
+	}
+	config.RunState = state
+	config.RerunFailed = input.rerunFailed
+	config.FromStep = input.fromStep
+
+	return func(ctx context.Context) error {
+		if common.Dryrun(ctx) {
+			return nil
+		}
+		log.Debugf("Writing the state of the run to %s", path)
+		if err := state.Write(path); err != nil {
+			return fmt.Errorf("failed to write the state of the run: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+// planHasStep returns true if a job of the plan has a step with the id, name or index
+func planHasStep(plan *model.Plan, filter string) bool {
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			for i, step := range run.Job().Steps {
+				if runner.MatchStep(filter, i, step) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
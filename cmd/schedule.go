@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/nektos/act/pkg/artifacts"
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+)
+
+type scheduleInput struct {
+	count  int
+	at     string
+	window time.Duration
+}
+
+func newScheduleCommand(ctx context.Context, input *Input) *cobra.Command {
+	scheduleInput := new(scheduleInput)
+	cmd := &cobra.Command{
+		Use:   "cron",
+		Short: "List the upcoming times of scheduled workflows, or run the workflows that are due at a given time",
+		Long: "List the upcoming times of the cron expressions of the schedule event, or run the workflows that are due at a given time.\n\n" +
+			"It is called cron because 'act schedule' runs all workflows of the schedule event like any other event name, without looking at their cron expressions.",
+		Args: cobra.NoArgs,
+		RunE: newScheduleRunCommand(ctx, input, scheduleInput),
+	}
+	cmd.Flags().IntVar(&scheduleInput.count, "count", 5, "number of upcoming times to list for each schedule")
+	cmd.Flags().StringVar(&scheduleInput.at, "at", "", "run the workflows scheduled at this time in RFC 3339 format (e.g. 2022-03-14T10:00:00Z)")
+	cmd.Flags().DurationVar(&scheduleInput.window, "window", 0, "run the workflows scheduled within this duration before --at or now (e.g. 1h)")
+	return cmd
+}
+
+func newScheduleRunCommand(ctx context.Context, input *Input, scheduleInput *scheduleInput) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		planner, err := model.NewWorkflowPlanner(input.WorkflowsPath(), input.noWorkflowRecurse)
+		if err != nil {
+			return workflowError(err)
+		}
+
+		schedules, err := planner.GetSchedules()
+		if err != nil {
+			return err
+		}
+
+		// GitHub evaluates all schedules in UTC
+		now := time.Now().UTC()
+		if scheduleInput.at == "" && scheduleInput.window == 0 {
+			return printSchedules(schedules, now, scheduleInput.count)
+		}
+
+		end := now
+		if scheduleInput.at != "" {
+			if end, err = time.Parse(time.RFC3339, scheduleInput.at); err != nil {
+				return fmt.Errorf("invalid time '%s' for --at: %v", scheduleInput.at, err)
+			}
+			end = end.UTC()
+		}
+
+		config := newRunnerConfig(input)
+		closeEventStream, err := attachEventStream(input, config)
+		if err != nil {
+			return err
+		}
+		defer closeEventStream()
+		writeReports, err := attachReports(input, config)
+		if err != nil {
+			return err
+		}
+		if err := attachGroupedOutput(input, config); err != nil {
+			return err
+		}
+		cancel := artifacts.Serve(ctx, input.artifactServerPath, input.artifactServerPort)
+		defer cancel()
+		ctx = common.WithDryrun(ctx, input.dryrun)
+
+		var firstErr error
+		due := false
+		for _, schedule := range schedules {
+			firesAt, ok := lastFireTime(schedule.Cron, end.Add(-scheduleInput.window), end)
+			if !ok {
+				continue
+			}
+			due = true
+
+			log.Infof("Running workflow '%s' scheduled at %s by '%s'", schedule.Workflow.Name, firesAt.Format(time.RFC3339), schedule.Cron)
+			err := runSchedule(ctx, input, config, planner, schedule)
+			if firstErr == nil {
+				firstErr = err
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		if !due {
+			log.Infof("No scheduled workflows are due at %s", end.Format(time.RFC3339))
+		}
+		if err := writeReports(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return firstErr
+	}
+}
+
+// lastFireTime returns the last time within [start, end] the cron expression fires
+func lastFireTime(cron *common.Cron, start time.Time, end time.Time) (time.Time, bool) {
+	var last time.Time
+	found := false
+	for t := cron.Next(start.Add(-time.Nanosecond)); !t.IsZero() && !t.After(end); t = cron.Next(t) {
+		last = t
+		found = true
+	}
+	return last, found
+}
+
+func runSchedule(ctx context.Context, input *Input, config *runner.Config, planner model.WorkflowPlanner, schedule *model.Schedule) error {
+	eventJSON, err := json.Marshal(map[string]interface{}{
+		"schedule": schedule.Cron.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	scheduleConfig := *config
+	scheduleConfig.EventName = "schedule"
+	scheduleConfig.EventJSON = string(eventJSON)
+
+	plan := schedule.Plan()
+	if !input.noChain {
+		return runner.NewChainedPlanExecutor(&scheduleConfig, planner, plan, input.chainDepth)(ctx)
+	}
+	r, err := runner.New(&scheduleConfig)
+	if err != nil {
+		return err
+	}
+	return r.NewPlanExecutor(plan)(ctx)
+}
+
+func printSchedules(schedules []*model.Schedule, from time.Time, count int) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Workflow file\tWorkflow name\tCron\tNext")
+	for _, schedule := range schedules {
+		t := from
+		for i := 0; i < count; i++ {
+			if t = schedule.Cron.Next(t); t.IsZero() {
+				break
+			}
+			if i == 0 {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", schedule.Workflow.File, schedule.Workflow.Name, schedule.Cron, t.Format(time.RFC3339))
+			} else {
+				fmt.Fprintf(w, "\t\t\t%s\n", t.Format(time.RFC3339))
+			}
+		}
+	}
+	return w.Flush()
+}
@@ -7,6 +7,8 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/term"
+
+	"github.com/nektos/act/pkg/runner"
 )
 
 type secrets map[string]string
@@ -40,3 +42,18 @@ func newSecrets(secretList []string) secrets {
 func (s secrets) AsMap() map[string]string {
 	return s
 }
+
+// allSecrets returns the secrets of the repository and of all deployment environments for masking them in the
+// records of --events-file and --report
+func allSecrets(config *runner.Config) map[string]string {
+	all := make(map[string]string, len(config.Secrets))
+	for k, v := range config.Secrets {
+		all[k] = v
+	}
+	for name, environment := range config.EnvironmentSecrets {
+		for k, v := range environment {
+			all[name+"."+k] = v
+		}
+	}
+	return all
+}
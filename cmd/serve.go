@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nektos/act/pkg/artifacts"
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/server"
+)
+
+type serveInput struct {
+	addr        string
+	secret      string
+	token       string
+	concurrency int
+	queueSize   int
+}
+
+func newServeCommand(ctx context.Context, input *Input) *cobra.Command {
+	serveInput := new(serveInput)
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run workflows for GitHub webhook deliveries and serve an API to submit, stream and cancel runs",
+		Args:  cobra.NoArgs,
+		RunE:  newServeRunCommand(ctx, input, serveInput),
+	}
+	cmd.Flags().StringVar(&serveInput.addr, "addr", "localhost:8080", "address to listen on for webhook deliveries")
+	cmd.Flags().StringVar(&serveInput.secret, "webhook-secret", "", "secret to verify the X-Hub-Signature-256 header of deliveries with, defaults to $ACT_WEBHOOK_SECRET")
+	cmd.Flags().StringVar(&serveInput.token, "api-token", "", "bearer token required by the API, defaults to $ACT_API_TOKEN")
+	cmd.Flags().IntVar(&serveInput.concurrency, "concurrency", 1, "number of runs to execute at the same time")
+	cmd.Flags().IntVar(&serveInput.queueSize, "queue-size", 100, "number of queued runs before deliveries are rejected")
+	return cmd
+}
+
+func newServeRunCommand(ctx context.Context, input *Input, serveInput *serveInput) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		secret := serveInput.secret
+		if secret == "" {
+			secret = os.Getenv("ACT_WEBHOOK_SECRET")
+		}
+
+		token := serveInput.token
+		if token == "" {
+			token = os.Getenv("ACT_API_TOKEN")
+		}
+
+		chainDepth := input.chainDepth
+		if input.noChain {
+			chainDepth = 0
+		}
+
+		s := server.New(&server.Config{
+			Runner:            newRunnerConfig(input),
+			WorkflowsPath:     input.WorkflowsPath(),
+			NoWorkflowRecurse: input.noWorkflowRecurse,
+			Secret:            secret,
+			Token:             token,
+			Concurrency:       serveInput.concurrency,
+			QueueSize:         serveInput.queueSize,
+			ChainDepth:        chainDepth,
+		})
+
+		cancel := artifacts.Serve(ctx, input.artifactServerPath, input.artifactServerPort)
+		defer cancel()
+
+		return s.Serve(common.WithDryrun(ctx, input.dryrun), serveInput.addr)
+	}
+}
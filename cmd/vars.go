@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/model"
+)
+
+// newVars returns the configuration variables of the repository and of the deployment environments by their name,
+// from the file and --var. The variables of --var replace the ones in the file.
+func newVars(varList []string, path string) (map[string]string, map[string]map[string]string, error) {
+	vars, environmentVars, err := readVars(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error loading from %s: %v", path, err)
+	}
+	for _, v := range varList {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) == 2 {
+			vars[parts[0]] = parts[1]
+		} else {
+			vars[parts[0]] = ""
+		}
+	}
+	return vars, environmentVars, nil
+}
+
+// readVars reads a dotenv file, or a YAML or JSON file by its extension. In YAML and JSON an object instead of a value
+// has the variables of the deployment environment with its name, e.g. {"REGION": "eu", "production": {"REGION": "us"}}.
+// A missing file has no variables.
+func readVars(path string) (map[string]string, map[string]map[string]string, error) {
+	vars := make(map[string]string)
+	environmentVars := make(map[string]map[string]string)
+	if _, err := os.Stat(path); err != nil {
+		return vars, environmentVars, nil
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".yml" && ext != ".yaml" && ext != ".json" {
+		env, err := godotenv.Read(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		for k, v := range env {
+			vars[k] = v
+		}
+		return vars, environmentVars, nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var values map[string]interface{}
+	if ext == ".json" {
+		// numbers are kept as they are written instead of converting them to float64, e.g. 10000000 instead of 1e+07
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		err = decoder.Decode(&values)
+	} else {
+		err = yaml.Unmarshal(content, &values)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for name, value := range values {
+		if environment, ok := value.(map[string]interface{}); ok {
+			environmentVars[name] = make(map[string]string)
+			for k, v := range environment {
+				if environmentVars[name][k], err = varValue(v); err != nil {
+					return nil, nil, fmt.Errorf("variable %s of environment %s %v", k, name, err)
+				}
+			}
+			continue
+		}
+		if vars[name], err = varValue(value); err != nil {
+			return nil, nil, fmt.Errorf("variable %s %v", name, err)
+		}
+	}
+	return vars, environmentVars, nil
+}
+
+// varValue returns the value of a variable as a string like GitHub stores them, numbers and booleans are converted
+func varValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool, int, int64, uint64:
+		return fmt.Sprint(v), nil
+	}
+	return "", fmt.Errorf("must be a string, got %s", exprparser.TypeName(value))
+}
+
+// environmentNames returns the names of the deployment environments used by the jobs of the workflows and the ones
+// of --environment. The names of environments chosen by an expression are only known when the job runs.
+func environmentNames(input *Input) []string {
+	names := append([]string{}, input.environments...)
+	planner, err := model.NewWorkflowPlanner(input.WorkflowsPath(), input.noWorkflowRecurse)
+	if err != nil {
+		return names
+	}
+	for _, event := range planner.GetEvents() {
+		for _, stage := range planner.PlanEvent(event).Stages {
+			for _, run := range stage.Runs {
+				environment := run.Job().DeploymentEnvironment()
+				if environment == nil || environment.Name == "" {
+					continue
+				}
+				if strings.Contains(environment.Name, "${{") {
+					log.Debugf("Pass --environment to read the values of the environment %s of job %s", environment.Name, run.JobID)
+					continue
+				}
+				names = append(names, environment.Name)
+			}
+		}
+	}
+	return names
+}
+
+// readEnvironmentFiles reads the values of the deployment environments from the dotenv files next to path with the name
+// of the environment as extension, e.g. .secrets.production for the environment production of .secrets. Only the files
+// of the environments in names are read, files that can't be read are skipped.
+func readEnvironmentFiles(path string, names []string) map[string]map[string]string {
+	environments := make(map[string]map[string]string)
+	files, err := filepath.Glob(path + ".*")
+	if err != nil {
+		log.Warnf("Unable to find the files of the environments of %s: %v", path, err)
+		return environments
+	}
+	for _, file := range files {
+		name := strings.TrimPrefix(file, path+".")
+		if info, err := os.Stat(file); err != nil || info.IsDir() || !containsFold(names, name) {
+			continue
+		}
+		log.Debugf("Loading the values of environment %s from %s", name, file)
+		env, err := godotenv.Read(file)
+		if err != nil {
+			log.Warnf("Ignoring the values of environment %s in %s: %v", name, file, err)
+			continue
+		}
+		environments[name] = env
+	}
+	return environments
+}
+
+// containsFold returns true if the names contain name, compared case insensitively like the names of environments
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
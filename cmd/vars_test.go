@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadVarsNumbers(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"vars.json": `{"BUILD": 10000000, "RATIO": 0.5, "DEBUG": true, "production": {"BUILD": 20000000}}`,
+		"vars.yml":  "BUILD: 10000000\nRATIO: 0.5\nDEBUG: true\nproduction:\n  BUILD: 2.0e+7\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+
+		vars, environmentVars, err := readVars(path)
+		assert.NoError(t, err, name)
+		assert.Equal(t, map[string]string{"BUILD": "10000000", "RATIO": "0.5", "DEBUG": "true"}, vars, name)
+		assert.Equal(t, map[string]map[string]string{"production": {"BUILD": "20000000"}}, environmentVars, name)
+	}
+}
+
+func TestReadEnvironmentFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".secrets")
+	for name, content := range map[string]string{
+		".secrets.Production": "TOKEN=production-token\n",
+		".secrets.staging":    "TOKEN=staging-token\n",
+		".secrets.example":    "TOKEN=example\n",
+		".secrets.swp":        "\x00binary swap file",
+		".secrets.broken":     "\x00binary swap file",
+	} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	environments := readEnvironmentFiles(path, []string{"production", "broken", "missing"})
+	assert.Equal(t, map[string]map[string]string{
+		"Production": {"TOKEN": "production-token"},
+	}, environments)
+}
+
+func TestEnvironmentNames(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "deploy.yml"), []byte(`
+name: deploy
+on: push
+jobs:
+  production:
+    runs-on: ubuntu-latest
+    environment: production
+    steps:
+      - run: echo production
+  staging:
+    runs-on: ubuntu-latest
+    environment:
+      name: staging
+      url: https://staging.example.com
+    steps:
+      - run: echo staging
+  matrix:
+    strategy:
+      matrix:
+        target: [qa, preview]
+    runs-on: ubuntu-latest
+    environment: ${{ matrix.target }}
+    steps:
+      - run: echo ${{ matrix.target }}
+`), 0600))
+
+	input := &Input{workflowsPath: dir, environments: []string{"qa"}}
+	assert.ElementsMatch(t, []string{"qa", "production", "staging"}, environmentNames(input))
+}
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	tables := []struct {
+		spec   string
+		errMsg string
+	}{
+		{"*/15 * * * *", ""},
+		{"0 12 * JAN-MAR mon-fri", ""},
+		{"5,35 1-3 1 * 7", ""},
+		{"* * * *", "invalid cron expression '* * * *': expected 5 fields, got 4"},
+		{"60 * * * *", "invalid cron expression '60 * * * *': invalid value '60' in minute field, must be between 0 and 59"},
+		{"*/0 * * * *", "invalid cron expression '*/0 * * * *': invalid step '0' in minute field"},
+		{"5-1 * * * *", "invalid cron expression '5-1 * * * *': invalid range '5-1' in minute field"},
+	}
+	for _, table := range tables {
+		_, err := ParseCron(table.spec)
+		if table.errMsg == "" {
+			assert.NoError(t, err, table.spec)
+		} else {
+			assert.EqualError(t, err, table.errMsg)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	from := time.Date(2022, time.March, 14, 10, 7, 30, 0, time.UTC)
+	tables := []struct {
+		spec string
+		next time.Time
+	}{
+		{"*/15 * * * *", time.Date(2022, time.March, 14, 10, 15, 0, 0, time.UTC)},
+		{"0 12 * * *", time.Date(2022, time.March, 14, 12, 0, 0, 0, time.UTC)},
+		{"30 2 * * sun", time.Date(2022, time.March, 20, 2, 30, 0, 0, time.UTC)},
+		{"30 2 * * 7", time.Date(2022, time.March, 20, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2022, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		// day of month and day of week are or-ed if both are restricted
+		{"0 0 1 * tue", time.Date(2022, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+	for _, table := range tables {
+		cron, err := ParseCron(table.spec)
+		assert.NoError(t, err, table.spec)
+		assert.Equal(t, table.next, cron.Next(from), table.spec)
+	}
+}
+
+func TestCronMatches(t *testing.T) {
+	cron, err := ParseCron("0 9-17/2 * * 1-5")
+	assert.NoError(t, err)
+	assert.True(t, cron.Matches(time.Date(2022, time.March, 14, 11, 0, 45, 0, time.UTC)))
+	assert.False(t, cron.Matches(time.Date(2022, time.March, 14, 12, 0, 0, 0, time.UTC)))
+	assert.False(t, cron.Matches(time.Date(2022, time.March, 13, 11, 0, 0, 0, time.UTC)))
+}
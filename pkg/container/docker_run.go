@@ -63,6 +63,9 @@ type FileEntry struct {
 	Body string
 }
 
+// DefaultShell starts bash if the image has it and sh otherwise
+var DefaultShell = []string{"sh", "-c", "if command -v bash >/dev/null; then exec bash; else exec sh; fi"}
+
 // Container for managing docker run containers
 type Container interface {
 	Create(capAdd []string, capDrop []string) common.Executor
@@ -72,6 +75,7 @@ type Container interface {
 	Pull(forcePull bool) common.Executor
 	Start(attach bool) common.Executor
 	Exec(command []string, env map[string]string, user, workdir string) common.Executor
+	Shell(command []string, env map[string]string, user, workdir string, stdin io.Reader, stdout io.Writer) common.Executor
 	UpdateFromEnv(srcPath string, env *map[string]string) common.Executor
 	UpdateFromImageEnv(env *map[string]string) common.Executor
 	UpdateFromPath(env *map[string]string) common.Executor
@@ -161,7 +165,14 @@ func (cr *containerReference) CopyDir(destPath string, srcPath string, useGitIgn
 }
 
 func (cr *containerReference) GetContainerArchive(ctx context.Context, srcPath string) (io.ReadCloser, error) {
+	if cr.cli == nil || cr.id == "" {
+		return nil, fmt.Errorf("container %s is not running", cr.input.Name)
+	}
 	a, _, err := cr.cli.CopyFromContainer(ctx, cr.id, srcPath)
+	if client.IsErrNotFound(err) {
+		// callers can tell a missing path from a failed copy with errors.Is(err, os.ErrNotExist)
+		return nil, fmt.Errorf("%v: %w", err, os.ErrNotExist)
+	}
 	return a, err
 }
 
@@ -186,6 +197,16 @@ func (cr *containerReference) Exec(command []string, env map[string]string, user
 	).IfNot(common.Dryrun)
 }
 
+// Shell runs an interactive command with a terminal, stdin is put into raw mode if it is a terminal
+func (cr *containerReference) Shell(command []string, env map[string]string, user, workdir string, stdin io.Reader, stdout io.Writer) common.Executor {
+	return common.NewPipelineExecutor(
+		common.NewInfoExecutor("%sdocker exec -it cmd=[%s] user=%s workdir=%s", logPrefix, strings.Join(command, " "), user, workdir),
+		cr.connect(),
+		cr.find(),
+		cr.shell(command, env, user, workdir, stdin, stdout),
+	).IfNot(common.Dryrun)
+}
+
 func (cr *containerReference) Remove() common.Executor {
 	return common.NewPipelineExecutor(
 		cr.connect(),
@@ -557,6 +578,69 @@ func (cr *containerReference) exec(cmd []string, env map[string]string, user, wo
 	}
 }
 
+func (cr *containerReference) shell(cmd []string, env map[string]string, user, workdir string, stdin io.Reader, stdout io.Writer) common.Executor {
+	return func(ctx context.Context) error {
+		envList := make([]string, 0)
+		for k, v := range env {
+			envList = append(envList, fmt.Sprintf("%s=%s", k, v))
+		}
+		wd := cr.input.WorkingDir
+		if strings.HasPrefix(workdir, "/") {
+			wd = workdir
+		} else if workdir != "" {
+			wd = fmt.Sprintf("%s/%s", cr.input.WorkingDir, workdir)
+		}
+
+		idResp, err := cr.cli.ContainerExecCreate(ctx, cr.id, types.ExecConfig{
+			User:         user,
+			Cmd:          cmd,
+			WorkingDir:   wd,
+			Env:          envList,
+			Tty:          true,
+			AttachStdin:  true,
+			AttachStderr: true,
+			AttachStdout: true,
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		resp, err := cr.cli.ContainerExecAttach(ctx, idResp.ID, types.ExecStartCheck{
+			Tty: true,
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		defer resp.Close()
+
+		if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			state, err := term.MakeRaw(int(f.Fd()))
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			defer func() {
+				_ = term.Restore(int(f.Fd()), state)
+			}()
+		}
+		if f, ok := stdout.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			if width, height, err := term.GetSize(int(f.Fd())); err == nil {
+				err = cr.cli.ContainerExecResize(ctx, idResp.ID, types.ResizeOptions{Height: uint(height), Width: uint(width)})
+				if err != nil {
+					common.Logger(ctx).Debugf("Failed to resize terminal: %v", err)
+				}
+			}
+		}
+
+		go func() {
+			_, _ = io.Copy(resp.Conn, stdin)
+			_ = resp.CloseWrite()
+		}()
+		// the exit code of an interactive shell is the one of the last command, so it is not an error
+		_, err = io.Copy(stdout, resp.Reader)
+		return errors.WithStack(err)
+	}
+}
+
 // nolint: gocyclo
 func (cr *containerReference) copyDir(dstPath string, srcPath string, useGitIgnore bool) common.Executor {
 	return func(ctx context.Context) error {
@@ -0,0 +1,84 @@
+package exprparser
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rhysd/actionlint"
+)
+
+// maxCachedExpressions bounds the parse cache, it is cleared when it is full, e.g. after many runs of act --watch
+const maxCachedExpressions = 10000
+
+// parsedExpression is the AST of an expression, the interpreter never modifies it so it is shared by all evaluations
+type parsedExpression struct {
+	node                   actionlint.ExprNode
+	hasStatusCheckFunction bool // the expression calls success(), always(), cancelled() or failure()
+	callsAlways            bool // the expression calls always() outside of a negation
+	err                    error
+}
+
+var parseCache = struct {
+	sync.RWMutex
+	expressions map[string]*parsedExpression
+}{expressions: make(map[string]*parsedExpression)}
+
+// parse returns the parsed expression from the cache, it parses it only the first time
+func parse(input string) *parsedExpression {
+	parseCache.RLock()
+	parsed, ok := parseCache.expressions[input]
+	parseCache.RUnlock()
+	if ok {
+		return parsed
+	}
+
+	parsed = &parsedExpression{}
+	parser := actionlint.NewExprParser()
+	exprNode, err := parser.Parse(actionlint.NewExprLexer(input + "}}"))
+	if err != nil {
+		parsed.err = fmt.Errorf("Failed to parse: %s", err.Message)
+	} else {
+		parsed.node = exprNode
+		negations := 0
+		actionlint.VisitExprNode(exprNode, func(node, _ actionlint.ExprNode, entering bool) {
+			switch node := node.(type) {
+			case *actionlint.NotOpNode:
+				if entering {
+					negations++
+				} else {
+					negations--
+				}
+			case *actionlint.FuncCallNode:
+				if !entering {
+					return
+				}
+				switch strings.ToLower(node.Callee) {
+				case "always":
+					parsed.hasStatusCheckFunction = true
+					parsed.callsAlways = parsed.callsAlways || negations == 0
+				case "success", "cancelled", "failure":
+					parsed.hasStatusCheckFunction = true
+				}
+			}
+		})
+	}
+
+	parseCache.Lock()
+	if len(parseCache.expressions) >= maxCachedExpressions {
+		parseCache.expressions = make(map[string]*parsedExpression)
+	}
+	parseCache.expressions[input] = parsed
+	parseCache.Unlock()
+	return parsed
+}
+
+// CallsAlways returns true if the expression, e.g. the condition of a step, calls always() outside of a negation.
+// The expression can be wrapped in ${{ }}, an expression that can't be parsed doesn't call always().
+func CallsAlways(expression string) bool {
+	expression = strings.TrimSpace(expression)
+	if strings.HasPrefix(expression, "${{") && strings.HasSuffix(expression, "}}") {
+		expression = strings.TrimSuffix(strings.TrimPrefix(expression, "${{"), "}}")
+	}
+	return parse(expression).callsAlways
+}
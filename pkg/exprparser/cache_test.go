@@ -0,0 +1,88 @@
+package exprparser
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func clearParseCache() {
+	parseCache.Lock()
+	parseCache.expressions = make(map[string]*parsedExpression)
+	parseCache.Unlock()
+}
+
+func TestParseCache(t *testing.T) {
+	clearParseCache()
+
+	first := parse("github.event_name == 'push'")
+	assert.NoError(t, first.err)
+	assert.Same(t, first, parse("github.event_name == 'push'"))
+	assert.False(t, first.hasStatusCheckFunction)
+	assert.True(t, parse("always() && true").hasStatusCheckFunction)
+	assert.EqualError(t, parse("github.").err, parse("github.").err.Error())
+
+	env := &EvaluationEnvironment{Github: &model.GithubContext{EventName: "push"}}
+	for i := 0; i < 2; i++ {
+		output, err := NewInterpeter(env, Config{}).Evaluate("github.event_name == 'push'", false)
+		assert.NoError(t, err)
+		assert.Equal(t, true, output)
+	}
+
+	for i := 0; i < maxCachedExpressions; i++ {
+		parse(fmt.Sprintf("matrix.index == %d", i))
+	}
+	parseCache.RLock()
+	assert.LessOrEqual(t, len(parseCache.expressions), maxCachedExpressions)
+	parseCache.RUnlock()
+}
+
+func TestCallsAlways(t *testing.T) {
+	table := map[string]bool{
+		"always()":                       true,
+		"${{ always () }}":               true,
+		"  ${{ ALWAYS() && env.CI }}  ":  true,
+		"failure() || always()":          true,
+		"!always()":                      false,
+		"!(always() && env.CI)":          false,
+		"contains('always()', env.step)": false,
+		"success()":                      false,
+		"":                               false,
+		"always(":                        false,
+	}
+	for expression, callsAlways := range table {
+		assert.Equal(t, callsAlways, CallsAlways(expression), expression)
+	}
+}
+
+func BenchmarkEvaluate(b *testing.B) {
+	env := &EvaluationEnvironment{
+		Github: &model.GithubContext{EventName: "push", Ref: "refs/heads/main"},
+		Matrix: map[string]interface{}{"os": "ubuntu-latest", "node": 16},
+	}
+	expressions := []string{
+		"github.event_name == 'push' && startsWith(github.ref, 'refs/heads/')",
+		"format('{0}-node-{1}', matrix.os, matrix.node)",
+		"matrix.node >= 14 && matrix.os != 'windows-latest'",
+	}
+
+	b.Run("cached", func(b *testing.B) {
+		interpreter := NewInterpeter(env, Config{Context: "step"})
+		for i := 0; i < b.N; i++ {
+			for _, expression := range expressions {
+				_, _ = interpreter.Evaluate(expression, false)
+			}
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		interpreter := NewInterpeter(env, Config{Context: "step"})
+		for i := 0; i < b.N; i++ {
+			clearParseCache()
+			for _, expression := range expressions {
+				_, _ = interpreter.Evaluate(expression, false)
+			}
+		}
+	})
+}
@@ -1,13 +1,8 @@
 package exprparser
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io"
-	"os"
-	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -177,52 +172,6 @@ func (impl *interperterImpl) fromJSON(value reflect.Value) (interface{}, error)
 	return data, nil
 }
 
-func (impl *interperterImpl) hashFiles(paths ...reflect.Value) (string, error) {
-	var filepaths []string
-
-	for _, path := range paths {
-		if path.Kind() == reflect.String {
-			filepaths = append(filepaths, path.String())
-		} else {
-			return "", fmt.Errorf("Non-string path passed to hashFiles")
-		}
-	}
-
-	var files []string
-
-	for i := range filepaths {
-		newFiles, err := filepath.Glob(filepath.Join(impl.config.WorkingDir, filepaths[i]))
-		if err != nil {
-			return "", fmt.Errorf("Unable to glob.Glob: %v", err)
-		}
-
-		files = append(files, newFiles...)
-	}
-
-	if len(files) == 0 {
-		return "", nil
-	}
-
-	hasher := sha256.New()
-
-	for _, file := range files {
-		f, err := os.Open(file)
-		if err != nil {
-			return "", fmt.Errorf("Unable to os.Open: %v", err)
-		}
-
-		if _, err := io.Copy(hasher, f); err != nil {
-			return "", fmt.Errorf("Unable to io.Copy: %v", err)
-		}
-
-		if err := f.Close(); err != nil {
-			return "", fmt.Errorf("Unable to Close file: %v", err)
-		}
-	}
-
-	return hex.EncodeToString(hasher.Sum(nil)), nil
-}
-
 func (impl *interperterImpl) getNeedsTransitive(job *model.Job) []string {
 	needs := job.Needs()
 
@@ -186,8 +186,8 @@ func TestFunctionHashFiles(t *testing.T) {
 	}{
 		{"hashFiles('**/non-extant-files') }}", "", "hash-non-existing-file"},
 		{"hashFiles('**/non-extant-files', '**/more-non-extant-files') }}", "", "hash-multiple-non-existing-files"},
-		{"hashFiles('./for-hashing-1.txt') }}", "66a045b452102c59d840ec097d59d9467e13a3f34f6494e539ffd32c1bb35f18", "hash-single-file"},
-		{"hashFiles('./for-hashing-*') }}", "8e5935e7e13368cd9688fe8f48a0955293676a021562582c7e848dafe13fb046", "hash-multiple-files"},
+		{"hashFiles('./for-hashing-1.txt') }}", "31ff3fcb19566e855efbe0c4eb393d1a7807e08c4f1cf4f1a89e29d9d55968c5", "hash-single-file"},
+		{"hashFiles('./for-hashing-*') }}", "56c352d06ebcf622658fb248292304a432b204d29e11ba76c96dbb647d3b73ad", "hash-multiple-files"},
 	}
 
 	env := &EvaluationEnvironment{}
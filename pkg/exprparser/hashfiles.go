@@ -0,0 +1,250 @@
+package exprparser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// hashFiles returns the hash of the files of the workspace matching the patterns like the function of the GitHub runner:
+//   - a pattern is relative to the workspace, every argument can have several patterns on separate lines
+//   - '*', '?' and '[...]' match within a path segment, '**' matches any number of segments
+//   - a pattern matching a directory matches all files in it, a pattern ending with '/' only matches directories
+//   - a pattern starting with '!' excludes the files matched by the patterns before it
+//   - the files are hashed in the order the patterns are searched, the files of a directory in the order of their names
+//
+// The hash is the SHA-256 of the SHA-256 hashes of the files, it is empty if no file matches.
+func (impl *interperterImpl) hashFiles(paths ...reflect.Value) (string, error) {
+	var lines []string
+	for _, path := range paths {
+		if path.Kind() != reflect.String {
+			return "", fmt.Errorf("Non-string path passed to hashFiles")
+		}
+		lines = append(lines, strings.Split(path.String(), "\n")...)
+	}
+
+	var patterns []*globPattern
+	for _, line := range lines {
+		if pattern := impl.parseGlobPattern(line); pattern != nil {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	var hash string
+	hashWorkspace := func(workspace fs.FS) error {
+		var err error
+		hash, err = hashGlob(workspace, patterns)
+		return err
+	}
+	if impl.config.Workspace != nil {
+		searchPaths := globSearchPaths(patterns)
+		if len(searchPaths) == 0 {
+			return "", nil
+		}
+		err := impl.config.Workspace(searchPaths, hashWorkspace)
+		return hash, err
+	}
+	return hash, hashWorkspace(os.DirFS(impl.config.WorkingDir))
+}
+
+// globPattern is a pattern of hashFiles with its path relative to the workspace
+type globPattern struct {
+	segments []string
+	negate   bool
+	dirOnly  bool // the pattern ends with '/'
+}
+
+// parseGlobPattern returns nil for empty lines, comments and absolute patterns outside of the workspace
+func (impl *interperterImpl) parseGlobPattern(line string) *globPattern {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+
+	pattern := &globPattern{}
+	for strings.HasPrefix(line, "!") {
+		pattern.negate = !pattern.negate
+		line = strings.TrimSpace(line[1:])
+	}
+	pattern.dirOnly = strings.HasSuffix(line, "/")
+
+	if path.IsAbs(line) || filepath.IsAbs(line) {
+		rel, err := filepath.Rel(impl.config.WorkingDir, filepath.FromSlash(line))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+		line = filepath.ToSlash(rel)
+	}
+	line = path.Clean(line)
+	if line == "." {
+		pattern.segments = []string{}
+	} else if strings.HasPrefix(line, "../") || line == ".." {
+		return nil
+	} else {
+		pattern.segments = strings.Split(line, "/")
+	}
+	return pattern
+}
+
+// searchPath is the directory or file with the literal segments before the first one with a wildcard
+func (p *globPattern) searchPath() string {
+	literal := p.segments
+	for i, segment := range p.segments {
+		if strings.ContainsAny(segment, "*?[") {
+			literal = p.segments[:i]
+			break
+		}
+	}
+	if len(literal) == 0 {
+		return "."
+	}
+	return path.Join(literal...)
+}
+
+// match returns true if the pattern matches the file or one of its directories
+func (p *globPattern) match(file string) bool {
+	segments := strings.Split(file, "/")
+	for i := len(segments); i >= 0; i-- {
+		if p.dirOnly && i == len(segments) {
+			continue
+		}
+		if matchSegments(p.segments, segments[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSegments(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, err := path.Match(pattern[0], segments[0]); err != nil || !matched {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+// globSearchPaths returns the search paths of the patterns in their order without the ones in another search path
+func globSearchPaths(patterns []*globPattern) []string {
+	candidates := map[string]bool{}
+	for _, pattern := range patterns {
+		if !pattern.negate {
+			candidates[pattern.searchPath()] = true
+		}
+	}
+
+	var searchPaths []string
+	included := map[string]bool{}
+	for _, pattern := range patterns {
+		searchPath := pattern.searchPath()
+		if pattern.negate || included[searchPath] {
+			continue
+		}
+		hasAncestor := false
+		for child, dir := searchPath, path.Dir(searchPath); dir != child; child, dir = dir, path.Dir(dir) {
+			hasAncestor = hasAncestor || candidates[dir]
+		}
+		if !hasAncestor {
+			searchPaths = append(searchPaths, searchPath)
+			included[searchPath] = true
+		}
+	}
+	return searchPaths
+}
+
+func hashGlob(workspace fs.FS, patterns []*globPattern) (string, error) {
+	hasher := sha256.New()
+	matches := 0
+
+	var walk func(name string, parents []fs.FileInfo) error
+	walk = func(name string, parents []fs.FileInfo) error {
+		// stat follows symbolic links like the runner
+		info, err := fs.Stat(workspace, name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return fmt.Errorf("Unable to stat '%s': %v", name, err)
+		}
+
+		if !info.IsDir() {
+			matched := false
+			for _, pattern := range patterns {
+				if pattern.match(name) {
+					matched = !pattern.negate
+				}
+			}
+			if !matched {
+				return nil
+			}
+			fileHash, err := hashFile(workspace, name)
+			if err != nil {
+				return err
+			}
+			hasher.Write(fileHash)
+			matches++
+			return nil
+		}
+
+		for _, parent := range parents {
+			if os.SameFile(parent, info) {
+				// symbolic link cycle
+				return nil
+			}
+		}
+		entries, err := fs.ReadDir(workspace, name)
+		if err != nil {
+			return fmt.Errorf("Unable to read directory '%s': %v", name, err)
+		}
+		for _, entry := range entries {
+			if err := walk(path.Join(name, entry.Name()), append(parents, info)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, searchPath := range globSearchPaths(patterns) {
+		if err := walk(searchPath, nil); err != nil {
+			return "", err
+		}
+	}
+
+	if matches == 0 {
+		return "", nil
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func hashFile(workspace fs.FS, name string) ([]byte, error) {
+	f, err := workspace.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to open '%s': %v", name, err)
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return nil, fmt.Errorf("Unable to read '%s': %v", name, err)
+	}
+	return hasher.Sum(nil), nil
+}
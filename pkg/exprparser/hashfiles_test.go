@@ -0,0 +1,135 @@
+package exprparser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// openRecorder records the files opened in a workspace, stat and directory listings don't open them
+type openRecorder struct {
+	fstest.MapFS
+	opened []string
+}
+
+func (r *openRecorder) Open(name string) (fs.File, error) {
+	r.opened = append(r.opened, name)
+	return r.MapFS.Open(name)
+}
+
+// hashOf returns the hash of hashFiles for files with the contents in their order
+func hashOf(contents ...string) string {
+	hasher := sha256.New()
+	for _, content := range contents {
+		hash := sha256.Sum256([]byte(content))
+		hasher.Write(hash[:])
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func TestHashFilesPatterns(t *testing.T) {
+	workspace := fstest.MapFS{
+		"package-lock.json":           {Data: []byte("root")},
+		"a.txt":                       {Data: []byte("a")},
+		"b.txt":                       {Data: []byte("b")},
+		".github/cache.txt":           {Data: []byte("dot")},
+		"src/main.go":                 {Data: []byte("main")},
+		"src/lib/lib.go":              {Data: []byte("lib")},
+		"src/lib/lib_test.go":         {Data: []byte("lib test")},
+		"web/package-lock.json":       {Data: []byte("web")},
+		"web/node_modules/x/pkg.json": {Data: []byte("module")},
+	}
+	var searchPaths []string
+	config := Config{
+		WorkingDir: "/home/runner/work/act",
+		Workspace: func(paths []string, fn func(fs.FS) error) error {
+			searchPaths = paths
+			return fn(workspace)
+		},
+	}
+
+	table := []struct {
+		input    string
+		expected string
+	}{
+		{"hashFiles('a.txt')", hashOf("a")},
+		{"hashFiles('./a.txt')", hashOf("a")},
+		{"hashFiles('/home/runner/work/act/a.txt')", hashOf("a")},
+		{"hashFiles('/etc/passwd')", ""},
+		{"hashFiles('../a.txt')", ""},
+		{"hashFiles('missing')", ""},
+		// literal patterns are searched in their order
+		{"hashFiles('b.txt', 'a.txt')", hashOf("b", "a")},
+		{"hashFiles('*.txt')", hashOf("a", "b")},
+		{"hashFiles('a.txt\nb.txt\n# comment\n')", hashOf("a", "b")},
+		// the files of a directory are searched in the order of their names
+		{"hashFiles('**/package-lock.json')", hashOf("root", "web")},
+		{"hashFiles('**/package-lock.json', '!web/**')", hashOf("root")},
+		{"hashFiles('**/package-lock.json', '!**/package-lock.json', 'web/package-lock.json')", hashOf("web")},
+		{"hashFiles('src/**/*.go')", hashOf("lib", "lib test", "main")},
+		{"hashFiles('src/**/*.go', '!**/*_test.go')", hashOf("lib", "main")},
+		{"hashFiles('src/*.go')", hashOf("main")},
+		{"hashFiles('src/lib')", hashOf("lib", "lib test")},
+		{"hashFiles('src/lib/')", hashOf("lib", "lib test")},
+		{"hashFiles('a.txt/')", ""},
+		{"hashFiles('**/cache.txt')", hashOf("dot")},
+		{"hashFiles('web/**/*.json', '!!web/node_modules/**')", hashOf("module", "web")},
+		{"hashFiles('src/lib/lib?go', 'src/[m]ain.go')", hashOf("lib", "main")},
+	}
+
+	for _, tt := range table {
+		output, err := NewInterpeter(&EvaluationEnvironment{}, config).Evaluate(tt.input, false)
+		assert.NoError(t, err, tt.input)
+		assert.Equal(t, tt.expected, output, tt.input)
+	}
+
+	for input, expected := range map[string][]string{
+		"hashFiles('src/**/*.go', 'src/lib/lib.go')": {"src"},
+		"hashFiles('a.txt', 'web/*.json')":           {"a.txt", "web"},
+		"hashFiles('**/package-lock.json')":          {"."},
+	} {
+		_, err := NewInterpeter(&EvaluationEnvironment{}, config).Evaluate(input, false)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, searchPaths, input)
+	}
+}
+
+func TestGlobSearchPaths(t *testing.T) {
+	impl := &interperterImpl{}
+	var patterns []*globPattern
+	for _, line := range []string{"src/lib/*.go", "docs/a.md", "src/**", "!src/lib/**", "docs/a.md", "**/*.txt"} {
+		patterns = append(patterns, impl.parseGlobPattern(line))
+	}
+	assert.Equal(t, []string{"."}, globSearchPaths(patterns))
+	assert.Equal(t, []string{"docs/a.md", "src"}, globSearchPaths(patterns[:4]))
+}
+
+func TestHashFilesLeadingDoubleStar(t *testing.T) {
+	workspace := &openRecorder{MapFS: fstest.MapFS{
+		"package-lock.json":                  {Data: []byte("root")},
+		"package.json":                       {Data: []byte("package")},
+		"node_modules/a/index.js":            {Data: []byte("a")},
+		"node_modules/a/package.json":        {Data: []byte("a package")},
+		"node_modules/b/package-lock.json":   {Data: []byte("b")},
+		"dist/bundle.js":                     {Data: []byte("bundle")},
+		"web/package-lock.json":              {Data: []byte("web")},
+		"web/node_modules/c/lib/c.js":        {Data: []byte("c")},
+		"web/node_modules/c/package-lock.md": {Data: []byte("not json")},
+	}}
+	interpreter := NewInterpeter(&EvaluationEnvironment{}, Config{
+		WorkingDir: "/home/runner/work/act",
+		Workspace: func(paths []string, fn func(fs.FS) error) error {
+			assert.Equal(t, []string{"."}, paths)
+			return fn(workspace)
+		},
+	})
+
+	out, err := interpreter.Evaluate("hashFiles('**/package-lock.json')", false)
+	assert.NoError(t, err)
+	assert.Equal(t, hashOf("b", "root", "web"), out)
+	assert.Equal(t, []string{"node_modules/b/package-lock.json", "package-lock.json", "web/package-lock.json"}, workspace.opened)
+}
@@ -0,0 +1,119 @@
+package exprparser
+
+import (
+	"encoding"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/rhysd/actionlint"
+)
+
+// ContextNames are the names of the contexts available in expressions
+var ContextNames = []string{"github", "env", "job", "steps", "runner", "secrets", "strategy", "matrix", "needs", "inputs", "vars"}
+
+// TypeName returns the type of an evaluated value like GitHub names it, e.g. 'string', 'number' or 'object'
+func TypeName(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	if _, ok := value.(encoding.TextMarshaler); ok {
+		return "string"
+	}
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "null"
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return v.Kind().String()
+}
+
+// Complete returns the context paths starting with a partial path, e.g. 'github.event_name' for 'github.ev'.
+// The properties of objects are looked up in the environment and the contexts of the config,
+// they match case insensitively like in expressions.
+func Complete(env *EvaluationEnvironment, config Config, path string) []string {
+	parts := strings.Split(path, ".")
+	last := parts[len(parts)-1]
+
+	impl := &interperterImpl{env: env, config: config}
+	var names []string
+	if len(parts) == 1 {
+		names = impl.contextNames()
+	} else {
+		value, err := impl.evaluateVariable(&actionlint.VariableNode{Name: parts[0]})
+		for _, property := range parts[1 : len(parts)-1] {
+			if err != nil {
+				return nil
+			}
+			value, err = impl.getPropertyValue(reflect.ValueOf(value), property)
+		}
+		if err != nil {
+			return nil
+		}
+		names = propertyNames(reflect.ValueOf(value))
+	}
+
+	parent := strings.Join(parts[:len(parts)-1], ".")
+	completions := make([]string, 0)
+	for _, name := range names {
+		if !strings.HasPrefix(strings.ToLower(name), strings.ToLower(last)) {
+			continue
+		}
+		if parent != "" {
+			name = parent + "." + name
+		}
+		completions = append(completions, name)
+	}
+	return completions
+}
+
+// propertyNames returns the sorted names of the properties of an object, the json names of the fields of structs
+func propertyNames(v reflect.Value) []string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	names := make([]string, 0)
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			names = append(names, name)
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		for _, key := range v.MapKeys() {
+			names = append(names, key.String())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
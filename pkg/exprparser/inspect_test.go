@@ -0,0 +1,78 @@
+package exprparser
+
+import (
+	"testing"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeName(t *testing.T) {
+	table := []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, "null"},
+		{true, "boolean"},
+		{1, "number"},
+		{3.14, "number"},
+		{"text", "string"},
+		{model.StepStatusFailure, "string"},
+		{[]interface{}{"a"}, "array"},
+		{map[string]string{}, "object"},
+		{&model.GithubContext{}, "object"},
+		{(*model.JobContext)(nil), "null"},
+	}
+
+	for _, tt := range table {
+		assert.Equal(t, tt.expected, TypeName(tt.value), "%#v", tt.value)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	env := &EvaluationEnvironment{
+		Github: &model.GithubContext{
+			Event: map[string]interface{}{
+				"pull_request": map[string]interface{}{
+					"number": 1,
+					"head":   map[string]interface{}{"ref": "feature"},
+				},
+			},
+		},
+		Steps: map[string]*model.StepResult{
+			"build": {Outputs: map[string]string{"version": "1.0.0"}},
+		},
+		Matrix: map[string]interface{}{"os": "linux", "node": 16},
+		Vars:   map[string]string{"REGION": "eu"},
+	}
+	config := Config{
+		Contexts: map[string]interface{}{
+			"deploy": map[string]string{"TARGET": "staging"},
+		},
+	}
+
+	table := []struct {
+		path     string
+		expected []string
+	}{
+		{"", append(append([]string{}, ContextNames...), "deploy")},
+		{"s", []string{"steps", "secrets", "strategy"}},
+		{"github.ev", []string{"github.event", "github.event_name", "github.event_path"}},
+		{"github.event.pull_request.", []string{"github.event.pull_request.head", "github.event.pull_request.number"}},
+		{"GitHub.Event.Pull_Request.He", []string{"GitHub.Event.Pull_Request.head"}},
+		{"steps.build.", []string{"steps.build.conclusion", "steps.build.outcome", "steps.build.outputs"}},
+		{"steps.build.outputs.v", []string{"steps.build.outputs.version"}},
+		{"matrix.", []string{"matrix.node", "matrix.os"}},
+		{"matrix.os.", []string{}},
+		{"needs.", []string{}},
+		{"v", []string{"vars"}},
+		{"vars.", []string{"vars.REGION"}},
+		{"d", []string{"deploy"}},
+		{"deploy.", []string{"deploy.TARGET"}},
+		{"unknown.", nil},
+	}
+
+	for _, tt := range table {
+		assert.Equal(t, tt.expected, Complete(env, config, tt.path), tt.path)
+	}
+}
@@ -2,6 +2,7 @@ package exprparser
 
 import (
 	"fmt"
+	"io/fs"
 	"math"
 	"reflect"
 	"strings"
@@ -21,12 +22,25 @@ type EvaluationEnvironment struct {
 	Matrix   map[string]interface{}
 	Needs    map[string]map[string]map[string]string
 	Inputs   map[string]interface{}
+	Vars     map[string]string
 }
 
 type Config struct {
 	Run        *model.Run
 	WorkingDir string
 	Context    string
+	// Trace is called with the evaluation of the sub-expressions of every evaluated expression if it is set
+	Trace func(trace *Trace)
+	// Functions are additional functions by their name, they replace the built-in functions with the same name
+	Functions map[string]*Function
+	// Contexts are additional contexts by their name, they replace the built-in contexts with the same name
+	Contexts map[string]interface{}
+	// Workspace calls fn with the files of the workspace for hashFiles, e.g. the copy of the workspace in a container.
+	// Only the files in the search paths relative to the workspace are read, '.' is the whole workspace.
+	// hashFiles reads the files in WorkingDir if it is nil.
+	Workspace func(searchPaths []string, fn func(workspace fs.FS) error) error
+	// Strict makes dereferencing a property that does not exist on a context with a known shape an error, e.g. github.evnet
+	Strict bool
 }
 
 type Interpreter interface {
@@ -36,6 +50,7 @@ type Interpreter interface {
 type interperterImpl struct {
 	env    *EvaluationEnvironment
 	config Config
+	trace  *Trace // trace of the node being evaluated if Config.Trace is set
 }
 
 func NewInterpeter(env *EvaluationEnvironment, config Config) Interpreter {
@@ -50,33 +65,32 @@ func (impl *interperterImpl) Evaluate(input string, isIfExpression bool) (interf
 	if isIfExpression && input == "" {
 		input = "success()"
 	}
-	parser := actionlint.NewExprParser()
-	exprNode, err := parser.Parse(actionlint.NewExprLexer(input + "}}"))
-	if err != nil {
-		return nil, fmt.Errorf("Failed to parse: %s", err.Message)
+	parsed := parse(input)
+	if parsed.err != nil {
+		return nil, parsed.err
+	}
+	exprNode := parsed.node
+
+	if isIfExpression && !parsed.hasStatusCheckFunction {
+		exprNode = &actionlint.LogicalOpNode{
+			Kind: actionlint.LogicalOpNodeKindAnd,
+			Left: &actionlint.FuncCallNode{
+				Callee: "success",
+				Args:   []actionlint.ExprNode{},
+			},
+			Right: exprNode,
+		}
 	}
 
-	if isIfExpression {
-		hasStatusCheckFunction := false
-		actionlint.VisitExprNode(exprNode, func(node, _ actionlint.ExprNode, entering bool) {
-			if funcCallNode, ok := node.(*actionlint.FuncCallNode); entering && ok {
-				switch strings.ToLower(funcCallNode.Callee) {
-				case "success", "always", "cancelled", "failure":
-					hasStatusCheckFunction = true
-				}
-			}
-		})
-
-		if !hasStatusCheckFunction {
-			exprNode = &actionlint.LogicalOpNode{
-				Kind: actionlint.LogicalOpNodeKindAnd,
-				Left: &actionlint.FuncCallNode{
-					Callee: "success",
-					Args:   []actionlint.ExprNode{},
-				},
-				Right: exprNode,
+	if impl.config.Trace != nil {
+		root := &Trace{}
+		impl.trace = root
+		defer func() {
+			impl.trace = nil
+			if len(root.Children) > 0 {
+				impl.config.Trace(root.Children[0])
 			}
-		}
+		}()
 	}
 
 	result, err2 := impl.evaluateNode(exprNode)
@@ -85,6 +99,28 @@ func (impl *interperterImpl) Evaluate(input string, isIfExpression bool) (interf
 }
 
 func (impl *interperterImpl) evaluateNode(exprNode actionlint.ExprNode) (interface{}, error) {
+	if impl.trace == nil {
+		return impl.evaluateNodeValue(exprNode)
+	}
+
+	// literals are left out of the trace and context paths are traced as a whole
+	switch exprNode.(type) {
+	case *actionlint.NullNode, *actionlint.BoolNode, *actionlint.IntNode, *actionlint.FloatNode, *actionlint.StringNode:
+		return impl.evaluateNodeValue(exprNode)
+	}
+	parent := impl.trace
+	trace := &Trace{Expression: exprString(exprNode)}
+	parent.Children = append(parent.Children, trace)
+	impl.trace = trace
+	if isContextPath(exprNode) {
+		impl.trace = nil
+	}
+	trace.Value, trace.Err = impl.evaluateNodeValue(exprNode)
+	impl.trace = parent
+	return trace.Value, trace.Err
+}
+
+func (impl *interperterImpl) evaluateNodeValue(exprNode actionlint.ExprNode) (interface{}, error) {
 	switch node := exprNode.(type) {
 	case *actionlint.VariableNode:
 		return impl.evaluateVariable(node)
@@ -118,6 +154,10 @@ func (impl *interperterImpl) evaluateNode(exprNode actionlint.ExprNode) (interfa
 }
 
 func (impl *interperterImpl) evaluateVariable(variableNode *actionlint.VariableNode) (interface{}, error) {
+	if value, ok := impl.context(variableNode.Name); ok {
+		return value, nil
+	}
+
 	switch strings.ToLower(variableNode.Name) {
 	case "github":
 		return impl.env.Github, nil
@@ -139,6 +179,8 @@ func (impl *interperterImpl) evaluateVariable(variableNode *actionlint.VariableN
 		return impl.env.Needs, nil
 	case "inputs":
 		return impl.env.Inputs, nil
+	case "vars":
+		return impl.env.Vars, nil
 	case "infinity":
 		return math.Inf(1), nil
 	case "nan":
@@ -165,6 +207,9 @@ func (impl *interperterImpl) evaluateIndexAccess(indexAccessNode *actionlint.Ind
 
 	switch rightValue.Kind() {
 	case reflect.String:
+		if err := impl.checkProperty(indexAccessNode.Operand, leftValue, rightValue.String()); err != nil {
+			return nil, err
+		}
 		return impl.getPropertyValue(leftValue, rightValue.String())
 
 	case reflect.Int:
@@ -186,6 +231,10 @@ func (impl *interperterImpl) evaluateObjectDeref(objectDerefNode *actionlint.Obj
 		return nil, err
 	}
 
+	if err := impl.checkProperty(objectDerefNode.Receiver, reflect.ValueOf(left), objectDerefNode.Property); err != nil {
+		return nil, err
+	}
+
 	return impl.getPropertyValue(reflect.ValueOf(left), objectDerefNode.Property)
 }
 
@@ -274,7 +323,7 @@ func (impl *interperterImpl) evaluateNot(notNode *actionlint.NotOpNode) (interfa
 		return nil, err
 	}
 
-	return !IsTruthy(operand), nil
+	return !impl.isTruthy(operand), nil
 }
 
 func (impl *interperterImpl) evaluateCompare(compareNode *actionlint.CompareOpNode) (interface{}, error) {
@@ -298,9 +347,11 @@ func (impl *interperterImpl) compareValues(leftValue reflect.Value, rightValue r
 	if leftValue.Kind() != rightValue.Kind() {
 		if !impl.isNumber(leftValue) {
 			leftValue = impl.coerceToNumber(leftValue)
+			impl.traceCoercion("left side converted to number %s", traceValue(leftValue.Interface()))
 		}
 		if !impl.isNumber(rightValue) {
 			rightValue = impl.coerceToNumber(rightValue)
+			impl.traceCoercion("right side converted to number %s", traceValue(rightValue.Interface()))
 		}
 	}
 
@@ -347,8 +398,10 @@ func (impl *interperterImpl) coerceToNumber(value reflect.Value) reflect.Value {
 			return reflect.ValueOf(0)
 		}
 
-		// try to parse the string as a number
-		evaluated, err := impl.Evaluate(value.String(), false)
+		// try to parse the string as a number, without tracing it as an expression
+		parser := &interperterImpl{env: impl.env, config: impl.config}
+		parser.config.Trace = nil
+		evaluated, err := parser.Evaluate(value.String(), false)
 		if err != nil {
 			return reflect.ValueOf(math.NaN())
 		}
@@ -503,14 +556,14 @@ func (impl *interperterImpl) evaluateLogicalCompare(compareNode *actionlint.Logi
 
 	switch compareNode.Kind {
 	case actionlint.LogicalOpNodeKindAnd:
-		if IsTruthy(left) {
+		if impl.isTruthy(left) {
 			return impl.getSafeValue(rightValue), nil
 		}
 
 		return impl.getSafeValue(leftValue), nil
 
 	case actionlint.LogicalOpNodeKindOr:
-		if IsTruthy(left) {
+		if impl.isTruthy(left) {
 			return impl.getSafeValue(leftValue), nil
 		}
 
@@ -522,6 +575,15 @@ func (impl *interperterImpl) evaluateLogicalCompare(compareNode *actionlint.Logi
 
 // nolint:gocyclo
 func (impl *interperterImpl) evaluateFuncCall(funcCallNode *actionlint.FuncCallNode) (interface{}, error) {
+	if function, ok := impl.function(funcCallNode.Callee); ok {
+		return impl.evaluateFunction(funcCallNode, function)
+	}
+	if arity, ok := builtinArity[strings.ToLower(funcCallNode.Callee)]; ok {
+		if err := checkArity(funcCallNode.Callee, arity[0], arity[1], len(funcCallNode.Args)); err != nil {
+			return nil, err
+		}
+	}
+
 	args := make([]reflect.Value, 0)
 
 	for _, arg := range funcCallNode.Args {
@@ -577,3 +639,25 @@ func (impl *interperterImpl) evaluateFuncCall(funcCallNode *actionlint.FuncCallN
 		return nil, fmt.Errorf("TODO: '%s' not implemented", funcCallNode.Callee)
 	}
 }
+
+func (impl *interperterImpl) evaluateFunction(funcCallNode *actionlint.FuncCallNode, function *Function) (interface{}, error) {
+	if err := checkArity(funcCallNode.Callee, function.MinArgs, function.MaxArgs, len(funcCallNode.Args)); err != nil {
+		return nil, err
+	}
+
+	args := make([]interface{}, 0, len(funcCallNode.Args))
+	for _, arg := range funcCallNode.Args {
+		value, err := impl.evaluateNode(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, value)
+	}
+
+	if err := function.checkTypes(funcCallNode.Callee, args); err != nil {
+		return nil, err
+	}
+
+	return function.Call(args)
+}
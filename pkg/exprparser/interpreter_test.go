@@ -529,6 +529,8 @@ func TestContexts(t *testing.T) {
 		{"matrix.os", "Linux", "matrix-context"},
 		{"needs.job-id.outputs.output-name", "value", "needs-context"},
 		{"inputs.name", "value", "inputs-context"},
+		{"vars.name", "value", "vars-context"},
+		{"vars.NAME", "value", "vars-context-case-insensitive"},
 	}
 
 	env := &EvaluationEnvironment{
@@ -572,6 +574,9 @@ func TestContexts(t *testing.T) {
 		Inputs: map[string]interface{}{
 			"name": "value",
 		},
+		Vars: map[string]string{
+			"name": "value",
+		},
 	}
 
 	for _, tt := range table {
@@ -0,0 +1,98 @@
+package exprparser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Function is a function that can be called in expressions in addition to the built-in ones, see Config.Functions
+type Function struct {
+	MinArgs int
+	MaxArgs int // -1 for any number of arguments
+	// ArgTypes are the types of the arguments like TypeName returns them, the last one applies to the remaining arguments.
+	// 'any' or no types accept every value.
+	ArgTypes []string
+	Call     func(args []interface{}) (interface{}, error)
+}
+
+// builtinArity is the minimum and maximum number of arguments of the built-in functions, -1 for any number
+var builtinArity = map[string][2]int{
+	"contains":   {2, 2},
+	"startswith": {2, 2},
+	"endswith":   {2, 2},
+	"format":     {1, -1},
+	"join":       {1, 2},
+	"tojson":     {1, 1},
+	"fromjson":   {1, 1},
+	"hashfiles":  {1, -1},
+	"always":     {0, 0},
+	"success":    {0, 0},
+	"failure":    {0, 0},
+	"cancelled":  {0, 0},
+}
+
+// checkArity returns the error of GitHub for a call with too few or too many arguments
+func checkArity(name string, minArgs int, maxArgs int, args int) error {
+	if args < minArgs {
+		return fmt.Errorf("Too few parameters supplied: '%s'", name)
+	}
+	if maxArgs >= 0 && args > maxArgs {
+		return fmt.Errorf("Too many parameters supplied: '%s'", name)
+	}
+	return nil
+}
+
+// checkTypes returns an error if an argument is not of the type the function expects
+func (f *Function) checkTypes(name string, args []interface{}) error {
+	if len(f.ArgTypes) == 0 {
+		return nil
+	}
+	for i, arg := range args {
+		expected := f.ArgTypes[len(f.ArgTypes)-1]
+		if i < len(f.ArgTypes) {
+			expected = f.ArgTypes[i]
+		}
+		if actual := TypeName(arg); expected != "any" && expected != actual {
+			return fmt.Errorf("Parameter %d of '%s' must be a %s, got %s", i+1, name, expected, actual)
+		}
+	}
+	return nil
+}
+
+// function returns the function of Config.Functions with the name, names match case insensitively like built-in functions
+func (impl *interperterImpl) function(name string) (*Function, bool) {
+	for n, f := range impl.config.Functions {
+		if strings.EqualFold(n, name) {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// context returns the context of Config.Contexts with the name, names match case insensitively like built-in contexts
+func (impl *interperterImpl) context(name string) (interface{}, bool) {
+	for n, c := range impl.config.Contexts {
+		if strings.EqualFold(n, name) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// contextNames returns the names of the built-in contexts and the ones of Config.Contexts
+func (impl *interperterImpl) contextNames() []string {
+	names := append([]string{}, ContextNames...)
+	custom := make([]string, 0, len(impl.config.Contexts))
+	for name := range impl.config.Contexts {
+		builtin := false
+		for _, n := range ContextNames {
+			builtin = builtin || strings.EqualFold(n, name)
+		}
+		if !builtin {
+			custom = append(custom, name)
+		}
+	}
+	sort.Strings(custom)
+	return append(names, custom...)
+}
@@ -0,0 +1,142 @@
+package exprparser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomFunctions(t *testing.T) {
+	config := Config{
+		Context: "step",
+		Functions: map[string]*Function{
+			"toUpper": {
+				MinArgs:  1,
+				MaxArgs:  1,
+				ArgTypes: []string{"string"},
+				Call: func(args []interface{}) (interface{}, error) {
+					return strings.ToUpper(args[0].(string)), nil
+				},
+			},
+			"sum": {
+				MinArgs:  0,
+				MaxArgs:  -1,
+				ArgTypes: []string{"number"},
+				Call: func(args []interface{}) (interface{}, error) {
+					sum := 0.0
+					for _, arg := range args {
+						switch v := arg.(type) {
+						case int:
+							sum += float64(v)
+						case float64:
+							sum += v
+						}
+					}
+					return sum, nil
+				},
+			},
+			"fail": {
+				Call: func(args []interface{}) (interface{}, error) {
+					return nil, fmt.Errorf("failed on purpose")
+				},
+			},
+			// replaces the built-in function
+			"always": {
+				Call: func(args []interface{}) (interface{}, error) {
+					return false, nil
+				},
+			},
+		},
+	}
+
+	table := []struct {
+		input    string
+		expected interface{}
+		err      string
+	}{
+		{"toUpper('act')", "ACT", ""},
+		{"TOUPPER('act') == 'ACT'", true, ""},
+		{"toUpper(format('{0}', 'x'))", "X", ""},
+		{"sum()", 0.0, ""},
+		{"sum(1, 2.5, 3)", 6.5, ""},
+		{"always()", false, ""},
+		{"toUpper()", nil, "Too few parameters supplied: 'toUpper'"},
+		{"toUpper('a', 'b')", nil, "Too many parameters supplied: 'toUpper'"},
+		{"toUpper(1)", nil, "Parameter 1 of 'toUpper' must be a string, got number"},
+		{"sum(1, '2')", nil, "Parameter 2 of 'sum' must be a number, got string"},
+		{"fail()", nil, "failed on purpose"},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.input, func(t *testing.T) {
+			output, err := NewInterpeter(&EvaluationEnvironment{}, config).Evaluate(tt.input, false)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, output)
+		})
+	}
+}
+
+func TestBuiltinFunctionArity(t *testing.T) {
+	table := []struct {
+		input string
+		err   string
+	}{
+		{"contains('a')", "Too few parameters supplied: 'contains'"},
+		{"startsWith('a', 'b', 'c')", "Too many parameters supplied: 'startsWith'"},
+		{"format()", "Too few parameters supplied: 'format'"},
+		{"join('a', 'b', 'c')", "Too many parameters supplied: 'join'"},
+		{"toJSON()", "Too few parameters supplied: 'toJSON'"},
+		{"hashFiles()", "Too few parameters supplied: 'hashFiles'"},
+		{"success(1)", "Too many parameters supplied: 'success'"},
+		{"unknown()", "TODO: 'unknown' not implemented"},
+	}
+
+	for _, tt := range table {
+		_, err := NewInterpeter(&EvaluationEnvironment{}, Config{Context: "step"}).Evaluate(tt.input, false)
+		assert.EqualError(t, err, tt.err, tt.input)
+	}
+}
+
+func TestCustomContexts(t *testing.T) {
+	env := &EvaluationEnvironment{
+		Env: map[string]string{"KEY": "env"},
+	}
+	config := Config{
+		Contexts: map[string]interface{}{
+			"deploy": map[string]interface{}{
+				"REGION": "eu-west-1",
+				"flags":  map[string]interface{}{"beta": true},
+			},
+			// replaces the built-in context
+			"env": map[string]string{"KEY": "custom"},
+		},
+	}
+
+	table := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"deploy.REGION", "eu-west-1"},
+		{"DEPLOY.region", "eu-west-1"},
+		{"deploy['REGION']", "eu-west-1"},
+		{"deploy.flags.beta && 'on'", "on"},
+		{"deploy.missing", nil},
+		{"env.KEY", "custom"},
+	}
+
+	for _, tt := range table {
+		output, err := NewInterpeter(env, config).Evaluate(tt.input, false)
+		assert.NoError(t, err, tt.input)
+		assert.Equal(t, tt.expected, output, tt.input)
+	}
+
+	_, err := NewInterpeter(env, Config{}).Evaluate("deploy.REGION", false)
+	assert.EqualError(t, err, "Unavailable context: deploy")
+}
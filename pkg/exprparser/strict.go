@@ -0,0 +1,117 @@
+package exprparser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rhysd/actionlint"
+)
+
+// knownProperties are the properties GitHub defines on the contexts with a known shape by their path,
+// '*' stands for the id of a step or job. act doesn't set all of them.
+var knownProperties = map[string][]string{
+	"github": {
+		"action", "action_path", "action_ref", "action_repository", "action_status", "actor", "actor_id", "api_url",
+		"base_ref", "env", "event", "event_name", "event_path", "graphql_url", "head_ref", "job", "job_workflow_sha",
+		"path", "ref", "ref_name", "ref_protected", "ref_type", "repository", "repository_id", "repository_owner",
+		"repository_owner_id", "repositoryUrl", "retention_days", "run_attempt", "run_id", "run_number",
+		"secret_source", "server_url", "sha", "token", "triggering_actor", "workflow", "workflow_ref", "workflow_sha",
+		"workspace",
+	},
+	"runner":        {"arch", "debug", "environment", "name", "os", "temp", "tool_cache"},
+	"job":           {"container", "services", "status"},
+	"job.container": {"id", "network"},
+	"strategy":      {"fail-fast", "job-index", "job-total", "max-parallel"},
+	"steps.*":       {"conclusion", "outcome", "outputs"},
+	"needs.*":       {"outputs", "result"},
+}
+
+// checkProperty returns an error in strict mode if the property does not exist on a context with a known shape,
+// see knownProperties, or if steps or needs has no step or job with the id. The contents of github.event, outputs, env, matrix and the contexts of Config.Contexts can
+// have any property.
+func (impl *interperterImpl) checkProperty(receiver actionlint.ExprNode, left reflect.Value, property string) error {
+	if !impl.config.Strict {
+		return nil
+	}
+	path := contextPath(receiver)
+	if path == nil {
+		return nil
+	}
+	if _, ok := impl.context(path[0]); ok {
+		return nil
+	}
+
+	if len(path) == 1 && (strings.EqualFold(path[0], "steps") || strings.EqualFold(path[0], "needs")) {
+		if hasProperty(left, property) {
+			return nil
+		}
+		return fmt.Errorf("Property '%s' is not defined on '%s'", property, path[0])
+	}
+
+	key := strings.ToLower(strings.Join(path, "."))
+	if len(path) == 2 && (strings.EqualFold(path[0], "steps") || strings.EqualFold(path[0], "needs")) {
+		key = strings.ToLower(path[0]) + ".*"
+	}
+	known, ok := knownProperties[key]
+	if !ok || containsFold(known, property) || hasProperty(left, property) {
+		return nil
+	}
+	return fmt.Errorf("Property '%s' is not defined on '%s'", property, strings.Join(path, "."))
+}
+
+// contextPath returns the names of a context and its properties, nil if the node is not a property of a context
+func contextPath(node actionlint.ExprNode) []string {
+	switch n := node.(type) {
+	case *actionlint.VariableNode:
+		return []string{n.Name}
+	case *actionlint.ObjectDerefNode:
+		if path := contextPath(n.Receiver); path != nil {
+			return append(path, n.Property)
+		}
+	case *actionlint.IndexAccessNode:
+		if index, ok := n.Index.(*actionlint.StringNode); ok {
+			if path := contextPath(n.Operand); path != nil {
+				return append(path, index.Value)
+			}
+		}
+	}
+	return nil
+}
+
+func isStruct(v reflect.Value) bool {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	return v.Kind() == reflect.Struct
+}
+
+// hasProperty returns true if a struct has a field or a map has a key with the name of the property,
+// fields match by their json name or their name like in getPropertyValue
+func hasProperty(v reflect.Value, property string) bool {
+	if containsFold(propertyNames(v), property) {
+		return true
+	}
+	if !isStruct(v) {
+		return false
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	_, ok := v.Type().FieldByNameFunc(func(name string) bool {
+		return strings.EqualFold(name, property)
+	})
+	return ok
+}
+
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package exprparser
+
+import (
+	"testing"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictProperties(t *testing.T) {
+	env := &EvaluationEnvironment{
+		Github: &model.GithubContext{
+			EventName: "push",
+			Event:     map[string]interface{}{"ref": "refs/heads/main"},
+		},
+		Env:      map[string]string{"KEY": "value"},
+		Job:      &model.JobContext{Status: "success"},
+		Runner:   map[string]interface{}{"os": "Linux"},
+		Strategy: map[string]interface{}{"fail-fast": true},
+		Matrix:   map[string]interface{}{"os": "linux"},
+		Steps: map[string]*model.StepResult{
+			"build": {Outputs: map[string]string{"version": "1.0.0"}},
+		},
+		Needs: map[string]map[string]map[string]string{
+			"build": {"outputs": {"version": "1.0.0"}},
+		},
+	}
+	config := Config{
+		Context: "step",
+		Strict:  true,
+		Contexts: map[string]interface{}{
+			"deploy": map[string]string{"REGION": "eu"},
+		},
+	}
+
+	table := []struct {
+		input string
+		err   string
+	}{
+		{"github.event_name", ""},
+		{"github.eventName", ""},
+		{"github.server_url", ""},
+		{"github.event.missing", ""},
+		{"github['event']['anything']", ""},
+		{"runner.os", ""},
+		{"runner.arch", ""},
+		{"strategy.fail-fast", ""},
+		{"job.status", ""},
+		{"steps.build.outputs.anything", ""},
+		{"needs.build.result", ""},
+		{"needs.build.outputs.anything", ""},
+		{"env.MISSING", ""},
+		{"matrix.missing", ""},
+		{"vars.MISSING", ""},
+		{"deploy.MISSING", ""},
+		{"github.evnet", "Property 'evnet' is not defined on 'github'"},
+		{"github['evnet']", "Property 'evnet' is not defined on 'github'"},
+		{"runner.oss", "Property 'oss' is not defined on 'runner'"},
+		{"strategy.x", "Property 'x' is not defined on 'strategy'"},
+		{"job.stat", "Property 'stat' is not defined on 'job'"},
+		{"job.container.ports", "Property 'ports' is not defined on 'job.container'"},
+		{"steps.build.outpts", "Property 'outpts' is not defined on 'steps.build'"},
+		{"needs.build.foo", "Property 'foo' is not defined on 'needs.build'"},
+		{"steps.typo", "Property 'typo' is not defined on 'steps'"},
+		{"steps['typo']", "Property 'typo' is not defined on 'steps'"},
+		{"needs.nope", "Property 'nope' is not defined on 'needs'"},
+		{"needs.BUILD.result", ""},
+	}
+
+	for _, tt := range table {
+		_, err := NewInterpeter(env, config).Evaluate(tt.input, false)
+		if tt.err == "" {
+			assert.NoError(t, err, tt.input)
+		} else {
+			assert.EqualError(t, err, tt.err, tt.input)
+		}
+
+		_, err = NewInterpeter(env, Config{Context: "step", Contexts: config.Contexts}).Evaluate(tt.input, false)
+		assert.NoError(t, err, tt.input)
+	}
+}
+
+func TestStrictUnknownIDs(t *testing.T) {
+	env := &EvaluationEnvironment{
+		Steps: map[string]*model.StepResult{
+			"build": {Outputs: map[string]string{"version": "1.0.0"}},
+		},
+		Needs: map[string]map[string]map[string]string{
+			"build": {"outputs": {"version": "1.0.0"}},
+		},
+	}
+	config := Config{Context: "step", Strict: true}
+
+	_, err := NewInterpeter(env, config).Evaluate("steps.typo.outputs.version", false)
+	assert.EqualError(t, err, "Property 'typo' is not defined on 'steps'")
+	_, err = NewInterpeter(env, config).Evaluate("needs.nope.result == 'success'", false)
+	assert.EqualError(t, err, "Property 'nope' is not defined on 'needs'")
+	_, err = NewInterpeter(&EvaluationEnvironment{}, config).Evaluate("steps.build.outcome", false)
+	assert.EqualError(t, err, "Property 'build' is not defined on 'steps'")
+}
@@ -0,0 +1,191 @@
+package exprparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rhysd/actionlint"
+)
+
+// maxTraceValueLength is the length long values are shortened to in traces
+const maxTraceValueLength = 80
+
+// Trace is the evaluation of a node of an expression with the values of its sub-expressions, see Config.Trace
+type Trace struct {
+	Expression string
+	Value      interface{}
+	Err        error
+	Coercions  []string // implicit conversions of values, e.g. of strings to numbers to compare them
+	Children   []*Trace
+}
+
+// String returns the tree of the evaluated sub-expressions, one per line and indented below their parent
+func (t *Trace) String() string {
+	b := &strings.Builder{}
+	t.write(b, 0)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (t *Trace) write(b *strings.Builder, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(t.Expression)
+	if t.Err != nil {
+		fmt.Fprintf(b, " => error: %v", t.Err)
+	} else {
+		fmt.Fprintf(b, " => %s", traceValue(t.Value))
+	}
+	if len(t.Coercions) > 0 {
+		fmt.Fprintf(b, " (%s)", strings.Join(t.Coercions, ", "))
+	}
+	b.WriteString("\n")
+	for _, child := range t.Children {
+		child.write(b, depth+1)
+	}
+}
+
+// traceCoercion records an implicit conversion in the trace of the node being evaluated
+func (impl *interperterImpl) traceCoercion(format string, args ...interface{}) {
+	if impl.trace != nil {
+		impl.trace.Coercions = append(impl.trace.Coercions, fmt.Sprintf(format, args...))
+	}
+}
+
+// isTruthy returns the truthiness of a value, the falsy rules applied to values other than booleans are traced
+func (impl *interperterImpl) isTruthy(value interface{}) bool {
+	truthy := IsTruthy(value)
+	if _, ok := value.(bool); !ok && impl.trace != nil {
+		if truthy {
+			impl.traceCoercion("%s is truthy", traceValue(value))
+		} else {
+			impl.traceCoercion("%s is falsy", traceValue(value))
+		}
+	}
+	return truthy
+}
+
+// traceValue formats a value like a literal of an expression, objects and arrays as JSON
+func traceValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		// newlines are escaped to keep the trace one line per node
+		return "'" + shorten(strings.NewReplacer("'", "''", "\n", `\n`).Replace(v)) + "'"
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return "NaN"
+		case math.IsInf(v, 1):
+			return "Infinity"
+		case math.IsInf(v, -1):
+			return "-Infinity"
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return "null"
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return shorten(string(b))
+}
+
+// shorten cuts long values by runes, so that multi-byte characters stay intact
+func shorten(s string) string {
+	if utf8.RuneCountInString(s) > maxTraceValueLength {
+		return string([]rune(s)[:maxTraceValueLength-3]) + "..."
+	}
+	return s
+}
+
+var compareOperators = map[actionlint.CompareOpNodeKind]string{
+	actionlint.CompareOpNodeKindLess:      "<",
+	actionlint.CompareOpNodeKindLessEq:    "<=",
+	actionlint.CompareOpNodeKindGreater:   ">",
+	actionlint.CompareOpNodeKindGreaterEq: ">=",
+	actionlint.CompareOpNodeKindEq:        "==",
+	actionlint.CompareOpNodeKindNotEq:     "!=",
+}
+
+// exprString formats a node of an expression, operators are parenthesized inside other operators
+func exprString(node actionlint.ExprNode) string {
+	operand := func(node actionlint.ExprNode) string {
+		switch node.(type) {
+		case *actionlint.CompareOpNode, *actionlint.LogicalOpNode:
+			return "(" + exprString(node) + ")"
+		}
+		return exprString(node)
+	}
+
+	switch n := node.(type) {
+	case *actionlint.VariableNode:
+		return n.Name
+	case *actionlint.NullNode:
+		return "null"
+	case *actionlint.BoolNode, *actionlint.IntNode, *actionlint.FloatNode, *actionlint.StringNode:
+		return traceValue(literalValue(n))
+	case *actionlint.ObjectDerefNode:
+		return exprString(n.Receiver) + "." + n.Property
+	case *actionlint.ArrayDerefNode:
+		return exprString(n.Receiver) + ".*"
+	case *actionlint.IndexAccessNode:
+		return exprString(n.Operand) + "[" + exprString(n.Index) + "]"
+	case *actionlint.NotOpNode:
+		return "!" + operand(n.Operand)
+	case *actionlint.CompareOpNode:
+		return operand(n.Left) + " " + compareOperators[n.Kind] + " " + operand(n.Right)
+	case *actionlint.LogicalOpNode:
+		return operand(n.Left) + " " + n.Kind.String() + " " + operand(n.Right)
+	case *actionlint.FuncCallNode:
+		args := make([]string, 0, len(n.Args))
+		for _, arg := range n.Args {
+			args = append(args, exprString(arg))
+		}
+		return n.Callee + "(" + strings.Join(args, ", ") + ")"
+	}
+	return fmt.Sprintf("%T", node)
+}
+
+// isContextPath returns true for properties of contexts with literal indexes, e.g. github.event.inputs['name']
+func isContextPath(node actionlint.ExprNode) bool {
+	switch n := node.(type) {
+	case *actionlint.VariableNode:
+		return true
+	case *actionlint.ObjectDerefNode:
+		return isContextPath(n.Receiver)
+	case *actionlint.ArrayDerefNode:
+		return isContextPath(n.Receiver)
+	case *actionlint.IndexAccessNode:
+		switch n.Index.(type) {
+		case *actionlint.StringNode, *actionlint.IntNode:
+			return isContextPath(n.Operand)
+		}
+	}
+	return false
+}
+
+func literalValue(node actionlint.ExprNode) interface{} {
+	switch n := node.(type) {
+	case *actionlint.BoolNode:
+		return n.Value
+	case *actionlint.IntNode:
+		return n.Value
+	case *actionlint.FloatNode:
+		return n.Value
+	case *actionlint.StringNode:
+		return n.Value
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package exprparser
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace(t *testing.T) {
+	env := &EvaluationEnvironment{
+		Github: &model.GithubContext{
+			EventName: "pull_request",
+			Event: map[string]interface{}{
+				"number": 42.0,
+			},
+		},
+		Env: map[string]string{
+			"DEPLOY": "",
+		},
+		Job: &model.JobContext{
+			Status: "success",
+		},
+	}
+
+	table := []struct {
+		input    string
+		isIf     bool
+		expected string
+	}{
+		{"github.event_name == 'push'", false, `github.event_name == 'push' => false
+  github.event_name => 'pull_request'`},
+		{"github.event.number == '42'", false, `github.event.number == '42' => true (right side converted to number 42)
+  github.event.number => 42`},
+		{"env.DEPLOY || 'default'", false, `env.deploy || 'default' => 'default' ('' is falsy)
+  env.deploy => ''`},
+		{"!env.DEPLOY", true, `success() && !env.deploy => true
+  success() => true
+  !env.deploy => true ('' is falsy)
+    env.deploy => ''`},
+		{"fromJSON('[1]')[0] > 'a'", false, `fromJSON('[1]')[0] > 'a' => false (right side converted to number NaN)
+  fromJSON('[1]')[0] => 1
+    fromJSON('[1]') => [1]`},
+		{"toJSON(github.event) != '' && (contains(github.event_name, 'pull') || false)", false, `(toJSON(github.event) != '') && (contains(github.event_name, 'pull') || false) => true
+  toJSON(github.event) != '' => true
+    toJSON(github.event) => '{\n  "number": 42\n}'
+      github.event => {"number":42}
+  contains(github.event_name, 'pull') || false => true
+    contains(github.event_name, 'pull') => true
+      github.event_name => 'pull_request'`},
+		{"env.missing.property", false, `env.missing.property => error: Unable to dereference 'property' on non-struct 'invalid'`},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.input, func(t *testing.T) {
+			var traces []*Trace
+			interpreter := NewInterpeter(env, Config{
+				Context: "step",
+				Trace: func(trace *Trace) {
+					traces = append(traces, trace)
+				},
+			})
+			_, _ = interpreter.Evaluate(tt.input, tt.isIf)
+			assert.Len(t, traces, 1)
+			assert.Equal(t, tt.expected, traces[0].String())
+		})
+	}
+}
+
+func TestTraceDisabled(t *testing.T) {
+	impl := NewInterpeter(&EvaluationEnvironment{}, Config{}).(*interperterImpl)
+	out, err := impl.Evaluate("'1' == 1", false)
+	assert.NoError(t, err)
+	assert.Equal(t, true, out)
+	assert.Nil(t, impl.trace)
+}
+
+func TestTraceShortenRunes(t *testing.T) {
+	short := shorten(strings.Repeat("ä", maxTraceValueLength+1))
+	assert.True(t, utf8.ValidString(short))
+	assert.Equal(t, strings.Repeat("ä", maxTraceValueLength-3)+"...", short)
+	assert.Equal(t, strings.Repeat("ä", maxTraceValueLength), shorten(strings.Repeat("ä", maxTraceValueLength)))
+}
@@ -0,0 +1,283 @@
+// Package lint checks workflows and the local actions they use with actionlint, so invalid workflows
+// are reported with their position before any container is started
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rhysd/actionlint"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+const (
+	// KindJobID is the kind of errors for job ids the planner of act rejects
+	KindJobID = "job-id"
+	// KindLocalAction is the kind of errors in the metadata of local actions
+	KindLocalAction = "local-action"
+)
+
+// fatalKinds are the kinds of errors that make act fail to run a workflow, the other ones are only reported
+var fatalKinds = map[string]bool{
+	"yaml-syntax":   true,
+	"job-needs":     true,
+	KindJobID:       true,
+	KindLocalAction: true,
+}
+
+// fatalExpressionPattern matches the expression errors that make act fail, syntax errors and unknown contexts or functions,
+// the type checks of actionlint are stricter than the evaluation of act
+var fatalExpressionPattern = regexp.MustCompile(`while lexing|while parsing|parser did not reach|parsing invalid|^undefined (variable|function)`)
+
+// fatalSyntaxPattern matches the syntax errors that make act fail, unknown keys are rejected by the schema of act,
+// other checks of actionlint like empty `run` scripts are accepted by act
+var fatalSyntaxPattern = regexp.MustCompile(`^unexpected key`)
+
+// unknownContextPattern matches the errors of actionlint for the contexts it doesn't know yet, act and GitHub support them
+var unknownContextPattern = regexp.MustCompile(`(?i)^undefined variable "vars"`)
+
+var (
+	// jobIDPattern matches the job ids act accepts, unlike GitHub they can't start with a digit
+	jobIDPattern = regexp.MustCompile(`^([[:alpha:]_][[:alnum:]_\-]*)$`)
+	// githubJobIDPattern matches the job ids actionlint accepts, it reports the other ones itself
+	githubJobIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_-]*$`)
+)
+
+// Error is a problem in a workflow, the line and column are 1-based
+type Error struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s [%s]", e.File, e.Line, e.Column, e.Message, e.Kind)
+}
+
+// Fatal returns true if act would fail to run the workflow, e.g. for syntax errors, invalid expressions or needs cycles
+func (e *Error) Fatal() bool {
+	switch e.Kind {
+	case "expression":
+		return fatalExpressionPattern.MatchString(e.Message)
+	case "syntax-check":
+		return fatalSyntaxPattern.MatchString(e.Message)
+	}
+	return fatalKinds[e.Kind]
+}
+
+// WorkflowFiles returns the workflow files in the path like the planner reads them, the path can be a file or a directory.
+// YAML files in a directory without jobs are left out, e.g. the metadata of actions next to the workflows.
+func WorkflowFiles(path string, noWorkflowRecurse bool) ([]string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return []string{path}, nil
+	}
+
+	isWorkflow := func(name string) bool {
+		ext := filepath.Ext(name)
+		if ext != ".yml" && ext != ".yaml" {
+			return false
+		}
+		content, err := ioutil.ReadFile(name)
+		if err != nil {
+			return true
+		}
+		// files with syntax errors are kept to report them
+		var workflow map[string]interface{}
+		if err := yaml.Unmarshal(content, &workflow); err != nil {
+			return true
+		}
+		_, ok := workflow["jobs"]
+		return ok
+	}
+
+	files := make([]string, 0)
+	if noWorkflowRecurse {
+		infos, err := ioutil.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range infos {
+			if file := filepath.Join(path, info.Name()); !info.IsDir() && isWorkflow(file) {
+				files = append(files, file)
+			}
+		}
+		return files, nil
+	}
+
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && isWorkflow(p) {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// Lint checks the workflow files with actionlint and the rules of act, the local actions they use are resolved relative to workdir
+func Lint(workdir string, files []string) ([]*Error, error) {
+	linter, err := actionlint.NewLinter(ioutil.Discard, &actionlint.LinterOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]*Error, 0)
+	for _, file := range files {
+		found, err := linter.LintFile(file, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range found {
+			if e.Kind == "expression" && unknownContextPattern.MatchString(e.Message) {
+				continue
+			}
+			errs = append(errs, &Error{
+				File:    file,
+				Line:    e.Line,
+				Column:  e.Column,
+				Kind:    e.Kind,
+				Message: e.Message,
+			})
+		}
+	}
+
+	actions := make(map[string]*Error)
+	for _, file := range files {
+		found, err := lintWorkflow(workdir, file, actions)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, found...)
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].File != errs[j].File {
+			return errs[i].File < errs[j].File
+		}
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+	return errs, nil
+}
+
+// lintWorkflow checks the job ids of a workflow and the metadata of the local actions used by its steps,
+// the problems of every action are cached by its path
+func lintWorkflow(workdir string, file string, actions map[string]*Error) ([]*Error, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	// syntax errors are reported by actionlint
+	workflow, _ := actionlint.Parse(content)
+	if workflow == nil {
+		return nil, nil
+	}
+
+	errs := make([]*Error, 0)
+	for _, job := range workflow.Jobs {
+		if job == nil {
+			continue
+		}
+		if job.ID != nil && githubJobIDPattern.MatchString(job.ID.Value) && !jobIDPattern.MatchString(job.ID.Value) {
+			errs = append(errs, &Error{
+				File:    file,
+				Line:    job.ID.Pos.Line,
+				Column:  job.ID.Pos.Col,
+				Kind:    KindJobID,
+				Message: fmt.Sprintf("invalid job ID %q. act requires job IDs to start with a letter or _", job.ID.Value),
+			})
+		}
+		for _, step := range job.Steps {
+			exec, ok := step.Exec.(*actionlint.ExecAction)
+			if !ok || exec.Uses == nil || !strings.HasPrefix(exec.Uses.Value, "./") {
+				continue
+			}
+			problem, ok := actions[exec.Uses.Value]
+			if !ok {
+				problem = lintLocalAction(filepath.Join(workdir, filepath.FromSlash(exec.Uses.Value)), exec.Args != nil)
+				actions[exec.Uses.Value] = problem
+			}
+			if problem == nil {
+				continue
+			}
+			errs = append(errs, &Error{
+				File:    file,
+				Line:    exec.Uses.Pos.Line,
+				Column:  exec.Uses.Pos.Col,
+				Kind:    problem.Kind,
+				Message: fmt.Sprintf("local action %q %s", exec.Uses.Value, problem.Message),
+			})
+		}
+	}
+	return errs, nil
+}
+
+// lintLocalAction returns the problem of the metadata of a local action without its position, nil if it is valid.
+// Like the runner it accepts a Dockerfile without metadata, or no metadata at all if the step passes args.
+// A missing action is not fatal, it can be checked out by an earlier step.
+func lintLocalAction(dir string, hasArgs bool) *Error {
+	invalid := func(format string, args ...interface{}) *Error {
+		return &Error{Kind: KindLocalAction, Message: "is invalid: " + fmt.Sprintf(format, args...)}
+	}
+
+	var content []byte
+	var name string
+	for _, n := range []string{"action.yml", "action.yaml"} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, n))
+		if err == nil {
+			content, name = b, n
+			break
+		}
+	}
+	if content == nil {
+		if _, err := os.Stat(filepath.Join(dir, "Dockerfile")); err == nil || hasArgs {
+			return nil
+		}
+		return &Error{Kind: "action", Message: "has no action.yml, action.yaml or Dockerfile"}
+	}
+
+	action, err := model.ReadAction(bytes.NewReader(content))
+	if err != nil {
+		return invalid("%s: %s", name, strings.ReplaceAll(err.Error(), "\n", " "))
+	}
+	switch action.Runs.Using {
+	case model.ActionRunsUsingNode12, model.ActionRunsUsingNode16:
+		if action.Runs.Main == "" {
+			return invalid("%s: runs.main is required for %s actions", name, action.Runs.Using)
+		}
+	case model.ActionRunsUsingDocker:
+		if action.Runs.Image == "" {
+			return invalid("%s: runs.image is required for docker actions", name)
+		}
+	case model.ActionRunsUsingComposite:
+		if len(action.Runs.Steps) == 0 {
+			return invalid("%s: runs.steps is required for composite actions", name)
+		}
+		for i, step := range action.Runs.Steps {
+			if step.Run != "" && step.Shell == "" {
+				return invalid("%s: runs.steps[%d].shell is required for run steps of composite actions", name, i)
+			}
+		}
+	default:
+		return invalid("%s: runs.using is required", name)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package lint
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	errs, err := Lint("testdata", []string{"testdata/workflows/valid.yml"})
+	assert.NoError(t, err)
+	assert.Empty(t, errs)
+
+	file := "testdata/workflows/invalid.yml"
+	errs, err = Lint("testdata", []string{file})
+	assert.NoError(t, err)
+
+	type position struct {
+		line   int
+		column int
+		kind   string
+		fatal  bool
+	}
+	positions := make([]position, 0)
+	for _, e := range errs {
+		assert.Equal(t, file, e.File)
+		if e.Kind == "job-needs" {
+			// the cycle is reported at either job
+			assert.Contains(t, []int{4, 12}, e.Line)
+			assert.Contains(t, e.Message, "cyclic dependencies")
+			continue
+		}
+		positions = append(positions, position{e.Line, e.Column, e.Kind, e.Fatal()})
+	}
+	assert.Equal(t, []position{
+		{8, 23, "expression", true},     // unknown context
+		{9, 15, KindLocalAction, true},  // docker action without image
+		{10, 15, KindLocalAction, true}, // composite run step without shell
+		{11, 15, "action", false},       // missing action
+		{16, 33, "expression", true},    // unterminated expression
+		{17, 3, KindJobID, true},        // job id starting with a digit
+		{21, 23, "expression", false},   // untrusted input
+	}, positions)
+}
+
+func TestLocalAction(t *testing.T) {
+	dir := filepath.Join("testdata", "actions")
+	assert.Nil(t, lintLocalAction(filepath.Join(dir, "node"), false))
+	assert.Nil(t, lintLocalAction(filepath.Join(dir, "dockerfile"), false))
+	assert.Nil(t, lintLocalAction(filepath.Join(dir, "missing"), true))
+	assert.Equal(t, &Error{
+		Kind:    KindLocalAction,
+		Message: "is invalid: action.yml: runs.image is required for docker actions",
+	}, lintLocalAction(filepath.Join(dir, "docker-no-image"), false))
+}
+
+func TestErrorString(t *testing.T) {
+	e := &Error{File: "ci.yml", Line: 3, Column: 5, Kind: "expression", Message: "undefined variable"}
+	assert.Equal(t, "ci.yml:3:5: undefined variable [expression]", e.Error())
+}
+
+func TestErrorFatal(t *testing.T) {
+	assert.True(t, (&Error{Kind: "syntax-check", Message: `unexpected key "step" for "job" section`}).Fatal())
+	assert.True(t, (&Error{Kind: "expression", Message: "unexpected EOF while lexing expression"}).Fatal())
+	assert.True(t, (&Error{Kind: "expression", Message: `undefined function "foo". available functions are "always"`}).Fatal())
+	assert.False(t, (&Error{Kind: "expression", Message: `property "key2" is not defined in object type {key1: string}`}).Fatal())
+	assert.False(t, (&Error{Kind: "runner-label", Message: `label "gpu" is unknown`}).Fatal())
+	assert.True(t, (&Error{Kind: "yaml-syntax", Message: "could not parse as YAML: yaml: line 3: did not find expected key"}).Fatal())
+}
+
+func TestLintSyntaxCheck(t *testing.T) {
+	errs, err := Lint("testdata", []string{filepath.Join("..", "runner", "testdata", "shells", "custom", "push.yml")})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, errs)
+	for _, e := range errs {
+		assert.Equal(t, "syntax-check", e.Kind)
+		assert.False(t, e.Fatal(), e.Message)
+	}
+}
+
+func TestWorkflowFiles(t *testing.T) {
+	files, err := WorkflowFiles("testdata", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join("testdata", "workflows", "invalid.yml"),
+		filepath.Join("testdata", "workflows", "valid.yml"),
+	}, files)
+
+	files, err = WorkflowFiles(filepath.Join("testdata", "workflows"), true)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	files, err = WorkflowFiles(filepath.Join("testdata", "workflows", "valid.yml"), true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("testdata", "workflows", "valid.yml")}, files)
+
+	_, err = WorkflowFiles(filepath.Join("testdata", "missing"), false)
+	assert.Error(t, err)
+}
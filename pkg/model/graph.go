@@ -0,0 +1,169 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Graph is the dependency graph of the jobs of a plan, the edges point from a job to the jobs that need it
+type Graph struct {
+	Nodes []*GraphNode `json:"nodes"`
+	Edges []*GraphEdge `json:"edges"`
+}
+
+// GraphNode is a job of the plan
+type GraphNode struct {
+	ID         string   `json:"id"` // workflow file and job id, unique in the graph
+	Workflow   string   `json:"workflow"`
+	File       string   `json:"file"`
+	JobID      string   `json:"job_id"`
+	Name       string   `json:"name"`
+	RunsOn     []string `json:"runs_on"`
+	MatrixSize int      `json:"matrix_size"`
+	Stage      int      `json:"stage"`
+}
+
+// GraphEdge is a dependency between two jobs of the same workflow
+type GraphEdge struct {
+	From string `json:"from"` // id of the job that is needed
+	To   string `json:"to"`   // id of the job that needs it
+}
+
+// NewGraph creates the graph of the jobs of the plan, needed jobs that are not part of the plan are left out
+func NewGraph(plan *Plan) *Graph {
+	graph := &Graph{
+		Nodes: make([]*GraphNode, 0),
+		Edges: make([]*GraphEdge, 0),
+	}
+	nodes := make(map[string]*GraphNode)
+	for i, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			job := run.Job()
+			node := &GraphNode{
+				ID:         graphNodeID(run.Workflow, run.JobID),
+				Workflow:   run.Workflow.Name,
+				File:       run.Workflow.File,
+				JobID:      run.JobID,
+				Name:       run.String(),
+				RunsOn:     job.RunsOn(),
+				MatrixSize: len(job.GetMatrixes()),
+				Stage:      i,
+			}
+			if node.RunsOn == nil {
+				node.RunsOn = make([]string, 0)
+			}
+			nodes[node.ID] = node
+			graph.Nodes = append(graph.Nodes, node)
+		}
+	}
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			for _, needs := range run.Job().Needs() {
+				if from, ok := nodes[graphNodeID(run.Workflow, needs)]; ok {
+					graph.Edges = append(graph.Edges, &GraphEdge{From: from.ID, To: graphNodeID(run.Workflow, run.JobID)})
+				}
+			}
+		}
+	}
+	return graph
+}
+
+// graphNodeID identifies a job by the file of its workflow, the names of workflows don't have to be unique
+func graphNodeID(workflow *Workflow, jobID string) string {
+	if workflow.File == "" {
+		return fmt.Sprintf("%s/%s", workflow.Name, jobID)
+	}
+	return fmt.Sprintf("%s/%s", workflow.File, jobID)
+}
+
+// workflows returns the nodes grouped by their workflow, in the order the workflows appear
+func (g *Graph) workflows() [][]*GraphNode {
+	workflows := make([][]*GraphNode, 0)
+	index := make(map[string]int)
+	for _, node := range g.Nodes {
+		key := strings.TrimSuffix(node.ID, "/"+node.JobID)
+		i, ok := index[key]
+		if !ok {
+			i = len(workflows)
+			index[key] = i
+			workflows = append(workflows, nil)
+		}
+		workflows[i] = append(workflows[i], node)
+	}
+	return workflows
+}
+
+// label returns the name of the job with its runners and the size of its matrix
+func (n *GraphNode) label() []string {
+	lines := []string{n.Name}
+	if len(n.RunsOn) > 0 {
+		lines = append(lines, fmt.Sprintf("runs-on: %s", strings.Join(n.RunsOn, ", ")))
+	}
+	if n.MatrixSize > 1 {
+		lines = append(lines, fmt.Sprintf("matrix: %d jobs", n.MatrixSize))
+	}
+	return lines
+}
+
+// WriteJSON writes the graph as JSON
+func (g *Graph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
+
+// WriteDOT writes the graph in the DOT language of Graphviz, the jobs of every workflow are grouped in a cluster
+func (g *Graph) WriteDOT(w io.Writer) error {
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+	}
+
+	b := &strings.Builder{}
+	b.WriteString("digraph act {\n  rankdir=LR;\n  node [shape=box];\n")
+	for i, nodes := range g.workflows() {
+		fmt.Fprintf(b, "  subgraph cluster_%d {\n    label=%s;\n", i, quote(nodes[0].Workflow))
+		for _, node := range nodes {
+			fmt.Fprintf(b, "    %s [label=%s];\n", quote(node.ID), quote(strings.Join(node.label(), "\n")))
+		}
+		b.WriteString("  }\n")
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(b, "  %s -> %s;\n", quote(edge.From), quote(edge.To))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart, the jobs of every workflow are grouped in a subgraph
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	// the ids of mermaid nodes can't contain most characters, so the nodes are numbered
+	ids := make(map[string]string)
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("job%d", i)
+	}
+	escape := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace
+
+	b := &strings.Builder{}
+	b.WriteString("flowchart LR\n")
+	for i, nodes := range g.workflows() {
+		fmt.Fprintf(b, "  subgraph workflow%d[\"%s\"]\n", i, escape(nodes[0].Workflow))
+		for _, node := range nodes {
+			label := node.label()
+			for i := range label {
+				label[i] = escape(label[i])
+			}
+			fmt.Fprintf(b, "    %s[\"%s\"]\n", ids[node.ID], strings.Join(label, "<br/>"))
+		}
+		b.WriteString("  end\n")
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(b, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
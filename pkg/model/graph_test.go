@@ -0,0 +1,85 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestGraph(t *testing.T) *Graph {
+	planner, err := NewWorkflowPlanner("testdata/graph", true)
+	assert.NoError(t, err)
+	return NewGraph(planner.PlanEvent("push"))
+}
+
+func TestGraph(t *testing.T) {
+	graph := newTestGraph(t)
+
+	assert.Equal(t, []*GraphNode{
+		{ID: "ci.yml/build", Workflow: "CI", File: "ci.yml", JobID: "build", Name: "build", RunsOn: []string{"ubuntu-latest"}, MatrixSize: 1, Stage: 0},
+		{ID: "ci.yml/test", Workflow: "CI", File: "ci.yml", JobID: "test", Name: `Test "unit"`, RunsOn: []string{"self-hosted", "linux"}, MatrixSize: 2, Stage: 1},
+		{ID: "ci.yml/release", Workflow: "CI", File: "ci.yml", JobID: "release", Name: "release", RunsOn: []string{"ubuntu-latest"}, MatrixSize: 1, Stage: 2},
+	}, graph.Nodes)
+	assert.Equal(t, []*GraphEdge{
+		{From: "ci.yml/build", To: "ci.yml/test"},
+		{From: "ci.yml/build", To: "ci.yml/release"},
+		{From: "ci.yml/test", To: "ci.yml/release"},
+	}, graph.Edges)
+}
+
+func TestGraphJobWithoutNeededJob(t *testing.T) {
+	planner, err := NewWorkflowPlanner("testdata/graph", true)
+	assert.NoError(t, err)
+	plan := planner.PlanEvent("push")
+	plan.Stages = plan.Stages[1:]
+
+	graph := NewGraph(plan)
+	assert.Len(t, graph.Nodes, 2)
+	assert.Equal(t, []*GraphEdge{{From: "ci.yml/test", To: "ci.yml/release"}}, graph.Edges)
+}
+
+func TestGraphWriteDOT(t *testing.T) {
+	out := &bytes.Buffer{}
+	assert.NoError(t, newTestGraph(t).WriteDOT(out))
+	assert.Equal(t, `digraph act {
+  rankdir=LR;
+  node [shape=box];
+  subgraph cluster_0 {
+    label="CI";
+    "ci.yml/build" [label="build\nruns-on: ubuntu-latest"];
+    "ci.yml/test" [label="Test \"unit\"\nruns-on: self-hosted, linux\nmatrix: 2 jobs"];
+    "ci.yml/release" [label="release\nruns-on: ubuntu-latest"];
+  }
+  "ci.yml/build" -> "ci.yml/test";
+  "ci.yml/build" -> "ci.yml/release";
+  "ci.yml/test" -> "ci.yml/release";
+}
+`, out.String())
+}
+
+func TestGraphWriteMermaid(t *testing.T) {
+	out := &bytes.Buffer{}
+	assert.NoError(t, newTestGraph(t).WriteMermaid(out))
+	assert.Equal(t, `flowchart LR
+  subgraph workflow0["CI"]
+    job0["build<br/>runs-on: ubuntu-latest"]
+    job1["Test #quot;unit#quot;<br/>runs-on: self-hosted, linux<br/>matrix: 2 jobs"]
+    job2["release<br/>runs-on: ubuntu-latest"]
+  end
+  job0 --> job1
+  job0 --> job2
+  job1 --> job2
+`, out.String())
+}
+
+func TestGraphWriteJSON(t *testing.T) {
+	out := &bytes.Buffer{}
+	assert.NoError(t, newTestGraph(t).WriteJSON(out))
+
+	graph := &Graph{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), graph))
+	assert.Equal(t, newTestGraph(t), graph)
+	assert.Contains(t, out.String(), `"matrix_size": 2`)
+}
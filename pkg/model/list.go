@@ -0,0 +1,50 @@
+package model
+
+// ListedJob describes a planned job for tools driving act, see ListJobs
+type ListedJob struct {
+	WorkflowFile string                   `json:"workflow_file" yaml:"workflow_file"`
+	WorkflowName string                   `json:"workflow_name" yaml:"workflow_name"`
+	JobID        string                   `json:"job_id" yaml:"job_id"`
+	JobName      string                   `json:"job_name" yaml:"job_name"`
+	Stage        int                      `json:"stage" yaml:"stage"`
+	Events       []string                 `json:"events" yaml:"events"`
+	RunsOn       []string                 `json:"runs_on" yaml:"runs_on"`
+	Image        string                   `json:"image" yaml:"image"` // image of the job container, empty if no platform matches
+	Matrix       []map[string]interface{} `json:"matrix" yaml:"matrix"`
+	Needs        []string                 `json:"needs" yaml:"needs"`
+	Skipped      bool                     `json:"skipped" yaml:"skipped"` // the job would be skipped because no image is set for its platform
+}
+
+// ListJobs describes the jobs of the plan, image returns the image of the job container of a run
+func ListJobs(plan *Plan, image func(run *Run) string) []*ListedJob {
+	jobs := make([]*ListedJob, 0)
+	for i, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			job := run.Job()
+			listed := &ListedJob{
+				WorkflowFile: run.Workflow.File,
+				WorkflowName: run.Workflow.Name,
+				JobID:        run.JobID,
+				JobName:      run.String(),
+				Stage:        i,
+				Events:       run.Workflow.On(),
+				RunsOn:       job.RunsOn(),
+				Image:        image(run),
+				Matrix:       job.GetMatrixes(),
+				Needs:        job.Needs(),
+			}
+			listed.Skipped = listed.Image == ""
+			if listed.Events == nil {
+				listed.Events = make([]string, 0)
+			}
+			if listed.RunsOn == nil {
+				listed.RunsOn = make([]string, 0)
+			}
+			if listed.Needs == nil {
+				listed.Needs = make([]string, 0)
+			}
+			jobs = append(jobs, listed)
+		}
+	}
+	return jobs
+}
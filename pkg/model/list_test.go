@@ -0,0 +1,57 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListJobs(t *testing.T) {
+	planner, err := NewWorkflowPlanner("testdata/graph", true)
+	assert.NoError(t, err)
+
+	jobs := ListJobs(planner.PlanEvent("push"), func(run *Run) string {
+		if run.Job().RunsOn()[0] == "ubuntu-latest" {
+			return "node:16-buster-slim"
+		}
+		return ""
+	})
+	assert.Equal(t, []*ListedJob{
+		{
+			WorkflowFile: "ci.yml",
+			WorkflowName: "CI",
+			JobID:        "build",
+			JobName:      "build",
+			Stage:        0,
+			Events:       []string{"push"},
+			RunsOn:       []string{"ubuntu-latest"},
+			Image:        "node:16-buster-slim",
+			Matrix:       []map[string]interface{}{{}},
+			Needs:        []string{},
+		},
+		{
+			WorkflowFile: "ci.yml",
+			WorkflowName: "CI",
+			JobID:        "test",
+			JobName:      `Test "unit"`,
+			Stage:        1,
+			Events:       []string{"push"},
+			RunsOn:       []string{"self-hosted", "linux"},
+			Matrix:       []map[string]interface{}{{"go": 1.16}, {"go": 1.17}},
+			Needs:        []string{"build"},
+			Skipped:      true,
+		},
+		{
+			WorkflowFile: "ci.yml",
+			WorkflowName: "CI",
+			JobID:        "release",
+			JobName:      "release",
+			Stage:        2,
+			Events:       []string{"push"},
+			RunsOn:       []string{"ubuntu-latest"},
+			Image:        "node:16-buster-slim",
+			Matrix:       []map[string]interface{}{{}},
+			Needs:        []string{"build", "test"},
+		},
+	}, jobs)
+}
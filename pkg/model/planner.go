@@ -12,13 +12,17 @@ import (
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+
+	"github.com/nektos/act/pkg/common"
 )
 
 // WorkflowPlanner contains methods for creating plans
 type WorkflowPlanner interface {
 	PlanEvent(eventName string) *Plan
 	PlanJob(jobName string) *Plan
+	PlanWorkflowRun(workflowName string, activityType string, branch string) *Plan
 	GetEvents() []string
+	GetSchedules() ([]*Schedule, error)
 }
 
 // Plan contains a list of stages to run in series
@@ -31,6 +35,19 @@ type Stage struct {
 	Runs []*Run
 }
 
+// Schedule is a cron expression of the `schedule` event of a workflow
+type Schedule struct {
+	Workflow *Workflow
+	Cron     *common.Cron
+}
+
+// Plan builds a new list of runs for the scheduled workflow
+func (s *Schedule) Plan() *Plan {
+	plan := new(Plan)
+	plan.mergeStages(createStages(s.Workflow, s.Workflow.GetJobIDs()...))
+	return plan
+}
+
 // Run represents a job from a workflow that needs to be run
 type Run struct {
 	Workflow *Workflow
@@ -134,7 +151,7 @@ func NewWorkflowPlanner(path string, noWorkflowRecurse bool) (WorkflowPlanner, e
 				if err == io.EOF {
 					return nil, errors.WithMessagef(err, "unable to read workflow, %s file is empty", wf.workflowFileInfo.Name())
 				}
-				return nil, err
+				return nil, WithFile(err, f.Name())
 			}
 			_, err = f.Seek(0, 0)
 			if err != nil {
@@ -148,9 +165,14 @@ func NewWorkflowPlanner(path string, noWorkflowRecurse bool) (WorkflowPlanner, e
 			}
 
 			jobNameRegex := regexp.MustCompile(`^([[:alpha:]_][[:alnum:]_\-]*)$`)
-			for k := range workflow.Jobs {
+			for k, job := range workflow.Jobs {
 				if ok := jobNameRegex.MatchString(k); !ok {
-					return nil, fmt.Errorf("workflow is not valid. '%s': Job name '%s' is invalid. Names must start with a letter or '_' and contain only alphanumeric characters, '-', or '_'", workflow.Name, k)
+					return nil, &Error{
+						File:    f.Name(),
+						Line:    job.Pos.Line,
+						Column:  job.Pos.Column,
+						Message: fmt.Sprintf("workflow is not valid. '%s': Job name '%s' is invalid. Names must start with a letter or '_' and contain only alphanumeric characters, '-', or '_'", workflow.Name, k),
+					}
 				}
 			}
 
@@ -196,6 +218,18 @@ func (wp *workflowPlanner) PlanJob(jobName string) *Plan {
 	return plan
 }
 
+// PlanWorkflowRun builds a new list of runs for the workflows triggered by an activity of a run of another workflow
+func (wp *workflowPlanner) PlanWorkflowRun(workflowName string, activityType string, branch string) *Plan {
+	plan := new(Plan)
+	for _, w := range wp.workflows {
+		if trigger := w.WorkflowRun(); trigger != nil && trigger.Matches(workflowName, activityType, branch) {
+			log.Debugf("Workflow '%s' is triggered by workflow_run of '%s'", w.Name, workflowName)
+			plan.mergeStages(createStages(w, w.GetJobIDs()...))
+		}
+	}
+	return plan
+}
+
 // GetEvents gets all the events in the workflows file
 func (wp *workflowPlanner) GetEvents() []string {
 	events := make([]string, 0)
@@ -226,6 +260,24 @@ func (wp *workflowPlanner) GetEvents() []string {
 	return events
 }
 
+// GetSchedules gets all the cron schedules in the workflows file
+func (wp *workflowPlanner) GetSchedules() ([]*Schedule, error) {
+	schedules := make([]*Schedule, 0)
+	for _, w := range wp.workflows {
+		for _, spec := range w.Schedules() {
+			cron, err := common.ParseCron(spec)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "workflow is not valid. '%s'", w.Name)
+			}
+			schedules = append(schedules, &Schedule{
+				Workflow: w,
+				Cron:     cron,
+			})
+		}
+	}
+	return schedules, nil
+}
+
 // MaxRunNameLen determines the max name length of all jobs
 func (p *Plan) MaxRunNameLen() int {
 	maxRunNameLen := 0
@@ -2,6 +2,7 @@ package model
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 
 	log "github.com/sirupsen/logrus"
@@ -18,8 +19,8 @@ func TestPlanner(t *testing.T) {
 	log.SetLevel(log.DebugLevel)
 
 	tables := []WorkflowPlanTest{
-		{"invalid-job-name/invalid-1.yml", "workflow is not valid. 'invalid-job-name-1': Job name 'invalid-JOB-Name-v1.2.3-docker_hub' is invalid. Names must start with a letter or '_' and contain only alphanumeric characters, '-', or '_'", false},
-		{"invalid-job-name/invalid-2.yml", "workflow is not valid. 'invalid-job-name-2': Job name '1234invalid-JOB-Name-v123-docker_hub' is invalid. Names must start with a letter or '_' and contain only alphanumeric characters, '-', or '_'", false},
+		{"invalid-job-name/invalid-1.yml", "invalid-1.yml:5:3: workflow is not valid. 'invalid-job-name-1': Job name 'invalid-JOB-Name-v1.2.3-docker_hub' is invalid. Names must start with a letter or '_' and contain only alphanumeric characters, '-', or '_'", false},
+		{"invalid-job-name/invalid-2.yml", "invalid-2.yml:5:3: workflow is not valid. 'invalid-job-name-2': Job name '1234invalid-JOB-Name-v123-docker_hub' is invalid. Names must start with a letter or '_' and contain only alphanumeric characters, '-', or '_'", false},
 		{"invalid-job-name/valid-1.yml", "", false},
 		{"invalid-job-name/valid-2.yml", "", false},
 		{"empty-workflow", "unable to read workflow, push.yml file is empty: EOF", false},
@@ -34,8 +35,44 @@ func TestPlanner(t *testing.T) {
 		_, err = NewWorkflowPlanner(fullWorkflowPath, table.noWorkflowRecurse)
 		if table.errorMessage == "" {
 			assert.NoError(t, err, "WorkflowPlanner should exit without any error")
-		} else {
-			assert.EqualError(t, err, table.errorMessage)
+		} else if assert.Error(t, err) {
+			// errors with a position start with the path of the workflow
+			assert.Equal(t, table.errorMessage, strings.TrimPrefix(err.Error(), filepath.Dir(fullWorkflowPath)+string(filepath.Separator)))
 		}
 	}
 }
+
+func TestPlanWorkflowRun(t *testing.T) {
+	planner, err := NewWorkflowPlanner("testdata/workflow-run", true)
+	assert.NoError(t, err)
+
+	plan := planner.PlanWorkflowRun("build", "completed", "main")
+	assert.Len(t, plan.Stages, 1)
+	assert.Len(t, plan.Stages[0].Runs, 1)
+	assert.Equal(t, "deploy", plan.Stages[0].Runs[0].JobID)
+
+	plan = planner.PlanWorkflowRun("build", "requested", "main")
+	assert.Len(t, plan.Stages, 1)
+	assert.Equal(t, "notify", plan.Stages[0].Runs[0].JobID)
+
+	plan = planner.PlanWorkflowRun("build", "completed", "feature")
+	assert.Len(t, plan.Stages, 0)
+
+	plan = planner.PlanWorkflowRun("deploy", "completed", "main")
+	assert.Len(t, plan.Stages, 0)
+}
+
+func TestPlanSchedule(t *testing.T) {
+	planner, err := NewWorkflowPlanner("testdata/schedule", true)
+	assert.NoError(t, err)
+
+	schedules, err := planner.GetSchedules()
+	assert.NoError(t, err)
+	assert.Len(t, schedules, 2)
+	assert.Equal(t, "30 2 * * *", schedules[0].Cron.String())
+	assert.Equal(t, "*/15 * * * 1-5", schedules[1].Cron.String())
+
+	plan := schedules[0].Plan()
+	assert.Len(t, plan.Stages, 1)
+	assert.Equal(t, "test", plan.Stages[0].Runs[0].JobID)
+}
@@ -0,0 +1,426 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is the line and column of a node in a workflow file, both are 1-based
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Error is an invalid node of a workflow file, File is set by the planner
+type Error struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+}
+
+// Errors are all invalid nodes of a workflow file
+type Errors []*Error
+
+func (e Errors) Error() string {
+	lines := make([]string, 0, len(e))
+	for _, err := range e {
+		lines = append(lines, err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// WithFile sets the file of the errors of a workflow, other errors are returned unchanged
+func WithFile(err error, file string) error {
+	switch e := err.(type) {
+	case Errors:
+		for _, err := range e {
+			err.File = file
+		}
+	case *Error:
+		e.File = file
+	}
+	return err
+}
+
+func nodeError(node *yaml.Node, format string, args ...interface{}) *Error {
+	return &Error{Line: node.Line, Column: node.Column, Message: fmt.Sprintf(format, args...)}
+}
+
+// yamlLinePattern matches the line yaml.v3 prefixes its errors with
+var yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// yamlError converts the errors of yaml.v3 to Errors, io.EOF of empty files is returned unchanged
+func yamlError(err error, node *yaml.Node) error {
+	var messages []string
+	switch e := err.(type) {
+	case *yaml.TypeError:
+		messages = e.Errors
+	default:
+		if err == io.EOF {
+			return err
+		}
+		messages = []string{err.Error()}
+	}
+
+	errs := make(Errors, 0, len(messages))
+	for _, message := range messages {
+		e := &Error{Line: 1, Column: 1, Message: strings.TrimPrefix(message, "yaml: ")}
+		if node != nil {
+			e.Line, e.Column = node.Line, node.Column
+		}
+		if m := yamlLinePattern.FindStringSubmatch(message); m != nil {
+			e.Line, _ = strconv.Atoi(m[1])
+			e.Message = m[2]
+			e.Column = 1
+			if n := nodeAtLine(node, e.Line); n != nil {
+				e.Column = n.Column
+			}
+		}
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// nodeAtLine returns the first node on a line, yaml.v3 only reports the line of errors
+func nodeAtLine(node *yaml.Node, line int) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Line == line {
+		return node
+	}
+	for _, n := range node.Content {
+		if found := nodeAtLine(n, line); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// the keys GitHub accepts in the sections of a workflow
+// Reference: https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions
+var (
+	workflowKeys    = []string{"name", "run-name", "on", "env", "defaults", "jobs", "permissions", "concurrency"}
+	defaultsKeys    = []string{"run"}
+	runDefaultsKeys = []string{"shell", "working-directory"}
+	jobKeys         = []string{
+		"name", "needs", "runs-on", "permissions", "environment", "concurrency", "outputs", "env", "defaults", "if",
+		"steps", "timeout-minutes", "strategy", "continue-on-error", "container", "services", "uses", "with", "secrets",
+	}
+	strategyKeys  = []string{"matrix", "fail-fast", "max-parallel"}
+	containerKeys = []string{"image", "credentials", "env", "ports", "volumes", "options"}
+	stepKeys      = []string{
+		"id", "if", "name", "uses", "run", "working-directory", "shell", "with", "env", "continue-on-error", "timeout-minutes",
+	}
+)
+
+// schemaValidator collects the errors of the nodes of a workflow that don't match the schema of GitHub
+// or can't be decoded into the model
+type schemaValidator struct {
+	errs Errors
+}
+
+func (v *schemaValidator) errorf(node *yaml.Node, format string, args ...interface{}) {
+	v.errs = append(v.errs, nodeError(node, format, args...))
+}
+
+// decode checks that the node can be decoded into the type of val
+func (v *schemaValidator) decode(node *yaml.Node, val interface{}) {
+	if err := node.Decode(val); err != nil {
+		if errs, ok := yamlError(err, node).(Errors); ok {
+			v.errs = append(v.errs, errs...)
+		}
+	}
+}
+
+// mapping checks that the node is a mapping with only the given keys and calls f for every key
+func (v *schemaValidator) mapping(node *yaml.Node, section string, keys []string, f func(key *yaml.Node, value *yaml.Node)) {
+	node = resolveAlias(node)
+	if node.Kind != yaml.MappingNode {
+		if !isNull(node) {
+			v.errorf(node, "%s must be a mapping", section)
+		}
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], resolveAlias(node.Content[i+1])
+		if key.Value == "<<" {
+			continue
+		}
+		if keys != nil && !containsString(keys, key.Value) {
+			v.errorf(key, "unknown key %q in %s, expected one of %s", key.Value, section, strings.Join(keys, ", "))
+			continue
+		}
+		if f != nil {
+			f(key, value)
+		}
+	}
+}
+
+// stringOrList checks that the node is a string or a list of strings
+func (v *schemaValidator) stringOrList(node *yaml.Node, section string) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if resolveAlias(item).Kind != yaml.ScalarNode {
+				v.errorf(item, "%s must be a list of strings", section)
+			}
+		}
+	default:
+		v.errorf(node, "%s must be a string or a list of strings", section)
+	}
+}
+
+// env checks that the node is a mapping of strings, an expression is evaluated at runtime
+func (v *schemaValidator) env(node *yaml.Node, section string) {
+	if node.Kind == yaml.ScalarNode {
+		return
+	}
+	v.mapping(node, section, nil, func(key *yaml.Node, value *yaml.Node) {
+		if value.Kind != yaml.ScalarNode {
+			v.errorf(value, "value of %q in %s must be a string", key.Value, section)
+		}
+	})
+}
+
+func (v *schemaValidator) workflow(node *yaml.Node) {
+	v.mapping(node, "workflow", workflowKeys, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case "on":
+			v.on(value)
+		case "env":
+			v.env(value, "env of the workflow")
+		case "defaults":
+			v.defaults(value, "defaults of the workflow")
+		case "jobs":
+			v.mapping(value, "jobs", nil, func(key *yaml.Node, value *yaml.Node) {
+				v.job(key.Value, value)
+			})
+		}
+	})
+}
+
+func (v *schemaValidator) on(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		v.stringOrList(node, "on")
+		return
+	}
+	v.mapping(node, "on", nil, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case "schedule":
+			v.decode(value, new([]map[string]string))
+		case "workflow_run":
+			v.decode(value, new(WorkflowRunTrigger))
+		}
+	})
+}
+
+func (v *schemaValidator) defaults(node *yaml.Node, section string) {
+	v.mapping(node, section, defaultsKeys, func(key *yaml.Node, value *yaml.Node) {
+		v.mapping(value, "run "+section, runDefaultsKeys, func(key *yaml.Node, value *yaml.Node) {
+			v.decode(value, new(string))
+		})
+	})
+}
+
+func (v *schemaValidator) job(id string, node *yaml.Node) {
+	section := fmt.Sprintf("job %q", id)
+	v.mapping(node, section, jobKeys, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case "needs":
+			v.stringOrList(value, "needs of "+section)
+		case "runs-on":
+			// runner groups are a mapping of group and labels
+			if value.Kind != yaml.MappingNode {
+				v.stringOrList(value, "runs-on of "+section)
+			}
+		case "env":
+			v.env(value, "env of "+section)
+		case "defaults":
+			v.defaults(value, "defaults of "+section)
+		case "strategy":
+			v.strategy(value, "strategy of "+section)
+		case "container":
+			if value.Kind != yaml.ScalarNode {
+				v.container(value, "container of "+section)
+			}
+		case "services":
+			v.mapping(value, "services of "+section, nil, func(key *yaml.Node, value *yaml.Node) {
+				v.container(value, fmt.Sprintf("service %q of %s", key.Value, section))
+			})
+		case "steps":
+			if value.Kind != yaml.SequenceNode {
+				v.errorf(value, "steps of %s must be a list", section)
+				return
+			}
+			for i, step := range value.Content {
+				v.step(resolveAlias(step), fmt.Sprintf("step %d of %s", i, section))
+			}
+		case "outputs":
+			v.decode(value, new(map[string]string))
+		case "timeout-minutes":
+			v.decode(value, new(int64))
+		}
+	})
+}
+
+func (v *schemaValidator) strategy(node *yaml.Node, section string) {
+	v.mapping(node, section, strategyKeys, func(key *yaml.Node, value *yaml.Node) {
+		if key.Value != "matrix" {
+			return
+		}
+		// the matrix and its keys can be expressions, they are evaluated when the job starts
+		if value.Kind == yaml.ScalarNode {
+			return
+		}
+		keys := make([]string, 0)
+		expressions := false
+		v.mapping(value, "matrix of "+section, nil, func(key *yaml.Node, value *yaml.Node) {
+			if strings.Contains(key.Value, "${{") {
+				expressions = true
+				return
+			}
+			if key.Value != "include" && key.Value != "exclude" {
+				keys = append(keys, key.Value)
+			}
+			if value.Kind == yaml.SequenceNode {
+				return
+			}
+			if value.Kind != yaml.ScalarNode || !strings.Contains(value.Value, "${{") {
+				v.errorf(value, "%q in matrix of %s must be a list", key.Value, section)
+			}
+		})
+		if expressions {
+			return
+		}
+		v.mapping(value, "matrix of "+section, nil, func(key *yaml.Node, value *yaml.Node) {
+			if key.Value != "exclude" || value.Kind != yaml.SequenceNode {
+				return
+			}
+			for _, exclude := range value.Content {
+				v.mapping(exclude, "exclude of matrix of "+section, keys, nil)
+			}
+		})
+	})
+}
+
+func (v *schemaValidator) container(node *yaml.Node, section string) {
+	v.mapping(node, section, containerKeys, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case "env":
+			v.env(value, "env of "+section)
+		case "credentials":
+			v.decode(value, new(map[string]string))
+		case "ports", "volumes":
+			v.decode(value, new([]string))
+		default:
+			v.decode(value, new(string))
+		}
+	})
+}
+
+func (v *schemaValidator) step(node *yaml.Node, section string) {
+	v.mapping(node, section, stepKeys, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case "env":
+			v.env(value, "env of "+section)
+		case "with":
+			v.decode(value, new(map[string]string))
+		case "continue-on-error":
+			v.decode(value, new(bool))
+		case "timeout-minutes":
+			v.decode(value, new(int64))
+		case "if":
+		default:
+			v.decode(value, new(string))
+		}
+	})
+}
+
+// validateWorkflow checks a document of a workflow against the schema of GitHub, documents without `on` and `jobs`
+// aren't workflows and are left alone, e.g. the metadata of actions or configs of other tools next to the workflows
+func validateWorkflow(document *yaml.Node) Errors {
+	v := &schemaValidator{errs: make(Errors, 0)}
+	node := document
+	if document.Kind == yaml.DocumentNode && len(document.Content) > 0 {
+		node = resolveAlias(document.Content[0])
+	}
+	if node.Kind == yaml.MappingNode && mappingValue(node, "on") == nil && mappingValue(node, "jobs") == nil {
+		return v.errs
+	}
+	v.workflow(node)
+	return v.errs
+}
+
+// setPositions sets the positions of the jobs and steps of the workflow from its document
+func setPositions(w *Workflow, document *yaml.Node) {
+	node := document
+	if document.Kind == yaml.DocumentNode && len(document.Content) > 0 {
+		node = document.Content[0]
+	}
+	jobs := mappingValue(resolveAlias(node), "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(jobs.Content); i += 2 {
+		key := jobs.Content[i]
+		job, ok := w.Jobs[key.Value]
+		if !ok || job == nil {
+			continue
+		}
+		job.Pos = Position{Line: key.Line, Column: key.Column}
+		steps := mappingValue(resolveAlias(jobs.Content[i+1]), "steps")
+		if steps == nil || steps.Kind != yaml.SequenceNode {
+			continue
+		}
+		for j, step := range steps.Content {
+			if j < len(job.Steps) && job.Steps[j] != nil {
+				job.Steps[j].Pos = Position{Line: step.Line, Column: step.Column}
+			}
+		}
+	}
+}
+
+// mappingValue returns the value of a key of a mapping node, nil if it has no such key
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return resolveAlias(node.Content[i+1])
+		}
+	}
+	return nil
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadWorkflowPositions(t *testing.T) {
+	workflow, err := ReadWorkflow(strings.NewReader(`
+name: positions
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: make
+      - name: test
+        run: make test
+`))
+	assert.NoError(t, err)
+	job := workflow.GetJob("build")
+	assert.Equal(t, Position{Line: 5, Column: 3}, job.Pos)
+	assert.Equal(t, Position{Line: 8, Column: 9}, job.Steps[0].Pos)
+	assert.Equal(t, Position{Line: 9, Column: 9}, job.Steps[1].Pos)
+}
+
+func TestReadWorkflowSchema(t *testing.T) {
+	_, err := ReadWorkflow(strings.NewReader(`
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    timeout: 5
+    needs: [{a: b}]
+    strategy:
+      matrix:
+        os: linux
+        exclude:
+          - arch: arm
+    container:
+      image: node:16
+      entrypoint: sh
+    steps:
+      - run: echo hi
+        shel: bash
+      - uses: actions/checkout@v2
+        with:
+          nested:
+            a: 1
+`))
+	var errs Errors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, Errors{
+		{Line: 6, Column: 5, Message: `unknown key "timeout" in job "build", expected one of ` + strings.Join(jobKeys, ", ")},
+		{Line: 7, Column: 13, Message: `needs of job "build" must be a list of strings`},
+		{Line: 10, Column: 13, Message: `"os" in matrix of strategy of job "build" must be a list`},
+		{Line: 12, Column: 13, Message: `unknown key "arch" in exclude of matrix of strategy of job "build", expected one of os`},
+		{Line: 15, Column: 7, Message: `unknown key "entrypoint" in container of job "build", expected one of ` + strings.Join(containerKeys, ", ")},
+		{Line: 18, Column: 9, Message: `unknown key "shel" in step 0 of job "build", expected one of ` + strings.Join(stepKeys, ", ")},
+		{Line: 22, Column: 13, Message: "cannot unmarshal !!map into string"},
+	}, errs)
+}
+
+func TestReadWorkflowExpressions(t *testing.T) {
+	// matrices, env and runs-on can be expressions that are evaluated at runtime
+	_, err := ReadWorkflow(strings.NewReader(`
+on: push
+jobs:
+  build:
+    runs-on: ${{ matrix.os }}
+    env: ${{ fromJSON(needs.setup.outputs.env) }}
+    strategy:
+      matrix:
+        os: ${{ fromJSON(needs.setup.outputs.os) }}
+        ${{ needs.setup.outputs.key }}: [a, b]
+    steps:
+      - run: echo hi
+  group:
+    runs-on:
+      group: large
+      labels: [linux]
+    strategy:
+      matrix: ${{ fromJSON(needs.setup.outputs.matrix) }}
+    steps:
+      - run: echo hi
+`))
+	assert.NoError(t, err)
+}
+
+func TestReadWorkflowNotAWorkflow(t *testing.T) {
+	// metadata of actions or configs of other tools can be next to the workflows
+	workflow, err := ReadWorkflow(strings.NewReader(`
+name: action
+runs:
+  using: node16
+  main: index.js
+`))
+	assert.NoError(t, err)
+	assert.Empty(t, workflow.Jobs)
+
+	_, err = ReadWorkflow(strings.NewReader(""))
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReadWorkflowSyntaxError(t *testing.T) {
+	_, err := ReadWorkflow(strings.NewReader("on: push\njobs:\n  build: [\n"))
+	var errs Errors
+	if assert.ErrorAs(t, err, &errs) {
+		assert.Len(t, errs, 1)
+		assert.Equal(t, 3, errs[0].Line)
+		assert.NotContains(t, errs[0].Message, "yaml:")
+	}
+}
+
+func TestWithFile(t *testing.T) {
+	err := WithFile(Errors{{Line: 1, Column: 2, Message: "a"}, {Line: 3, Column: 4, Message: "b"}}, "ci.yml")
+	assert.EqualError(t, err, "ci.yml:1:2: a\nci.yml:3:4: b")
+
+	err = WithFile(&Error{Line: 1, Column: 2, Message: "a"}, "ci.yml")
+	assert.EqualError(t, err, "ci.yml:1:2: a")
+
+	err = WithFile(io.EOF, "ci.yml")
+	assert.Equal(t, io.EOF, err)
+}
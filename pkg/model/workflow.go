@@ -43,24 +43,15 @@ func (w *Workflow) On() []string {
 	switch w.RawOn.Kind {
 	case yaml.ScalarNode:
 		var val string
-		err := w.RawOn.Decode(&val)
-		if err != nil {
-			log.Fatal(err)
-		}
+		decodeNode(&w.RawOn, &val)
 		return []string{val}
 	case yaml.SequenceNode:
 		var val []string
-		err := w.RawOn.Decode(&val)
-		if err != nil {
-			log.Fatal(err)
-		}
+		decodeNode(&w.RawOn, &val)
 		return val
 	case yaml.MappingNode:
 		var val map[string]interface{}
-		err := w.RawOn.Decode(&val)
-		if err != nil {
-			log.Fatal(err)
-		}
+		decodeNode(&w.RawOn, &val)
 		var keys []string
 		for k := range val {
 			keys = append(keys, k)
@@ -70,6 +61,146 @@ func (w *Workflow) On() []string {
 	return nil
 }
 
+// OnEvent returns the node of the given event in the `on` key of the workflow, nil if there is none
+func (w *Workflow) OnEvent(event string) *yaml.Node {
+	if w.RawOn.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(w.RawOn.Content); i += 2 {
+		if w.RawOn.Content[i].Value == event {
+			return w.RawOn.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// Schedules returns the cron expressions of the `schedule` event of the workflow
+func (w *Workflow) Schedules() []string {
+	node := w.OnEvent("schedule")
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	var val []map[string]string
+	decodeNode(node, &val)
+	schedules := make([]string, 0, len(val))
+	for _, v := range val {
+		if cron, ok := v["cron"]; ok {
+			schedules = append(schedules, cron)
+		}
+	}
+	return schedules
+}
+
+// WorkflowRunTrigger is the configuration of the `workflow_run` event of a workflow
+type WorkflowRunTrigger struct {
+	Workflows      []string `yaml:"workflows"`
+	Types          []string `yaml:"types"`
+	Branches       []string `yaml:"branches"`
+	BranchesIgnore []string `yaml:"branches-ignore"`
+}
+
+// WorkflowRun decodes the `workflow_run` event configuration, nil if the workflow is not triggered by it
+func (w *Workflow) WorkflowRun() *WorkflowRunTrigger {
+	found := false
+	for _, e := range w.On() {
+		if e == "workflow_run" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	val := new(WorkflowRunTrigger)
+	if node := w.OnEvent("workflow_run"); node != nil && node.Kind == yaml.MappingNode {
+		// the trigger of a workflow that was read is validated already, the schema errors are reported by the lint
+		if err := node.Decode(val); err != nil {
+			log.Warnf("Ignoring the workflow_run trigger of workflow '%s': %v", w.Name, yamlError(err, node))
+			return nil
+		}
+	}
+	return val
+}
+
+// Matches returns true if the given activity of a run of workflow on branch triggers this workflow
+func (t *WorkflowRunTrigger) Matches(workflow string, activityType string, branch string) bool {
+	found := false
+	for _, w := range t.Workflows {
+		if w == workflow {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+
+	// both activity types trigger the workflow if none are defined
+	if len(t.Types) > 0 {
+		found = false
+		for _, at := range t.Types {
+			if at == activityType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(t.Branches) > 0 && !matchFilterPatterns(t.Branches, branch) {
+		return false
+	}
+	if len(t.BranchesIgnore) > 0 && matchFilterPatterns(t.BranchesIgnore, branch) {
+		return false
+	}
+	return true
+}
+
+// matchFilterPatterns evaluates branch filter patterns in order, later patterns override earlier ones
+// Reference: https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#filter-pattern-cheat-sheet
+func matchFilterPatterns(patterns []string, name string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			if filterPatternRegexp(pattern[1:]).MatchString(name) {
+				matched = false
+			}
+		} else if filterPatternRegexp(pattern).MatchString(name) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+func filterPatternRegexp(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?', '+', '[', ']':
+			sb.WriteByte(c)
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return regexp.MustCompile(regexp.QuoteMeta(pattern))
+	}
+	return re
+}
+
 // Job is the structure of one job in a workflow
 type Job struct {
 	Name           string                    `yaml:"name"`
@@ -82,9 +213,11 @@ type Job struct {
 	Services       map[string]*ContainerSpec `yaml:"services"`
 	Strategy       *Strategy                 `yaml:"strategy"`
 	RawContainer   yaml.Node                 `yaml:"container"`
+	RawEnvironment yaml.Node                 `yaml:"environment"`
 	Defaults       Defaults                  `yaml:"defaults"`
 	Outputs        map[string]string         `yaml:"outputs"`
 	Result         string
+	Pos            Position `yaml:"-"` // position of the id of the job in the workflow file
 }
 
 // Strategy for the job
@@ -144,16 +277,30 @@ func (j *Job) Container() *ContainerSpec {
 	switch j.RawContainer.Kind {
 	case yaml.ScalarNode:
 		val = new(ContainerSpec)
-		err := j.RawContainer.Decode(&val.Image)
-		if err != nil {
-			log.Fatal(err)
-		}
+		decodeNode(&j.RawContainer, &val.Image)
 	case yaml.MappingNode:
 		val = new(ContainerSpec)
-		err := j.RawContainer.Decode(val)
-		if err != nil {
-			log.Fatal(err)
-		}
+		decodeNode(&j.RawContainer, val)
+	}
+	return val
+}
+
+// Environment is the deployment environment of a job, the name and url can contain expressions
+type Environment struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+}
+
+// DeploymentEnvironment returns the environment the job deploys to, nil if it has none
+func (j *Job) DeploymentEnvironment() *Environment {
+	var val *Environment
+	switch j.RawEnvironment.Kind {
+	case yaml.ScalarNode:
+		val = new(Environment)
+		decodeNode(&j.RawEnvironment, &val.Name)
+	case yaml.MappingNode:
+		val = new(Environment)
+		decodeNode(&j.RawEnvironment, val)
 	}
 	return val
 }
@@ -163,17 +310,11 @@ func (j *Job) Needs() []string {
 	switch j.RawNeeds.Kind {
 	case yaml.ScalarNode:
 		var val string
-		err := j.RawNeeds.Decode(&val)
-		if err != nil {
-			log.Fatal(err)
-		}
+		decodeNode(&j.RawNeeds, &val)
 		return []string{val}
 	case yaml.SequenceNode:
 		var val []string
-		err := j.RawNeeds.Decode(&val)
-		if err != nil {
-			log.Fatal(err)
-		}
+		decodeNode(&j.RawNeeds, &val)
 		return val
 	}
 	return nil
@@ -184,17 +325,11 @@ func (j *Job) RunsOn() []string {
 	switch j.RawRunsOn.Kind {
 	case yaml.ScalarNode:
 		var val string
-		err := j.RawRunsOn.Decode(&val)
-		if err != nil {
-			log.Fatal(err)
-		}
+		decodeNode(&j.RawRunsOn, &val)
 		return []string{val}
 	case yaml.SequenceNode:
 		var val []string
-		err := j.RawRunsOn.Decode(&val)
-		if err != nil {
-			log.Fatal(err)
-		}
+		decodeNode(&j.RawRunsOn, &val)
 		return val
 	}
 	return nil
@@ -203,9 +338,7 @@ func (j *Job) RunsOn() []string {
 func environment(yml yaml.Node) map[string]string {
 	env := make(map[string]string)
 	if yml.Kind == yaml.MappingNode {
-		if err := yml.Decode(&env); err != nil {
-			log.Fatal(err)
-		}
+		decodeNode(&yml, &env)
 	}
 	return env
 }
@@ -219,9 +352,7 @@ func (j *Job) Environment() map[string]string {
 func (j *Job) Matrix() map[string][]interface{} {
 	if j.Strategy.RawMatrix.Kind == yaml.MappingNode {
 		var val map[string][]interface{}
-		if err := j.Strategy.RawMatrix.Decode(&val); err != nil {
-			log.Fatal(err)
-		}
+		decodeNode(&j.Strategy.RawMatrix, &val)
 		return val
 	}
 	return nil
@@ -270,7 +401,7 @@ func (j *Job) GetMatrixes() []map[string]interface{} {
 						excludes = append(excludes, e)
 					} else {
 						// We fail completely here because that's what GitHub does for non-existing matrix keys, fail on exclude, silent skip on include
-						log.Fatalf("The workflow is not valid. Matrix exclude key '%s' does not match any key within the matrix", k)
+						log.Fatal(nodeError(&j.Strategy.RawMatrix, "The workflow is not valid. Matrix exclude key '%s' does not match any key within the matrix", k))
 					}
 				}
 			}
@@ -336,6 +467,7 @@ type Step struct {
 	With             map[string]string `yaml:"with"`
 	ContinueOnError  bool              `yaml:"continue-on-error"`
 	TimeoutMinutes   int64             `yaml:"timeout-minutes"`
+	Pos              Position          `yaml:"-"` // position of the step in the workflow file
 }
 
 // String gets the name of step
@@ -442,11 +574,29 @@ func (s *Step) Validate(config *CompositeRestrictions) error {
 	return nil
 }
 
-// ReadWorkflow returns a list of jobs for a given workflow file reader
+// ReadWorkflow returns a list of jobs for a given workflow file reader.
+// Nodes that don't match the schema of GitHub, e.g. unknown keys, are returned as Errors with their position.
 func ReadWorkflow(in io.Reader) (*Workflow, error) {
 	w := new(Workflow)
-	err := yaml.NewDecoder(in).Decode(w)
-	return w, err
+	var document yaml.Node
+	if err := yaml.NewDecoder(in).Decode(&document); err != nil {
+		return w, yamlError(err, nil)
+	}
+	if errs := validateWorkflow(&document); len(errs) > 0 {
+		return w, errs
+	}
+	if err := document.Decode(w); err != nil {
+		return w, yamlError(err, nil)
+	}
+	setPositions(w, &document)
+	return w, nil
+}
+
+// decodeNode decodes a node of a workflow, the nodes of workflows that were read are validated already
+func decodeNode(node *yaml.Node, val interface{}) {
+	if err := node.Decode(val); err != nil {
+		log.Fatal(yamlError(err, node))
+	}
 }
 
 // GetJob will get a job by name in the workflow
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
 )
 
 func TestReadWorkflow_StringEvent(t *testing.T) {
@@ -99,6 +100,40 @@ jobs:
 	assert.Contains(t, workflow.Jobs["test2"].Container().Env["foo"], "bar")
 }
 
+func TestReadWorkflow_Environment(t *testing.T) {
+	yaml := `
+name: deploy
+
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo build
+  staging:
+    environment: staging
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo deploy
+  production:
+    environment:
+      name: ${{ github.ref_name }}-production
+      url: ${{ steps.deploy.outputs.url }}
+    runs-on: ubuntu-latest
+    steps:
+    - id: deploy
+      run: echo deploy
+`
+
+	workflow, err := ReadWorkflow(strings.NewReader(yaml))
+	assert.NoError(t, err, "read workflow should succeed")
+	assert.Nil(t, workflow.Jobs["build"].DeploymentEnvironment())
+	assert.Equal(t, &Environment{Name: "staging"}, workflow.Jobs["staging"].DeploymentEnvironment())
+	assert.Equal(t, &Environment{
+		Name: "${{ github.ref_name }}-production",
+		URL:  "${{ steps.deploy.outputs.url }}",
+	}, workflow.Jobs["production"].DeploymentEnvironment())
+}
+
 func TestReadWorkflow_ObjectContainer(t *testing.T) {
 	yaml := `
 name: local-action-docker-url
@@ -277,3 +312,58 @@ func TestStep_ShellCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestReadWorkflow_WorkflowRun(t *testing.T) {
+	yaml := `
+name: deploy
+on:
+  push:
+  workflow_run:
+    workflows: [build, test]
+    branches:
+    - 'releases/**'
+    - '!releases/**-alpha'
+    branches-ignore:
+    - 'releases/old'
+
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo
+`
+
+	workflow, err := ReadWorkflow(strings.NewReader(yaml))
+	assert.NoError(t, err, "read workflow should succeed")
+
+	trigger := workflow.WorkflowRun()
+	assert.NotNil(t, trigger)
+	assert.Equal(t, []string{"build", "test"}, trigger.Workflows)
+
+	tables := []struct {
+		workflow     string
+		activityType string
+		branch       string
+		matches      bool
+	}{
+		{"build", "completed", "releases/v1", true},
+		{"test", "requested", "releases/v1/hotfix", true},
+		{"build", "completed", "releases/v2-alpha", false},
+		{"build", "completed", "releases/old", false},
+		{"build", "completed", "main", false},
+		{"lint", "completed", "releases/v1", false},
+	}
+	for _, table := range tables {
+		assert.Equal(t, table.matches, trigger.Matches(table.workflow, table.activityType, table.branch), "%+v", table)
+	}
+
+	workflow, err = ReadWorkflow(strings.NewReader("on: push\n"))
+	assert.NoError(t, err, "read workflow should succeed")
+	assert.Nil(t, workflow.WorkflowRun())
+}
+
+func TestWorkflowRunInvalidTrigger(t *testing.T) {
+	workflow := &Workflow{Name: "invalid"}
+	assert.NoError(t, yaml.Unmarshal([]byte("workflow_run: {workflows: {build: true}}"), &workflow.RawOn))
+	assert.Nil(t, workflow.WorkflowRun())
+}
@@ -41,6 +41,17 @@ func (rc *RunContext) commandHandler(ctx context.Context) common.LineHandler {
 		}
 		arg = unescapeCommandData(arg)
 		kvPairs = unescapeKvPairs(kvPairs)
+		if rc.Config != nil && rc.Config.Hooks != nil {
+			event := &CommandEvent{
+				Job:        rc.jobEvent(),
+				StepID:     rc.CurrentStep,
+				Command:    command,
+				Parameters: kvPairs,
+				Value:      arg,
+			}
+			event.Job.Outputs = nil
+			rc.Config.Hooks.Command(ctx, event)
+		}
 		switch command {
 		case "set-env":
 			rc.setEnv(ctx, kvPairs, arg)
@@ -0,0 +1,159 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+)
+
+// errDeploymentRejected is the error of a job whose deployment was rejected with Config.ReviewDeployments
+var errDeploymentRejected = errors.New("deployment rejected")
+
+// resolveEnvironment returns the deployment environment of the job with the expressions in its name interpolated,
+// nil if the job has none
+func (rc *RunContext) resolveEnvironment(ctx context.Context) *model.Environment {
+	environment := rc.Run.Job().DeploymentEnvironment()
+	if environment == nil {
+		return nil
+	}
+	ee := rc.ExprEval
+	if ee == nil {
+		ee = rc.NewExpressionEvaluator(ctx)
+	}
+	environment.Name = strings.TrimSpace(ee.Interpolate(environment.Name))
+	if environment.Name == "" {
+		return nil
+	}
+	return environment
+}
+
+// startEnvironment resolves the deployment environment of the job, its secrets and vars replace the ones of the
+// repository in the contexts of the job. With Config.ReviewDeployments the user approves or rejects the deployment
+// like a required reviewer of the environment, it returns false if the deployment was rejected.
+func (rc *RunContext) startEnvironment(ctx context.Context) (bool, error) {
+	rc.environment = rc.resolveEnvironment(ctx)
+	if rc.environment == nil {
+		return true, nil
+	}
+	logger := common.Logger(ctx)
+	logger.Infof("\U0001F30D  Environment: %s", rc.environment.Name)
+
+	for _, secret := range environmentValues(rc.Config.EnvironmentSecrets, rc.environment.Name) {
+		rc.AddMask(secret)
+	}
+	rc.ExprEval = rc.NewExpressionEvaluator(ctx)
+
+	if !rc.Config.ReviewDeployments || common.Dryrun(ctx) {
+		return true, nil
+	}
+	if !stdinPauser.interactive {
+		return false, fmt.Errorf("Unable to review the deployment to environment '%s' because the input is not a terminal", rc.environment.Name)
+	}
+	approved, err := stdinPauser.review(ctx, rc)
+	if err != nil {
+		return false, err
+	}
+	if !approved {
+		logger.Errorf("\u274C  Deployment to environment '%s' was rejected", rc.environment.Name)
+		// a rejected deployment has no url to log
+		rc.environment.URL = ""
+	}
+	return approved, nil
+}
+
+// logEnvironmentURL logs the url of the deployment environment at the end of the job, it can use the outputs of the steps
+func (rc *RunContext) logEnvironmentURL() common.Executor {
+	return func(ctx context.Context) error {
+		if rc.environment == nil || rc.environment.URL == "" {
+			return nil
+		}
+		if url := rc.NewExpressionEvaluator(ctx).Interpolate(rc.environment.URL); url != "" {
+			common.Logger(ctx).Infof("\U0001F517  Environment %s: %s", rc.environment.Name, url)
+		}
+		return nil
+	}
+}
+
+// getSecretsContext returns the secrets of the repository, replaced by the ones of the deployment environment of the job
+func (rc *RunContext) getSecretsContext() map[string]string {
+	if rc.environment == nil {
+		return rc.Config.Secrets
+	}
+	return mergeFold(rc.Config.Secrets, environmentValues(rc.Config.EnvironmentSecrets, rc.environment.Name))
+}
+
+// getVarsContext returns the configuration variables of the repository, replaced by the ones of the deployment
+// environment of the job
+func (rc *RunContext) getVarsContext() map[string]string {
+	if rc.environment == nil {
+		return rc.Config.Vars
+	}
+	return mergeFold(rc.Config.Vars, environmentValues(rc.Config.EnvironmentVars, rc.environment.Name))
+}
+
+// environmentValues returns the values of the environment, names of environments are case insensitive like on GitHub
+func environmentValues(environments map[string]map[string]string, name string) map[string]string {
+	for n, values := range environments {
+		if strings.EqualFold(n, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+// mergeFold returns the values of base replaced by the ones of override, names match case insensitively like in expressions
+func mergeFold(base map[string]string, override map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		for existing := range merged {
+			if strings.EqualFold(existing, k) {
+				delete(merged, existing)
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// lookupFold returns the value with the exact name, else the one of the first name in sorted order that matches
+// case insensitively, so that the value doesn't depend on the order of the map
+func lookupFold(values map[string]string, name string) (string, bool) {
+	if v, ok := values[name]; ok {
+		return v, true
+	}
+	names := make([]string, 0, len(values))
+	for n := range values {
+		if strings.EqualFold(n, name) {
+			names = append(names, n)
+		}
+	}
+	if len(names) == 0 {
+		return "", false
+	}
+	sort.Strings(names)
+	return values[names[0]], true
+}
+
+// review asks the user to approve or reject the deployment of the job to its environment
+func (p *pauser) review(ctx context.Context, rc *RunContext) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fmt.Fprintf(p.out, "\n\U0001F6A6  [%s] Review the deployment to environment '%s'\n", strings.TrimSpace(rc.String()), rc.environment.Name)
+	option, err := p.prompt(ctx, []pauseOption{
+		{"approve", pauseContinue},
+		{"reject", pauseAbort},
+	})
+	if err != nil {
+		return false, err
+	}
+	return option.choice == pauseContinue, nil
+}
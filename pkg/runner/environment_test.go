@@ -0,0 +1,159 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+)
+
+func createEnvironmentRunContext(t *testing.T, environment string) *RunContext {
+	workflow, err := model.ReadWorkflow(strings.NewReader(`
+name: deploy
+on: push
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    environment: ` + environment + `
+    steps:
+      - id: deploy
+        run: echo ok
+`))
+	assert.NoError(t, err)
+
+	return &RunContext{
+		Config: &Config{
+			Workdir:   ".",
+			EventName: "push",
+			Secrets:   map[string]string{"TOKEN": "repository-token", "OTHER": "other"},
+			Vars:      map[string]string{"REGION": "eu-west-1"},
+			EnvironmentSecrets: map[string]map[string]string{
+				"Production": {"token": "production-token"},
+			},
+			EnvironmentVars: map[string]map[string]string{
+				"production": {"REGION": "us-east-1"},
+			},
+		},
+		Env: map[string]string{"TARGET": "production"},
+		Run: &model.Run{
+			JobID:    "deploy",
+			Workflow: workflow,
+		},
+		StepResults: map[string]*model.StepResult{},
+	}
+}
+
+func TestResolveEnvironment(t *testing.T) {
+	rc := createEnvironmentRunContext(t, `{name: "${{ env.TARGET }}", url: "${{ steps.deploy.outputs.url }}"}`)
+	environment := rc.resolveEnvironment(context.Background())
+	assert.Equal(t, &model.Environment{Name: "production", URL: "${{ steps.deploy.outputs.url }}"}, environment)
+
+	rc = createEnvironmentRunContext(t, `"${{ env.MISSING }}"`)
+	assert.Nil(t, rc.resolveEnvironment(context.Background()))
+}
+
+func TestStartEnvironment(t *testing.T) {
+	rc := createEnvironmentRunContext(t, "production")
+	logger, hook := test.NewNullLogger()
+	ctx := common.WithLogger(context.Background(), logger)
+
+	assert.Equal(t, "repository-token", rc.NewExpressionEvaluator(context.Background()).Interpolate("${{ secrets.TOKEN }}"))
+
+	ok, err := rc.startEnvironment(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "\U0001F30D  Environment: production", hook.LastEntry().Message)
+	assert.Equal(t, []string{"production-token"}, rc.Masks)
+
+	assert.Equal(t, "production-token other us-east-1", rc.ExprEval.Interpolate("${{ secrets.TOKEN }} ${{ secrets.OTHER }} ${{ vars.REGION }}"))
+}
+
+func TestStartEnvironmentGithubToken(t *testing.T) {
+	rc := createEnvironmentRunContext(t, "production")
+	rc.Config.Secrets["GITHUB_TOKEN"] = "repository-github-token"
+	rc.Config.EnvironmentSecrets["Production"]["github_token"] = "production-github-token"
+	assert.Equal(t, "repository-github-token", rc.getGithubContext().Token)
+
+	ok, err := rc.startEnvironment(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "production-github-token", rc.getGithubContext().Token)
+	assert.Equal(t, "production-github-token", rc.ExprEval.Interpolate("${{ github.token }}"))
+}
+
+func TestGithubTokenBothCases(t *testing.T) {
+	rc := createEnvironmentRunContext(t, `""`)
+	rc.Config.Secrets["github_token"] = "lower-github-token"
+	rc.Config.Secrets["GITHUB_TOKEN"] = "repository-github-token"
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "repository-github-token", rc.getGithubContext().Token)
+	}
+}
+
+func TestStartEnvironmentWithoutEnvironment(t *testing.T) {
+	rc := createEnvironmentRunContext(t, `""`)
+	rc.Config.ReviewDeployments = true
+
+	ok, err := rc.startEnvironment(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "repository-token eu-west-1", rc.NewExpressionEvaluator(context.Background()).Interpolate("${{ secrets.TOKEN }} ${{ vars.REGION }}"))
+}
+
+func TestLogEnvironmentURL(t *testing.T) {
+	rc := createEnvironmentRunContext(t, `{name: production, url: "https://${{ steps.deploy.outputs.host }}"}`)
+	rc.StepResults["deploy"] = &model.StepResult{Outputs: map[string]string{"host": "example.com"}}
+	logger, hook := test.NewNullLogger()
+	ctx := common.WithLogger(context.Background(), logger)
+
+	assert.NoError(t, rc.logEnvironmentURL()(ctx))
+	assert.Empty(t, hook.AllEntries())
+
+	rc.environment = rc.resolveEnvironment(context.Background())
+	assert.NoError(t, rc.logEnvironmentURL()(ctx))
+	assert.Equal(t, "\U0001F517  Environment production: https://example.com", hook.LastEntry().Message)
+}
+
+func TestPauseReview(t *testing.T) {
+	rc := createEnvironmentRunContext(t, "production")
+	rc.environment = rc.resolveEnvironment(context.Background())
+
+	for input, approved := range map[string]bool{"a\n": true, "r\n": false} {
+		out := new(bytes.Buffer)
+		p := &pauser{in: strings.NewReader(input), out: out, interactive: true}
+
+		ok, err := p.review(context.Background(), rc)
+		assert.NoError(t, err)
+		assert.Equal(t, approved, ok)
+		assert.Contains(t, out.String(), "Review the deployment to environment 'production'")
+		assert.Contains(t, out.String(), "[a]pprove, [r]eject? ")
+	}
+}
+
+func TestLookupFold(t *testing.T) {
+	secrets := map[string]string{"github_token": "lower", "GITHUB_TOKEN": "exact", "Github_Token": "mixed"}
+	for i := 0; i < 10; i++ {
+		token, ok := lookupFold(secrets, "GITHUB_TOKEN")
+		assert.True(t, ok)
+		assert.Equal(t, "exact", token)
+	}
+	delete(secrets, "GITHUB_TOKEN")
+	for i := 0; i < 10; i++ {
+		token, ok := lookupFold(secrets, "GITHUB_TOKEN")
+		assert.True(t, ok)
+		assert.Equal(t, "mixed", token)
+	}
+	_, ok := lookupFold(secrets, "TOKEN")
+	assert.False(t, ok)
+}
+
+func TestMergeFold(t *testing.T) {
+	assert.Equal(t, map[string]string{"a": "1", "b": "3"}, mergeFold(map[string]string{"a": "1", "B": "2"}, map[string]string{"b": "3"}))
+	assert.Equal(t, map[string]string{"a": "1"}, mergeFold(map[string]string{"a": "1"}, nil))
+}
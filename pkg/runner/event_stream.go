@@ -0,0 +1,227 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// EventStreamVersion is the version of the records written by EventStream, it changes on incompatible changes only
+const EventStreamVersion = 1
+
+// Types of the records written by EventStream
+const (
+	EventTypePlanStarted   = "plan_started"
+	EventTypePlanCompleted = "plan_completed"
+	EventTypeJobStarted    = "job_started"
+	EventTypeJobCompleted  = "job_completed"
+	EventTypeStepStarted   = "step_started"
+	EventTypeStepCompleted = "step_completed"
+	EventTypeCommand       = "command"
+	EventTypeOutput        = "output"
+)
+
+// EventRecord is a line of the event stream, see EVENTS.md for the schema
+type EventRecord struct {
+	Version    int                    `json:"version"`
+	Seq        int                    `json:"seq"`
+	Type       string                 `json:"type"`
+	Time       time.Time              `json:"time"`
+	Jobs       []EventRecordJob       `json:"jobs,omitempty"`
+	Workflow   string                 `json:"workflow,omitempty"`
+	JobID      string                 `json:"job_id,omitempty"`
+	Job        string                 `json:"job,omitempty"`
+	Matrix     map[string]interface{} `json:"matrix,omitempty"`
+	StepID     string                 `json:"step_id,omitempty"`
+	Step       string                 `json:"step,omitempty"`
+	Result     string                 `json:"result,omitempty"`
+	Outcome    string                 `json:"outcome,omitempty"`
+	Outputs    map[string]string      `json:"outputs,omitempty"`
+	DurationMs *int64                 `json:"duration_ms,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	Command    string                 `json:"command,omitempty"`
+	Parameters map[string]string      `json:"parameters,omitempty"`
+	Value      *string                `json:"value,omitempty"`
+	Line       *string                `json:"line,omitempty"`
+}
+
+// EventRecordJob is a job planned by a `plan_started` record
+type EventRecordJob struct {
+	Stage    int      `json:"stage"`
+	Workflow string   `json:"workflow"`
+	JobID    string   `json:"job_id"`
+	Name     string   `json:"name"`
+	Needs    []string `json:"needs"`
+}
+
+// EventStream writes the lifecycle events of a runner as newline delimited JSON records.
+// Secrets and values masked with `add-mask` are replaced with `***`.
+type EventStream struct {
+	mu     sync.Mutex
+	w      io.Writer
+	seq    int
+	masker secretMasker
+}
+
+// NewEventStream creates an event stream writing to w, secrets are masked in all records
+func NewEventStream(w io.Writer, secrets map[string]string) *EventStream {
+	return &EventStream{
+		w:      w,
+		masker: secretMasker{secrets: secrets},
+	}
+}
+
+func (es *EventStream) write(record *EventRecord) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	es.seq++
+	record.Version = EventStreamVersion
+	record.Seq = es.seq
+	record.Time = time.Now()
+	es.mask(record)
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		log.Errorf("Failed to encode event: %v", err)
+		return
+	}
+	if _, err := es.w.Write(append(line, '\n')); err != nil {
+		log.Errorf("Failed to write event: %v", err)
+	}
+}
+
+// mask replaces secrets in all values of the record that are printed by the workflow, the lock has to be held
+func (es *EventStream) mask(record *EventRecord) {
+	mask := es.masker.mask
+	for k, v := range record.Outputs {
+		record.Outputs[k] = mask(v)
+	}
+	for k, v := range record.Parameters {
+		record.Parameters[k] = mask(v)
+	}
+	if record.Value != nil {
+		value := mask(*record.Value)
+		record.Value = &value
+	}
+	if record.Line != nil {
+		line := mask(*record.Line)
+		record.Line = &line
+	}
+	record.Error = mask(record.Error)
+}
+
+func durationMs(d time.Duration) *int64 {
+	ms := d.Milliseconds()
+	return &ms
+}
+
+func jobRecord(recordType string, event *JobEvent) *EventRecord {
+	return &EventRecord{
+		Type:     recordType,
+		Workflow: event.Run.Workflow.Name,
+		JobID:    event.Run.JobID,
+		Job:      event.Name,
+		Matrix:   event.Matrix,
+	}
+}
+
+// PlanStarted writes a `plan_started` record with the planned jobs
+func (es *EventStream) PlanStarted(ctx context.Context, event *PlanEvent) {
+	record := &EventRecord{
+		Type: EventTypePlanStarted,
+		Jobs: make([]EventRecordJob, 0),
+	}
+	for i, stage := range event.Plan.Stages {
+		for _, run := range stage.Runs {
+			needs := run.Job().Needs()
+			if needs == nil {
+				needs = make([]string, 0)
+			}
+			record.Jobs = append(record.Jobs, EventRecordJob{
+				Stage:    i,
+				Workflow: run.Workflow.Name,
+				JobID:    run.JobID,
+				Name:     run.String(),
+				Needs:    needs,
+			})
+		}
+	}
+	es.write(record)
+}
+
+// PlanCompleted writes a `plan_completed` record
+func (es *EventStream) PlanCompleted(ctx context.Context, event *PlanEvent) {
+	record := &EventRecord{
+		Type:       EventTypePlanCompleted,
+		Result:     "success",
+		DurationMs: durationMs(event.Duration),
+	}
+	if event.Err != nil {
+		record.Result = "failure"
+		record.Error = event.Err.Error()
+	}
+	es.write(record)
+}
+
+// JobStarted writes a `job_started` record
+func (es *EventStream) JobStarted(ctx context.Context, event *JobEvent) {
+	es.write(jobRecord(EventTypeJobStarted, event))
+}
+
+// JobCompleted writes a `job_completed` record
+func (es *EventStream) JobCompleted(ctx context.Context, event *JobEvent) {
+	record := jobRecord(EventTypeJobCompleted, event)
+	record.Result = event.Result
+	record.Outputs = copyStringMap(event.Outputs)
+	record.DurationMs = durationMs(event.Duration)
+	es.write(record)
+}
+
+// StepStarted writes a `step_started` record
+func (es *EventStream) StepStarted(ctx context.Context, event *StepEvent) {
+	record := jobRecord(EventTypeStepStarted, event.Job)
+	record.StepID = event.Step.ID
+	record.Step = event.Step.String()
+	es.write(record)
+}
+
+// StepCompleted writes a `step_completed` record
+func (es *EventStream) StepCompleted(ctx context.Context, event *StepEvent) {
+	record := jobRecord(EventTypeStepCompleted, event.Job)
+	record.StepID = event.Step.ID
+	record.Step = event.Step.String()
+	record.Result = event.Result.Conclusion.String()
+	record.Outcome = event.Result.Outcome.String()
+	record.Outputs = copyStringMap(event.Result.Outputs)
+	record.DurationMs = durationMs(event.Duration)
+	es.write(record)
+}
+
+// Command writes a `command` record, values of `add-mask` are masked in this and all following records
+func (es *EventStream) Command(ctx context.Context, event *CommandEvent) {
+	if event.Command == "add-mask" {
+		es.mu.Lock()
+		es.masker.add(event.Value)
+		es.mu.Unlock()
+	}
+
+	record := jobRecord(EventTypeCommand, event.Job)
+	record.StepID = event.StepID
+	record.Command = event.Command
+	record.Parameters = copyStringMap(event.Parameters)
+	record.Value = &event.Value
+	es.write(record)
+}
+
+// Output writes an `output` record
+func (es *EventStream) Output(ctx context.Context, event *OutputEvent) {
+	record := jobRecord(EventTypeOutput, event.Job)
+	record.StepID = event.StepID
+	record.Line = &event.Line
+	es.write(record)
+}
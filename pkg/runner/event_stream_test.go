@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func readEventRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	records := make([]map[string]interface{}, 0)
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		record := make(map[string]interface{})
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record), scanner.Text())
+		assert.NotEmpty(t, record["time"])
+		delete(record, "time")
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestEventStream(t *testing.T) {
+	ctx := context.Background()
+	buf := &bytes.Buffer{}
+	es := NewEventStream(buf, map[string]string{"TOKEN": "s3cr3t"})
+
+	workflow := &model.Workflow{
+		Name: "ci",
+		Jobs: map[string]*model.Job{
+			"build": {},
+		},
+	}
+	run := &model.Run{Workflow: workflow, JobID: "build"}
+	plan := &model.Plan{Stages: []*model.Stage{{Runs: []*model.Run{run}}}}
+	job := &JobEvent{Run: run, Name: "ci/build-1", Matrix: map[string]interface{}{"node": 16}}
+	step := &model.Step{ID: "test", Run: "make test"}
+
+	es.PlanStarted(ctx, &PlanEvent{Plan: plan})
+	es.JobStarted(ctx, job)
+	es.StepStarted(ctx, &StepEvent{Job: job, Step: step})
+	es.Command(ctx, &CommandEvent{Job: job, StepID: "test", Command: "add-mask", Value: "hidden"})
+	es.Output(ctx, &OutputEvent{Job: job, StepID: "test", Line: "token s3cr3t is hidden"})
+	es.StepCompleted(ctx, &StepEvent{Job: job, Step: step, Duration: 1500 * time.Millisecond, Result: &model.StepResult{
+		Outcome:    model.StepStatusFailure,
+		Conclusion: model.StepStatusSuccess,
+		Outputs:    map[string]string{"coverage": "hidden 80%"},
+	}})
+	es.JobCompleted(ctx, &JobEvent{Run: run, Name: job.Name, Matrix: job.Matrix, Result: "success", Outputs: map[string]string{}, Duration: 2 * time.Second})
+	es.PlanCompleted(ctx, &PlanEvent{Plan: plan, Err: errors.New("Job 's3cr3t' failed"), Duration: 3 * time.Second})
+
+	jobFields := func(record map[string]interface{}) map[string]interface{} {
+		record["version"] = float64(EventStreamVersion)
+		record["workflow"] = "ci"
+		record["job_id"] = "build"
+		record["job"] = "ci/build-1"
+		record["matrix"] = map[string]interface{}{"node": float64(16)}
+		return record
+	}
+	assert.Equal(t, []map[string]interface{}{
+		{"version": float64(1), "seq": float64(1), "type": "plan_started", "jobs": []interface{}{
+			map[string]interface{}{"stage": float64(0), "workflow": "ci", "job_id": "build", "name": "build", "needs": []interface{}{}},
+		}},
+		jobFields(map[string]interface{}{"seq": float64(2), "type": "job_started"}),
+		jobFields(map[string]interface{}{"seq": float64(3), "type": "step_started", "step_id": "test", "step": "make test"}),
+		jobFields(map[string]interface{}{"seq": float64(4), "type": "command", "step_id": "test", "command": "add-mask", "value": "***"}),
+		jobFields(map[string]interface{}{"seq": float64(5), "type": "output", "step_id": "test", "line": "token *** is ***"}),
+		jobFields(map[string]interface{}{"seq": float64(6), "type": "step_completed", "step_id": "test", "step": "make test",
+			"result": "success", "outcome": "failure", "outputs": map[string]interface{}{"coverage": "*** 80%"}, "duration_ms": float64(1500)}),
+		jobFields(map[string]interface{}{"seq": float64(7), "type": "job_completed", "result": "success", "duration_ms": float64(2000)}),
+		{"version": float64(1), "seq": float64(8), "type": "plan_completed", "result": "failure", "error": "Job '***' failed", "duration_ms": float64(3000)},
+	}, readEventRecords(t, buf))
+}
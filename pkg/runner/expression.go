@@ -1,11 +1,16 @@
 package runner
 
 import (
+	"context"
 	"fmt"
+	"io/fs"
 	"regexp"
 	"strings"
+	"sync"
 
+	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/model"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 )
@@ -17,8 +22,33 @@ type ExpressionEvaluator interface {
 	Interpolate(string) string
 }
 
-// NewExpressionEvaluator creates a new evaluator
-func (rc *RunContext) NewExpressionEvaluator() ExpressionEvaluator {
+// NewExpressionEvaluator creates a new evaluator, hashFiles copies the workspace of the job container with ctx
+func (rc *RunContext) NewExpressionEvaluator(ctx context.Context) ExpressionEvaluator {
+	return newExpressionEvaluator(rc.newEvaluationEnvironment(), rc.expressionConfig(ctx, "job"))
+}
+
+// expressionConfig returns the config of the interpreter for expressions of the job or its steps,
+// with the functions and contexts added by Config.ExpressionFunctions and Config.ExpressionContexts.
+// hashFiles reads the workspace of the job container unless the workdir is bound to it.
+func (rc *RunContext) expressionConfig(ctx context.Context, exprContext string) exprparser.Config {
+	config := exprparser.Config{
+		Run:        rc.Run,
+		WorkingDir: rc.Config.Workdir,
+		Context:    exprContext,
+		Functions:  rc.Config.ExpressionFunctions,
+		Contexts:   rc.Config.ExpressionContexts,
+		Strict:     rc.Config.StrictExpressions,
+	}
+	if !rc.Config.BindWorkdir {
+		config.Workspace = func(searchPaths []string, fn func(workspace fs.FS) error) error {
+			return rc.withJobContainerWorkspace(ctx, searchPaths, fn)
+		}
+	}
+	return config
+}
+
+// newEvaluationEnvironment creates the contexts of the job for expressions
+func (rc *RunContext) newEvaluationEnvironment() *exprparser.EvaluationEnvironment {
 	// todo: cleanup EvaluationEnvironment creation
 	job := rc.Run.Job()
 	strategy := make(map[string]interface{})
@@ -37,9 +67,11 @@ func (rc *RunContext) NewExpressionEvaluator() ExpressionEvaluator {
 		}
 	}
 
-	secrets := rc.Config.Secrets
+	secrets := rc.getSecretsContext()
+	vars := rc.getVarsContext()
 	if rc.Composite != nil {
 		secrets = nil
+		vars = nil
 	}
 
 	ee := &exprparser.EvaluationEnvironment{
@@ -59,18 +91,50 @@ func (rc *RunContext) NewExpressionEvaluator() ExpressionEvaluator {
 		Matrix:   rc.Matrix,
 		Needs:    using,
 		Inputs:   rc.Inputs,
+		Vars:     vars,
 	}
-	return expressionEvaluator{
-		interpreter: exprparser.NewInterpeter(ee, exprparser.Config{
-			Run:        rc.Run,
-			WorkingDir: rc.Config.Workdir,
-			Context:    "job",
-		}),
+	return ee
+}
+
+// ExpressionFixture is the state of a job outside of a run, the results of the jobs it needs and of its earlier steps
+type ExpressionFixture struct {
+	Matrix map[string]interface{}       // combination of the matrix of the job
+	Needs  map[string]map[string]string // outputs of the needed jobs by their id
+	Steps  map[string]*model.StepResult // results of the earlier steps by their id
+	Inputs map[string]interface{}
+}
+
+// NewExpressionEnvironment creates the contexts a step of the job can use in expressions without running it, e.g. for `act expr`.
+// The outputs of the needed jobs in the fixture replace the ones of the workflow.
+func NewExpressionEnvironment(config *Config, run *model.Run, fixture *ExpressionFixture) (*exprparser.EvaluationEnvironment, exprparser.Config, error) {
+	eventJSON, err := readEventJSON(config)
+	if err != nil {
+		return nil, exprparser.Config{}, err
+	}
+	rc := &RunContext{
+		Config:      config,
+		Run:         run,
+		EventJSON:   eventJSON,
+		StepResults: fixture.Steps,
+		Matrix:      fixture.Matrix,
+		Inputs:      fixture.Inputs,
+	}
+	if rc.StepResults == nil {
+		rc.StepResults = make(map[string]*model.StepResult)
+	}
+	rc.environment = rc.resolveEnvironment(context.Background())
+
+	ee := rc.newEvaluationEnvironment()
+	for id, outputs := range fixture.Needs {
+		ee.Needs[id] = map[string]map[string]string{
+			"outputs": outputs,
+		}
 	}
+	return ee, rc.expressionConfig(context.Background(), "step"), nil
 }
 
-// NewExpressionEvaluator creates a new evaluator
-func (sc *StepContext) NewExpressionEvaluator() ExpressionEvaluator {
+// NewExpressionEvaluator creates a new evaluator, hashFiles copies the workspace of the job container with ctx
+func (sc *StepContext) NewExpressionEvaluator(ctx context.Context) ExpressionEvaluator {
 	rc := sc.RunContext
 	// todo: cleanup EvaluationEnvironment creation
 	job := rc.Run.Job()
@@ -90,9 +154,11 @@ func (sc *StepContext) NewExpressionEvaluator() ExpressionEvaluator {
 		}
 	}
 
-	secrets := rc.Config.Secrets
+	secrets := rc.getSecretsContext()
+	vars := rc.getVarsContext()
 	if rc.Composite != nil {
 		secrets = nil
+		vars = nil
 	}
 
 	ee := &exprparser.EvaluationEnvironment{
@@ -112,18 +178,41 @@ func (sc *StepContext) NewExpressionEvaluator() ExpressionEvaluator {
 		// todo: should be unavailable
 		// but required to interpolate/evaluate the inputs in actions/composite
 		Inputs: rc.Inputs,
+		Vars:   vars,
 	}
-	return expressionEvaluator{
-		interpreter: exprparser.NewInterpeter(ee, exprparser.Config{
-			Run:        rc.Run,
-			WorkingDir: rc.Config.Workdir,
-			Context:    "step",
-		}),
-	}
+	return newExpressionEvaluator(ee, rc.expressionConfig(ctx, "step"))
 }
 
 type expressionEvaluator struct {
 	interpreter exprparser.Interpreter
+	env         *exprparser.EvaluationEnvironment
+	config      exprparser.Config
+}
+
+func newExpressionEvaluator(env *exprparser.EvaluationEnvironment, config exprparser.Config) expressionEvaluator {
+	return expressionEvaluator{
+		interpreter: exprparser.NewInterpeter(env, config),
+		env:         env,
+		config:      config,
+	}
+}
+
+// traceExpressions returns an evaluator logging how the sub-expressions of every expression evaluated at debug level
+// if Config.TraceExpressions is set, e.g. to see why an `if` condition skipped a step
+func traceExpressions(ctx context.Context, config *Config, evaluator ExpressionEvaluator) ExpressionEvaluator {
+	ee, ok := evaluator.(expressionEvaluator)
+	if !ok || !config.TraceExpressions {
+		return evaluator
+	}
+	logger := common.Logger(ctx)
+	traceConfig := ee.config
+	traceConfig.Trace = func(trace *exprparser.Trace) {
+		logger.Debugf("Evaluated expression:")
+		for _, line := range strings.Split(trace.String(), "\n") {
+			logger.Debugf("  %s", line)
+		}
+	}
+	return newExpressionEvaluator(ee.env, traceConfig)
 }
 
 func (ee expressionEvaluator) evaluate(in string, isIfExpression bool) (interface{}, error) {
@@ -149,9 +238,10 @@ func (ee expressionEvaluator) evaluateScalarYamlNode(node *yaml.Node) error {
 	return node.Encode(res)
 }
 
+// GitHub has this undocumented feature to merge maps, called insert directive
+var insertDirective = regexp.MustCompile(`\${{\s*insert\s*}}`)
+
 func (ee expressionEvaluator) evaluateMappingYamlNode(node *yaml.Node) error {
-	// GitHub has this undocumented feature to merge maps, called insert directive
-	insertDirective := regexp.MustCompile(`\${{\s*insert\s*}}`)
 	for i := 0; i < len(node.Content)/2; {
 		k := node.Content[i*2]
 		v := node.Content[i*2+1]
@@ -242,13 +332,51 @@ func escapeFormatString(in string) string {
 	return strings.ReplaceAll(strings.ReplaceAll(in, "{", "{{"), "}", "}}")
 }
 
-//nolint:gocyclo
+// maxCachedRewrites bounds the cache of rewriteSubExpression, it is cleared when it is full
+const maxCachedRewrites = 10000
+
+type rewriteKey struct {
+	in          string
+	forceFormat bool
+}
+
+var rewriteCache = struct {
+	sync.RWMutex
+	expressions map[rewriteKey]string
+}{expressions: make(map[rewriteKey]string)}
+
+// rewriteSubExpression rewrites a string with ${{ }} expressions to a single expression, e.g. to a call of format().
+// The same strings are rewritten for every step and every combination of a matrix, so they are cached.
 func rewriteSubExpression(in string, forceFormat bool) (string, error) {
 	if !strings.Contains(in, "${{") || !strings.Contains(in, "}}") {
 		return in, nil
 	}
 
-	strPattern := regexp.MustCompile("(?:''|[^'])*'")
+	key := rewriteKey{in, forceFormat}
+	rewriteCache.RLock()
+	out, ok := rewriteCache.expressions[key]
+	rewriteCache.RUnlock()
+	if ok {
+		return out, nil
+	}
+
+	out, err := rewriteSubExpressionUncached(in, forceFormat)
+	if err != nil {
+		return out, err
+	}
+	rewriteCache.Lock()
+	if len(rewriteCache.expressions) >= maxCachedRewrites {
+		rewriteCache.expressions = make(map[rewriteKey]string)
+	}
+	rewriteCache.expressions[key] = out
+	rewriteCache.Unlock()
+	return out, nil
+}
+
+var strPattern = regexp.MustCompile("(?:''|[^'])*'")
+
+//nolint:gocyclo
+func rewriteSubExpressionUncached(in string, forceFormat bool) (string, error) {
 	pos := 0
 	exprStart := -1
 	strStart := -1
@@ -1,13 +1,18 @@
 package runner
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"regexp"
 	"sort"
 	"testing"
 
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/exprparser"
 	"github.com/nektos/act/pkg/model"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	assert "github.com/stretchr/testify/assert"
 	yaml "gopkg.in/yaml.v3"
 )
@@ -75,7 +80,7 @@ func createRunContext(t *testing.T) *RunContext {
 
 func TestEvaluateRunContext(t *testing.T) {
 	rc := createRunContext(t)
-	ee := rc.NewExpressionEvaluator()
+	ee := rc.NewExpressionEvaluator(context.Background())
 
 	tables := []struct {
 		in      string
@@ -147,13 +152,142 @@ func TestEvaluateRunContext(t *testing.T) {
 	}
 }
 
+func TestNewExpressionEnvironment(t *testing.T) {
+	run := &model.Run{
+		JobID: "test",
+		Workflow: &model.Workflow{
+			Name: "test-workflow",
+			Jobs: map[string]*model.Job{
+				"build": {
+					Outputs: map[string]string{
+						"version": "from the workflow",
+					},
+				},
+				"test": {
+					RawNeeds: yaml.Node{Kind: yaml.ScalarNode, Value: "build"},
+				},
+			},
+		},
+	}
+	config := &Config{
+		Workdir:   ".",
+		EventName: "push",
+		EventJSON: `{"ref": "refs/heads/main"}`,
+		Secrets: map[string]string{
+			"TOKEN": "secret",
+		},
+		ExpressionFunctions: map[string]*exprparser.Function{
+			"greet": {
+				MinArgs: 1,
+				MaxArgs: 1,
+				Call: func(args []interface{}) (interface{}, error) {
+					return fmt.Sprintf("hello %v", args[0]), nil
+				},
+			},
+		},
+		ExpressionContexts: map[string]interface{}{
+			"deploy": map[string]string{"REGION": "eu"},
+		},
+		Vars: map[string]string{"REGION": "eu-west-1"},
+	}
+	fixture := &ExpressionFixture{
+		Matrix: map[string]interface{}{"os": "ubuntu-latest"},
+		Needs: map[string]map[string]string{
+			"build": {"version": "1.0.0"},
+		},
+		Steps: map[string]*model.StepResult{
+			"compile": {
+				Conclusion: model.StepStatusFailure,
+				Outcome:    model.StepStatusFailure,
+				Outputs:    map[string]string{"binary": "act"},
+			},
+		},
+		Inputs: map[string]interface{}{"debug": true},
+	}
+
+	ee, cfg, err := NewExpressionEnvironment(config, run, fixture)
+	assert.NoError(t, err)
+	assert.Equal(t, "step", cfg.Context)
+	interpreter := exprparser.NewInterpeter(ee, cfg)
+
+	tables := []struct {
+		in  string
+		out interface{}
+	}{
+		{"github.event_name", "push"},
+		{"github.event.ref", "refs/heads/main"},
+		{"github.workflow", "test-workflow"},
+		{"matrix.os", "ubuntu-latest"},
+		{"needs.build.outputs.version", "1.0.0"},
+		{"steps.compile.outputs.binary", "act"},
+		{"inputs.debug", true},
+		{"secrets.TOKEN", "secret"},
+		{"env.ACT", "true"},
+		{"job.status", "failure"},
+		{"success()", false},
+		{"greet(deploy.region)", "hello eu"},
+		{"vars.region", "eu-west-1"},
+	}
+	for _, table := range tables {
+		table := table
+		t.Run(table.in, func(t *testing.T) {
+			out, err := interpreter.Evaluate(table.in, false)
+			assert.NoError(t, err)
+			assert.Equal(t, table.out, out)
+		})
+	}
+
+	config.StrictExpressions = true
+	ee, cfg, err = NewExpressionEnvironment(config, run, fixture)
+	assert.NoError(t, err)
+	interpreter = exprparser.NewInterpeter(ee, cfg)
+	for _, table := range tables {
+		_, err := interpreter.Evaluate(table.in, false)
+		assert.NoError(t, err, table.in)
+	}
+	_, err = interpreter.Evaluate("runner.os && strategy.job-index", false)
+	assert.NoError(t, err)
+	_, err = interpreter.Evaluate("github.evnet.ref", false)
+	assert.EqualError(t, err, "Property 'evnet' is not defined on 'github'")
+}
+
+func TestTraceExpressions(t *testing.T) {
+	rc := createRunContext(t)
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+	ctx := common.WithLogger(context.Background(), logger)
+
+	ee := rc.NewExpressionEvaluator(context.Background())
+	assert.Same(t, ee.(expressionEvaluator).interpreter, traceExpressions(ctx, rc.Config, ee).(expressionEvaluator).interpreter)
+
+	rc.Config.TraceExpressions = true
+	ok, err := EvalBool(traceExpressions(ctx, rc.Config, ee), "matrix.os == 'linux' && env.missing")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	messages := make([]string, 0)
+	for _, entry := range hook.AllEntries() {
+		assert.Equal(t, logrus.DebugLevel, entry.Level)
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{
+		"Evaluated expression:",
+		"  success() && ((matrix.os == 'linux') && env.missing) => null",
+		"    success() => true",
+		"    (matrix.os == 'linux') && env.missing => null",
+		"      matrix.os == 'linux' => true",
+		"        matrix.os => 'Linux'",
+		"      env.missing => null",
+	}, messages)
+}
+
 func TestEvaluateStepContext(t *testing.T) {
 	rc := createRunContext(t)
 
 	sc := &StepContext{
 		RunContext: rc,
 	}
-	ee := sc.NewExpressionEvaluator()
+	ee := sc.NewExpressionEvaluator(context.Background())
 
 	tables := []struct {
 		in      string
@@ -212,7 +346,7 @@ func TestInterpolate(t *testing.T) {
 			},
 		},
 	}
-	ee := rc.NewExpressionEvaluator()
+	ee := rc.NewExpressionEvaluator(context.Background())
 	tables := []struct {
 		in  string
 		out string
@@ -363,3 +497,58 @@ func TestRewriteSubExpressionForceFormat(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkInterpolateMatrix(b *testing.B) {
+	var matrix []map[string]interface{}
+	for _, os := range []string{"ubuntu-latest", "windows-latest", "macos-latest", "ubuntu-18.04"} {
+		for _, node := range []int{12, 14, 16, 18} {
+			matrix = append(matrix, map[string]interface{}{"os": os, "node": node})
+		}
+	}
+	steps := make([]*model.Step, 10)
+	for i := range steps {
+		steps[i] = &model.Step{ID: fmt.Sprintf("step%d", i)}
+	}
+	inputs := []string{
+		"${{ matrix.os }}-node-${{ matrix.node }}",
+		"echo ${{ github.event_name }} ${{ github.sha }} on ${{ runner.os }}",
+		"${{ format('{0}/{1}', github.repository, github.ref) }}",
+		"${{ steps.step0.outputs.version || 'latest' }}",
+	}
+	config := &Config{Workdir: ".", EventName: "push"}
+	run := &model.Run{
+		JobID: "test",
+		Workflow: &model.Workflow{
+			Name: "matrix",
+			Jobs: map[string]*model.Job{"test": {}},
+		},
+	}
+
+	// the missing git remote is logged for every job
+	level := logrus.GetLevel()
+	logrus.SetLevel(logrus.ErrorLevel)
+	defer logrus.SetLevel(level)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, combination := range matrix {
+			rc := &RunContext{
+				Config: config,
+				Run:    run,
+				Matrix: combination,
+				Env:    map[string]string{},
+				StepResults: map[string]*model.StepResult{
+					"step0": {Outputs: map[string]string{"version": "1.0.0"}},
+				},
+			}
+			for _, step := range steps {
+				rc.CurrentStep = step.ID
+				sc := &StepContext{RunContext: rc, Step: step}
+				ee := sc.NewExpressionEvaluator(context.Background())
+				for _, in := range inputs {
+					ee.Interpolate(in)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/term"
+)
+
+// GroupedOutput buffers the logs of every job and writes them in one block when the job completes, or when a step
+// completes if flushSteps is set, so the lines of jobs running in parallel don't interleave.
+// Set it as LogWriter and Hooks of the runner config. On a terminal it shows a status line for every running job below the logs.
+type GroupedOutput struct {
+	NoopHooks
+	mu          sync.Mutex
+	out         io.Writer
+	flushSteps  bool
+	status      bool
+	jobs        []*groupedJob // jobs in the order they wrote their first line
+	statusLines int           // number of status lines currently drawn
+	stop        chan struct{}
+	logOutput   io.Writer // output of the standard logger while the status lines are drawn
+}
+
+type groupedJob struct {
+	name      string
+	buf       bytes.Buffer
+	running   bool
+	step      string
+	startTime time.Time
+}
+
+// groupedJobWriter is the LogWriter of a single job
+type groupedJobWriter struct {
+	output *GroupedOutput
+	name   string
+}
+
+// NewGroupedOutput creates a grouped output writing to out, the status lines are drawn if out is a terminal
+func NewGroupedOutput(out io.Writer, flushSteps bool) *GroupedOutput {
+	return &GroupedOutput{
+		out:        out,
+		flushSteps: flushSteps,
+		status:     checkIfTerminal(out),
+	}
+}
+
+// Write writes logs that don't belong to a job directly
+func (g *GroupedOutput) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(p), g.writeBlock(p)
+}
+
+func (w *groupedJobWriter) Write(p []byte) (int, error) {
+	w.output.mu.Lock()
+	defer w.output.mu.Unlock()
+	return w.output.job(w.name).buf.Write(p)
+}
+
+// JobWriter returns the writer buffering the logs of a job
+func (g *GroupedOutput) JobWriter(jobName string) io.Writer {
+	return &groupedJobWriter{output: g, name: jobName}
+}
+
+// job returns the job with the name, the lock has to be held
+func (g *GroupedOutput) job(name string) *groupedJob {
+	for _, job := range g.jobs {
+		if job.name == name {
+			return job
+		}
+	}
+	job := &groupedJob{name: name}
+	g.jobs = append(g.jobs, job)
+	return job
+}
+
+// flush writes the buffered logs of a job, the lock has to be held
+func (g *GroupedOutput) flush(job *groupedJob) error {
+	if job.buf.Len() == 0 {
+		return nil
+	}
+	defer job.buf.Reset()
+	return g.writeBlock(job.buf.Bytes())
+}
+
+// writeBlock writes p above the status lines, the lock has to be held
+func (g *GroupedOutput) writeBlock(p []byte) error {
+	g.clearStatus()
+	if _, err := g.out.Write(p); err != nil {
+		return err
+	}
+	g.drawStatus()
+	return nil
+}
+
+// clearStatus removes the status lines, the lock has to be held
+func (g *GroupedOutput) clearStatus() {
+	for ; g.statusLines > 0; g.statusLines-- {
+		fmt.Fprint(g.out, "\x1b[1A\x1b[2K")
+	}
+}
+
+// drawStatus draws a line with the current step and elapsed time of every running job, the lock has to be held
+func (g *GroupedOutput) drawStatus() {
+	if !g.status {
+		return
+	}
+	width := 0
+	if f, ok := g.out.(*os.File); ok {
+		width, _, _ = term.GetSize(int(f.Fd()))
+	}
+	for _, job := range g.jobs {
+		if !job.running {
+			continue
+		}
+		line := fmt.Sprintf("\u23F3  [%s] %s (%s)", job.name, job.step, time.Since(job.startTime).Round(time.Second))
+		if runes := []rune(line); width > 0 && len(runes) >= width {
+			line = string(runes[:width-1])
+		}
+		fmt.Fprintf(g.out, "\x1b[2K%s\n", line)
+		g.statusLines++
+	}
+}
+
+// redraw updates the elapsed time in the status lines every second until the plan completed
+func (g *GroupedOutput) redraw(stop chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			g.mu.Lock()
+			g.clearStatus()
+			g.drawStatus()
+			g.mu.Unlock()
+		}
+	}
+}
+
+// PlanStarted starts to redraw the status lines, the logs of act itself are written above them until the plan completed
+func (g *GroupedOutput) PlanStarted(ctx context.Context, event *PlanEvent) {
+	g.mu.Lock()
+	started := g.status && g.stop == nil
+	if started {
+		g.stop = make(chan struct{})
+		go g.redraw(g.stop)
+	}
+	g.mu.Unlock()
+
+	// the standard logger writes while holding its lock, so its output is changed without holding g.mu
+	if started {
+		g.logOutput = log.StandardLogger().Out
+		log.SetOutput(g)
+	}
+}
+
+// PlanCompleted writes the logs of all jobs that are still buffered and removes the status lines
+func (g *GroupedOutput) PlanCompleted(ctx context.Context, event *PlanEvent) {
+	g.mu.Lock()
+	if g.stop != nil {
+		close(g.stop)
+		g.stop = nil
+	}
+	for _, job := range g.jobs {
+		job.running = false
+		_ = g.flush(job)
+	}
+	g.jobs = nil
+	g.clearStatus()
+	g.mu.Unlock()
+
+	if g.logOutput != nil {
+		log.SetOutput(g.logOutput)
+		g.logOutput = nil
+	}
+}
+
+// JobStarted adds the status line of the job
+func (g *GroupedOutput) JobStarted(ctx context.Context, event *JobEvent) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	job := g.job(event.Name)
+	job.running = true
+	job.step = "Set up job"
+	job.startTime = time.Now()
+	g.clearStatus()
+	g.drawStatus()
+}
+
+// JobCompleted writes the logs of the job and removes its status line
+func (g *GroupedOutput) JobCompleted(ctx context.Context, event *JobEvent) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	job := g.job(event.Name)
+	job.running = false
+	_ = g.flush(job)
+}
+
+// StepStarted shows the step in the status line of the job
+func (g *GroupedOutput) StepStarted(ctx context.Context, event *StepEvent) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.job(event.Job.Name).step = event.Step.String()
+	g.clearStatus()
+	g.drawStatus()
+}
+
+// StepCompleted writes the logs of the job if the output is flushed after every step
+func (g *GroupedOutput) StepCompleted(ctx context.Context, event *StepEvent) {
+	if !g.flushSteps {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_ = g.flush(g.job(event.Job.Name))
+}
+
+// jobLogWriter returns the writer for the logs of a job, the one of the job if out is a JobLogWriter
+func jobLogWriter(out io.Writer, jobName string) io.Writer {
+	if w, ok := out.(JobLogWriter); ok {
+		return w.JobWriter(strings.TrimRight(jobName, " "))
+	}
+	return out
+}
@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestGroupedOutput(t *testing.T) {
+	ctx := context.Background()
+	out := &bytes.Buffer{}
+	g := NewGroupedOutput(out, false)
+	assert.False(t, g.status)
+
+	workflow := &model.Workflow{Name: "ci"}
+	build := &JobEvent{Run: &model.Run{Workflow: workflow, JobID: "build"}, Name: "ci/build"}
+	test := &JobEvent{Run: &model.Run{Workflow: workflow, JobID: "test"}, Name: "ci/test"}
+	step := &model.Step{ID: "0", Run: "make"}
+
+	g.PlanStarted(ctx, &PlanEvent{})
+	buildWriter := jobLogWriter(g, "ci/build  ")
+	testWriter := jobLogWriter(g, "ci/test   ")
+	g.JobStarted(ctx, build)
+	g.JobStarted(ctx, test)
+	for i := 0; i < 2; i++ {
+		fmt.Fprintf(buildWriter, "[ci/build] %d\n", i)
+		fmt.Fprintf(testWriter, "[ci/test] %d\n", i)
+	}
+	g.StepCompleted(ctx, &StepEvent{Job: test, Step: step, Result: &model.StepResult{}})
+	assert.Empty(t, out.String())
+
+	g.JobCompleted(ctx, test)
+	assert.Equal(t, "[ci/test] 0\n[ci/test] 1\n", out.String())
+
+	fmt.Fprintf(buildWriter, "[ci/build] 2\n")
+	g.PlanCompleted(ctx, &PlanEvent{})
+	assert.Equal(t, "[ci/test] 0\n[ci/test] 1\n[ci/build] 0\n[ci/build] 1\n[ci/build] 2\n", out.String())
+}
+
+func TestGroupedOutputSteps(t *testing.T) {
+	ctx := context.Background()
+	out := &bytes.Buffer{}
+	g := NewGroupedOutput(out, true)
+
+	job := &JobEvent{Run: &model.Run{Workflow: &model.Workflow{Name: "ci"}, JobID: "build"}, Name: "ci/build"}
+	w := jobLogWriter(g, "ci/build")
+	g.JobStarted(ctx, job)
+	fmt.Fprintf(w, "step 1\n")
+	g.StepCompleted(ctx, &StepEvent{Job: job, Step: &model.Step{ID: "0"}, Result: &model.StepResult{}})
+	assert.Equal(t, "step 1\n", out.String())
+	fmt.Fprintf(w, "step 2\n")
+	assert.Equal(t, "step 1\n", out.String())
+}
+
+func TestGroupedOutputStatus(t *testing.T) {
+	ctx := context.Background()
+	out := &bytes.Buffer{}
+	g := NewGroupedOutput(out, false)
+	g.status = true
+
+	job := &JobEvent{Run: &model.Run{Workflow: &model.Workflow{Name: "ci"}, JobID: "build"}, Name: "ci/build"}
+	w := jobLogWriter(g, "ci/build")
+	g.JobStarted(ctx, job)
+	g.StepStarted(ctx, &StepEvent{Job: job, Step: &model.Step{ID: "0", Run: "make"}})
+	assert.True(t, strings.HasSuffix(out.String(), "\x1b[2K\u23F3  [ci/build] make (0s)\n"))
+
+	// the status line is removed before the logs are written and drawn again below them
+	out.Reset()
+	fmt.Fprintf(w, "done\n")
+	g.StepCompleted(ctx, &StepEvent{Job: job, Step: &model.Step{ID: "0", Run: "make"}, Result: &model.StepResult{}})
+	g.JobCompleted(ctx, job)
+	assert.Equal(t, "\x1b[1A\x1b[2Kdone\n", out.String())
+}
+
+func TestGroupedOutputStandardLogger(t *testing.T) {
+	ctx := context.Background()
+	out := &bytes.Buffer{}
+	g := NewGroupedOutput(out, false)
+	g.status = true
+	logOutput := &bytes.Buffer{}
+	defer log.SetOutput(log.StandardLogger().Out)
+	log.SetOutput(logOutput)
+
+	job := &JobEvent{Run: &model.Run{Workflow: &model.Workflow{Name: "ci"}, JobID: "build"}, Name: "ci/build"}
+	g.PlanStarted(ctx, &PlanEvent{})
+	g.JobStarted(ctx, job)
+
+	// warnings of act are written above the status line instead of breaking it
+	out.Reset()
+	log.Warn("careful")
+	assert.True(t, strings.HasPrefix(out.String(), "\x1b[1A\x1b[2K"))
+	assert.Contains(t, out.String(), "careful")
+	assert.Contains(t, out.String(), "\u23F3  [ci/build] Set up job")
+
+	g.PlanCompleted(ctx, &PlanEvent{})
+	log.Warn("after")
+	assert.Contains(t, logOutput.String(), "after")
+	assert.NotContains(t, logOutput.String(), "careful")
+}
@@ -0,0 +1,168 @@
+package runner
+
+import (
+	"context"
+	"time"
+
+	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/model"
+)
+
+// Hooks receives the lifecycle events of the plans executed by a runner.
+// The jobs of a stage run in parallel, so implementations have to be safe for concurrent use.
+// Values in the events are not masked.
+type Hooks interface {
+	PlanStarted(ctx context.Context, event *PlanEvent)
+	PlanCompleted(ctx context.Context, event *PlanEvent)
+	JobStarted(ctx context.Context, event *JobEvent)
+	JobCompleted(ctx context.Context, event *JobEvent)
+	StepStarted(ctx context.Context, event *StepEvent)
+	StepCompleted(ctx context.Context, event *StepEvent)
+	Command(ctx context.Context, event *CommandEvent)
+}
+
+// OutputHooks is implemented by Hooks that also receive the lines printed by the containers of the jobs
+type OutputHooks interface {
+	Output(ctx context.Context, event *OutputEvent)
+}
+
+// PlanEvent describes a plan that started or completed
+type PlanEvent struct {
+	Plan     *model.Plan
+	Err      error         // error of the plan, nil when the plan started
+	Duration time.Duration // time the plan took, zero when the plan started
+}
+
+// JobEvent describes a job that started or completed
+type JobEvent struct {
+	Run      *model.Run
+	Name     string // name of the job, includes the index of the matrix
+	Matrix   map[string]interface{}
+	Result   string            // "success", "failure" or "cancelled", empty when the job started
+	Outputs  map[string]string // outputs of the job, empty when the job started
+	Duration time.Duration     // time the job took, zero when the job started
+
+	Cancel    context.CancelFunc  // cancels the job, the job fails with the error of its context
+	Container container.Container // container of the job, nil until it was started
+}
+
+// StepEvent describes a step that started or completed
+type StepEvent struct {
+	Job       *JobEvent
+	Step      *model.Step
+	Composite string            // id of the step running the composite action the step belongs to, empty for steps of the job
+	Result    *model.StepResult // result of the step including its outputs, nil when the step started
+	Duration  time.Duration     // time the step took, zero when the step started
+}
+
+// CommandEvent describes a workflow command printed by a step, e.g. `set-output` or `error`
+type CommandEvent struct {
+	Job        *JobEvent
+	StepID     string
+	Command    string
+	Parameters map[string]string
+	Value      string
+}
+
+// OutputEvent describes a line printed by a container of a job, lines containing workflow commands are passed to Command instead
+type OutputEvent struct {
+	Job    *JobEvent
+	StepID string // id of the current step, empty while the job container starts
+	Line   string
+}
+
+// NoopHooks ignores all events, embed it to implement only some of the callbacks of Hooks
+type NoopHooks struct{}
+
+// PlanStarted is called before the first job of a plan runs
+func (NoopHooks) PlanStarted(ctx context.Context, event *PlanEvent) {}
+
+// PlanCompleted is called after all jobs of a plan completed
+func (NoopHooks) PlanCompleted(ctx context.Context, event *PlanEvent) {}
+
+// JobStarted is called before the container of a job starts, once for every combination of the matrix
+func (NoopHooks) JobStarted(ctx context.Context, event *JobEvent) {}
+
+// JobCompleted is called after the container of a job was closed
+func (NoopHooks) JobCompleted(ctx context.Context, event *JobEvent) {}
+
+// StepStarted is called before the condition of a step is evaluated
+func (NoopHooks) StepStarted(ctx context.Context, event *StepEvent) {}
+
+// StepCompleted is called after a step ran or was skipped
+func (NoopHooks) StepCompleted(ctx context.Context, event *StepEvent) {}
+
+// Command is called for every workflow command a step prints
+func (NoopHooks) Command(ctx context.Context, event *CommandEvent) {}
+
+type multiHooks []Hooks
+
+// MultiHooks passes the events to all hooks in order
+func MultiHooks(hooks ...Hooks) Hooks {
+	return multiHooks(hooks)
+}
+
+func (m multiHooks) PlanStarted(ctx context.Context, event *PlanEvent) {
+	for _, h := range m {
+		h.PlanStarted(ctx, event)
+	}
+}
+
+func (m multiHooks) PlanCompleted(ctx context.Context, event *PlanEvent) {
+	for _, h := range m {
+		h.PlanCompleted(ctx, event)
+	}
+}
+
+func (m multiHooks) JobStarted(ctx context.Context, event *JobEvent) {
+	for _, h := range m {
+		h.JobStarted(ctx, event)
+	}
+}
+
+func (m multiHooks) JobCompleted(ctx context.Context, event *JobEvent) {
+	for _, h := range m {
+		h.JobCompleted(ctx, event)
+	}
+}
+
+func (m multiHooks) StepStarted(ctx context.Context, event *StepEvent) {
+	for _, h := range m {
+		h.StepStarted(ctx, event)
+	}
+}
+
+func (m multiHooks) StepCompleted(ctx context.Context, event *StepEvent) {
+	for _, h := range m {
+		h.StepCompleted(ctx, event)
+	}
+}
+
+func (m multiHooks) Command(ctx context.Context, event *CommandEvent) {
+	for _, h := range m {
+		h.Command(ctx, event)
+	}
+}
+
+func (m multiHooks) Output(ctx context.Context, event *OutputEvent) {
+	for _, h := range m {
+		if h, ok := h.(OutputHooks); ok {
+			h.Output(ctx, event)
+		}
+	}
+}
+
+func (config *Config) hooks() Hooks {
+	if config == nil || config.Hooks == nil {
+		return NoopHooks{}
+	}
+	return config.Hooks
+}
+
+func copyStringMap(outputs map[string]string) map[string]string {
+	rtn := make(map[string]string, len(outputs))
+	for k, v := range outputs {
+		rtn[k] = v
+	}
+	return rtn
+}
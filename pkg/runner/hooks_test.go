@@ -0,0 +1,105 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+type hooksRecorder struct {
+	NoopHooks
+	name   string
+	events *[]string
+}
+
+func (h *hooksRecorder) PlanStarted(ctx context.Context, event *PlanEvent) {
+	*h.events = append(*h.events, h.name+" planStarted")
+}
+
+func (h *hooksRecorder) StepStarted(ctx context.Context, event *StepEvent) {
+	*h.events = append(*h.events, fmt.Sprintf("%s stepStarted %s %s", h.name, event.Job.Name, event.Step.ID))
+}
+
+func (h *hooksRecorder) StepCompleted(ctx context.Context, event *StepEvent) {
+	*h.events = append(*h.events, fmt.Sprintf("%s stepCompleted %s %s %s", h.name, event.Job.Name, event.Step.ID, event.Result.Conclusion))
+}
+
+func (h *hooksRecorder) Command(ctx context.Context, event *CommandEvent) {
+	*h.events = append(*h.events, fmt.Sprintf("%s command %s %s %s %v %s", h.name, event.Job.Name, event.StepID, event.Command, event.Parameters, event.Value))
+}
+
+func TestMultiHooks(t *testing.T) {
+	events := make([]string, 0)
+	hooks := MultiHooks(&hooksRecorder{name: "a", events: &events}, &hooksRecorder{name: "b", events: &events})
+
+	hooks.PlanStarted(context.Background(), &PlanEvent{})
+	hooks.PlanCompleted(context.Background(), &PlanEvent{})
+
+	assert.Equal(t, []string{"a planStarted", "b planStarted"}, events)
+}
+
+type outputRecorder struct {
+	hooksRecorder
+}
+
+func (h *outputRecorder) Output(ctx context.Context, event *OutputEvent) {
+	*h.events = append(*h.events, fmt.Sprintf("%s output %s", h.name, event.Line))
+}
+
+func TestMultiHooksOutput(t *testing.T) {
+	events := make([]string, 0)
+	hooks := MultiHooks(&hooksRecorder{name: "a", events: &events}, &outputRecorder{hooksRecorder{name: "b", events: &events}})
+
+	outputHooks, ok := hooks.(OutputHooks)
+	assert.True(t, ok)
+	outputHooks.Output(context.Background(), &OutputEvent{Line: "hello"})
+
+	assert.Equal(t, []string{"b output hello"}, events)
+}
+
+func TestStepHooks(t *testing.T) {
+	events := make([]string, 0)
+	rc := createIfTestRunContext(map[string]*model.Job{
+		"job1": createJob(t, `runs-on: ubuntu-latest`, ""),
+	})
+	rc.Name = "job1"
+	rc.StepResults = make(map[string]*model.StepResult)
+	rc.Config.Hooks = &hooksRecorder{name: "a", events: &events}
+
+	step := &model.Step{ID: "skipped", If: yaml.Node{Kind: yaml.ScalarNode, Value: "false"}}
+	err := rc.newStepExecutor(step)(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"a stepStarted test-workflow/job1 skipped",
+		"a stepCompleted test-workflow/job1 skipped skipped",
+	}, events)
+}
+
+func TestCommandHooks(t *testing.T) {
+	events := make([]string, 0)
+	rc := createIfTestRunContext(map[string]*model.Job{
+		"job1": createJob(t, `runs-on: ubuntu-latest`, ""),
+	})
+	rc.Name = "job1"
+	rc.CurrentStep = "my-step"
+	rc.StepResults = map[string]*model.StepResult{
+		"my-step": {Outputs: make(map[string]string)},
+	}
+	rc.Config.Hooks = &hooksRecorder{name: "a", events: &events}
+
+	handler := rc.commandHandler(context.Background())
+	handler("::set-output name=x::value%25\n")
+	handler("::error file=app.js,line=1::broken\n")
+	handler("not a command\n")
+
+	assert.Equal(t, []string{
+		"a command test-workflow/job1 my-step set-output map[name:x] value%",
+		"a command test-workflow/job1 my-step error map[file:app.js line:1] broken",
+	}, events)
+}
@@ -3,6 +3,7 @@ package runner
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/nektos/act/pkg/common"
 	"github.com/nektos/act/pkg/model"
@@ -11,32 +12,59 @@ import (
 type jobInfo interface {
 	matrix() map[string]interface{}
 	steps() []*model.Step
+	startEnvironment(ctx context.Context) (bool, error)
 	startContainer() common.Executor
 	stopContainer() common.Executor
 	closeContainer() common.Executor
 	newStepExecutor(step *model.Step) common.Executor
 	interpolateOutputs() common.Executor
+	resetCurrentStep()
 	result(result string)
+	hooks() Hooks
+	jobEvent() *JobEvent
 }
 
 func newJobExecutor(info jobInfo) common.Executor {
 	steps := make([]common.Executor, 0)
+	var startTime time.Time
+	result := "failure"
 
 	steps = append(steps, func(ctx context.Context) error {
+		startTime = time.Now()
+		event := info.jobEvent()
+		event.Outputs = nil
+		info.hooks().JobStarted(ctx, event)
 		if len(info.matrix()) > 0 {
 			common.Logger(ctx).Infof("\U0001F9EA  Matrix: %v", info.matrix())
 		}
 		return nil
 	})
 
-	steps = append(steps, info.startContainer())
+	// a rejected deployment fails the job like a failed step, without starting its container or running its steps
+	approved := false
+	steps = append(steps, func(ctx context.Context) error {
+		var err error
+		if approved, err = info.startEnvironment(ctx); err != nil {
+			common.Logger(ctx).Errorf("%v", err)
+			common.SetJobError(ctx, err)
+		} else if !approved {
+			common.SetJobError(ctx, errDeploymentRejected)
+		}
+		return nil
+	})
+	isApproved := func(ctx context.Context) bool {
+		return approved
+	}
+
+	steps = append(steps, info.startContainer().If(isApproved))
 
 	for i, step := range info.steps() {
+		step := step
 		if step.ID == "" {
 			step.ID = fmt.Sprintf("%d", i)
 		}
 		stepExec := info.newStepExecutor(step)
-		steps = append(steps, func(ctx context.Context) error {
+		steps = append(steps, common.Executor(func(ctx context.Context) error {
 			stepName := step.String()
 			return (func(ctx context.Context) error {
 				err := stepExec(ctx)
@@ -48,24 +76,47 @@ func newJobExecutor(info jobInfo) common.Executor {
 					common.SetJobError(ctx, ctx.Err())
 				}
 				return nil
-			})(withStepLogger(ctx, stepName))
-		})
+			})(withStepLogger(ctx, step.ID, stepName))
+		}).If(isApproved))
 	}
 
+	// the output of stopping the containers and of the outputs of the job belongs to no step, also if the job was cancelled
+	resetCurrentStep := func(ctx context.Context) error {
+		info.resetCurrentStep()
+		return nil
+	}
+	steps = append(steps, resetCurrentStep)
+
 	steps = append(steps, func(ctx context.Context) error {
 		jobError := common.JobError(ctx)
 		if jobError != nil {
 			info.result("failure")
+			common.Logger(ctx).WithField("jobResult", "failure").Infof("\U0001F3C1  Job failed")
 		} else {
 			err := info.stopContainer()(ctx)
 			if err != nil {
 				return err
 			}
 			info.result("success")
+			result = "success"
+			common.Logger(ctx).WithField("jobResult", "success").Infof("\U0001F3C1  Job succeeded")
 		}
 
 		return nil
 	})
 
-	return common.NewPipelineExecutor(steps...).Finally(info.interpolateOutputs()).Finally(info.closeContainer())
+	return common.NewPipelineExecutor(steps...).Finally(resetCurrentStep).Finally(info.interpolateOutputs()).Finally(info.closeContainer()).Finally(func(ctx context.Context) error {
+		// the pipeline stops at a cancelled context before the job result was set
+		if ctx.Err() != nil && result != "success" {
+			result = "cancelled"
+			info.result(result)
+			common.Logger(ctx).WithField("jobResult", result).Infof("\U0001F3C1  Job cancelled")
+		}
+		event := info.jobEvent()
+		event.Result = result
+		event.Outputs = copyStringMap(event.Outputs)
+		event.Duration = time.Since(startTime)
+		info.hooks().JobCompleted(ctx, event)
+		return nil
+	})
 }
@@ -26,6 +26,12 @@ func (jpm *jobInfoMock) steps() []*model.Step {
 	return args.Get(0).([]*model.Step)
 }
 
+func (jpm *jobInfoMock) startEnvironment(ctx context.Context) (bool, error) {
+	args := jpm.Called()
+
+	return args.Bool(0), args.Error(1)
+}
+
 func (jpm *jobInfoMock) startContainer() common.Executor {
 	args := jpm.Called()
 
@@ -56,10 +62,39 @@ func (jpm *jobInfoMock) interpolateOutputs() common.Executor {
 	return args.Get(0).(func(context.Context) error)
 }
 
+func (jpm *jobInfoMock) resetCurrentStep() {
+	jpm.Called()
+}
+
 func (jpm *jobInfoMock) result(result string) {
 	jpm.Called(result)
 }
 
+func (jpm *jobInfoMock) hooks() Hooks {
+	args := jpm.Called()
+
+	return args.Get(0).(Hooks)
+}
+
+func (jpm *jobInfoMock) jobEvent() *JobEvent {
+	args := jpm.Called()
+
+	return args.Get(0).(func() *JobEvent)()
+}
+
+type jobHooksMock struct {
+	NoopHooks
+	events []string
+}
+
+func (h *jobHooksMock) JobStarted(ctx context.Context, event *JobEvent) {
+	h.events = append(h.events, "jobStarted "+event.Name)
+}
+
+func (h *jobHooksMock) JobCompleted(ctx context.Context, event *JobEvent) {
+	h.events = append(h.events, fmt.Sprintf("jobCompleted %s %s %v", event.Name, event.Result, event.Outputs))
+}
+
 func TestNewJobExecutor(t *testing.T) {
 	table := []struct {
 		name          string
@@ -67,13 +102,16 @@ func TestNewJobExecutor(t *testing.T) {
 		executedSteps []string
 		result        string
 		hasError      bool
+		rejected      bool
 	}{
 		{
 			name:  "zeroSteps",
 			steps: []*model.Step{},
 			executedSteps: []string{
 				"startContainer",
+				"resetCurrentStep",
 				"stopContainer",
+				"resetCurrentStep",
 				"interpolateOutputs",
 				"closeContainer",
 			},
@@ -88,7 +126,9 @@ func TestNewJobExecutor(t *testing.T) {
 			executedSteps: []string{
 				"startContainer",
 				"step1",
+				"resetCurrentStep",
 				"stopContainer",
+				"resetCurrentStep",
 				"interpolateOutputs",
 				"closeContainer",
 			},
@@ -103,6 +143,8 @@ func TestNewJobExecutor(t *testing.T) {
 			executedSteps: []string{
 				"startContainer",
 				"step1",
+				"resetCurrentStep",
+				"resetCurrentStep",
 				"interpolateOutputs",
 				"closeContainer",
 			},
@@ -120,13 +162,30 @@ func TestNewJobExecutor(t *testing.T) {
 				"startContainer",
 				"step1",
 				"step2",
+				"resetCurrentStep",
 				"stopContainer",
+				"resetCurrentStep",
 				"interpolateOutputs",
 				"closeContainer",
 			},
 			result:   "success",
 			hasError: false,
 		},
+		{
+			name: "rejectedDeployment",
+			steps: []*model.Step{{
+				ID: "1",
+			}},
+			executedSteps: []string{
+				"resetCurrentStep",
+				"resetCurrentStep",
+				"interpolateOutputs",
+				"closeContainer",
+			},
+			result:   "failure",
+			hasError: false,
+			rejected: true,
+		},
 	}
 
 	for _, tt := range table {
@@ -135,6 +194,7 @@ func TestNewJobExecutor(t *testing.T) {
 			jpm := &jobInfoMock{}
 			executorOrder := make([]string, 0)
 
+			jpm.On("startEnvironment").Return(!tt.rejected, nil)
 			jpm.On("startContainer").Return(func(ctx context.Context) error {
 				executorOrder = append(executorOrder, "startContainer")
 				return nil
@@ -167,16 +227,61 @@ func TestNewJobExecutor(t *testing.T) {
 			})
 
 			jpm.On("result", tt.result)
+			jpm.On("resetCurrentStep").Run(func(args mock.Arguments) {
+				executorOrder = append(executorOrder, "resetCurrentStep")
+			})
 
 			jpm.On("closeContainer").Return(func(ctx context.Context) error {
 				executorOrder = append(executorOrder, "closeContainer")
 				return nil
 			})
 
+			hooks := &jobHooksMock{}
+			jpm.On("hooks").Return(hooks)
+			jpm.On("jobEvent").Return(func() *JobEvent {
+				return &JobEvent{Name: "job", Outputs: map[string]string{"out": "value"}}
+			})
+
 			executor := newJobExecutor(jpm)
 			err := executor(ctx)
 			assert.Nil(t, err)
+			if tt.rejected {
+				assert.Equal(t, errDeploymentRejected, common.JobError(ctx))
+			}
 			assert.Equal(t, tt.executedSteps, executorOrder)
+			assert.Equal(t, []string{"jobStarted job", "jobCompleted job " + tt.result + " map[out:value]"}, hooks.events)
 		})
 	}
 }
+
+func TestNewJobExecutorCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(common.WithJobErrorContainer(context.Background()))
+	defer cancel()
+	jpm := &jobInfoMock{}
+	step := &model.Step{ID: "1"}
+
+	noop := func(ctx context.Context) error { return nil }
+	jpm.On("startEnvironment").Return(true, nil)
+	jpm.On("startContainer").Return(noop)
+	jpm.On("steps").Return([]*model.Step{step})
+	jpm.On("newStepExecutor", step).Return(func(ctx context.Context) error {
+		cancel()
+		return nil
+	})
+	jpm.On("interpolateOutputs").Return(noop)
+	jpm.On("matrix").Return(map[string]interface{}{})
+	jpm.On("closeContainer").Return(noop)
+	jpm.On("result", "cancelled")
+	jpm.On("resetCurrentStep")
+
+	hooks := &jobHooksMock{}
+	jpm.On("hooks").Return(hooks)
+	jpm.On("jobEvent").Return(func() *JobEvent {
+		return &JobEvent{Name: "job"}
+	})
+
+	err := newJobExecutor(jpm)(ctx)
+	assert.Equal(t, context.Canceled, err)
+	jpm.AssertCalled(t, "result", "cancelled")
+	assert.Equal(t, []string{"jobStarted job", "jobCompleted job cancelled map[]"}, hooks.events)
+}
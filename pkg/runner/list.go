@@ -0,0 +1,23 @@
+package runner
+
+import (
+	"github.com/nektos/act/pkg/model"
+)
+
+// ListJobs describes the jobs of the plan like model.ListJobs, with the image of every job resolved the same way as
+// when the job runs, for the first combination of its matrix
+func ListJobs(config *Config, plan *model.Plan) ([]*model.ListedJob, error) {
+	eventJSON, err := readEventJSON(config)
+	if err != nil {
+		return nil, err
+	}
+	runner := &runnerImpl{config: config, eventJSON: eventJSON}
+
+	return model.ListJobs(plan, func(run *model.Run) string {
+		matrix := map[string]interface{}{}
+		if matrixes := run.Job().GetMatrixes(); len(matrixes) > 0 {
+			matrix = matrixes[0]
+		}
+		return runner.newRunContext(run, matrix).platformImage()
+	}), nil
+}
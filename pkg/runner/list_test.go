@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestListJobs(t *testing.T) {
+	workflow, err := model.ReadWorkflow(strings.NewReader(`
+name: list
+on: push
+jobs:
+  container:
+    runs-on: ubuntu-latest
+    container: ${{ format('alpine:{0}', github.event.version) }}
+    steps:
+      - run: true
+  matrix:
+    strategy:
+      matrix:
+        os: [Ubuntu-Latest, windows-latest]
+    runs-on: ${{ matrix.os }}
+    steps:
+      - run: true
+  missing:
+    runs-on: windows-latest
+    steps:
+      - run: true
+`))
+	assert.NoError(t, err)
+	workflow.File = "list.yml"
+	plan := &model.Plan{Stages: []*model.Stage{{Runs: []*model.Run{
+		{Workflow: workflow, JobID: "container"},
+		{Workflow: workflow, JobID: "matrix"},
+		{Workflow: workflow, JobID: "missing"},
+	}}}}
+
+	jobs, err := ListJobs(&Config{
+		EventName: "push",
+		EventJSON: `{"version": "3"}`,
+		Platforms: map[string]string{"ubuntu-latest": "node:16-buster-slim"},
+	}, plan)
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 3)
+	assert.Equal(t, "alpine:3", jobs[0].Image)
+	assert.Equal(t, "node:16-buster-slim", jobs[1].Image)
+	assert.Equal(t, "", jobs[2].Image)
+	assert.True(t, jobs[2].Skipped)
+}
@@ -0,0 +1,187 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+)
+
+// maxLogFileNameLen is the maximum length of the name of a step in the name of its log file
+const maxLogFileNameLen = 64
+
+var logFileNamePattern = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// logFileName replaces all characters that are not safe in file names
+func logFileName(name string) string {
+	return trimToLen(strings.Trim(logFileNamePattern.ReplaceAllString(name, "_"), "_"), maxLogFileNameLen)
+}
+
+// jobLogDir is a logrus hook writing the logs of a job to `job.log` and the output of every step to `<n>_<step>.log` in a directory
+type jobLogDir struct {
+	dir    string
+	steps  func() []*model.Step
+	level  logrus.Level // level of the console, less severe entries are written if they are output of a container only
+	masker entryProcessor
+
+	mu      sync.Mutex
+	jobLog  *os.File
+	stepLog *os.File
+	stepID  string          // id of the current step, empty between steps
+	created map[string]bool // names of the files written by this job, a step that runs again appends to its log
+}
+
+// attachLogDir writes the logs of the job in ctx to the log directory of the config, the returned function closes the log files
+func (rc *RunContext) attachLogDir(ctx context.Context) func() {
+	entry, ok := common.Logger(ctx).(*logrus.Entry)
+	if rc.Config.LogDir == "" || !ok {
+		return func() {}
+	}
+
+	d := &jobLogDir{
+		dir:    filepath.Join(rc.Config.LogDir, logFileName(rc.Run.Workflow.Name), logFileName(rc.Name)),
+		steps:  rc.steps,
+		level:  entry.Logger.GetLevel(),
+		masker: valueMasker(rc.Config.InsecureSecrets, rc.Config.Secrets, &rc.Masks),
+	}
+
+	// the output of the containers is logged at debug level with --quiet, but has to end up in the files anyway
+	entry.Logger.SetFormatter(&levelFormatter{formatter: entry.Logger.Formatter, level: d.level})
+	entry.Logger.SetLevel(logrus.DebugLevel)
+	entry.Logger.AddHook(d)
+
+	return func() {
+		if err := d.Close(); err != nil {
+			common.Logger(ctx).Errorf("Failed to close log files: %v", err)
+		}
+	}
+}
+
+// Levels returns all levels, the entries are filtered in Fire
+func (d *jobLogDir) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire writes the entry to the job log and output of containers to the log of the current step
+func (d *jobLogDir) Fire(entry *logrus.Entry) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry = d.masker(entry)
+	raw := entry.Data["raw_output"] == true
+	line := strings.TrimSuffix(entry.Message, "\n")
+
+	// output of the job container has no step, it belongs to the step that logged last
+	if stepID, ok := entry.Data["stepID"].(string); ok {
+		if err := d.setStep(stepID); err != nil {
+			return err
+		}
+	} else if !raw {
+		if err := d.setStep(""); err != nil {
+			return err
+		}
+	}
+
+	if !raw {
+		if entry.Level > d.level {
+			return nil
+		}
+		return d.write(&d.jobLog, "job.log", line)
+	}
+
+	if err := d.write(&d.jobLog, "job.log", "  | "+line); err != nil {
+		return err
+	}
+	if d.stepID == "" {
+		return nil
+	}
+	return d.write(&d.stepLog, d.stepLogName(), line)
+}
+
+// setStep closes the log of the previous step when another step starts, the lock has to be held
+func (d *jobLogDir) setStep(stepID string) error {
+	if stepID == d.stepID {
+		return nil
+	}
+	d.stepID = stepID
+	if d.stepLog == nil {
+		return nil
+	}
+	err := d.stepLog.Close()
+	d.stepLog = nil
+	return err
+}
+
+// stepLogName returns the name of the log of the current step, numbered by its position in the job
+func (d *jobLogDir) stepLogName() string {
+	for i, step := range d.steps() {
+		if step.ID == d.stepID {
+			return fmt.Sprintf("%d_%s.log", i+1, logFileName(step.String()))
+		}
+	}
+	return fmt.Sprintf("%s.log", logFileName(d.stepID))
+}
+
+// write appends a line to the file, which is created on first use and replaces the file of a previous run of act, e.g.
+// the retry of a step paused at a failure appends to the log of its first attempt. The lock has to be held.
+func (d *jobLogDir) write(f **os.File, name string, line string) error {
+	if *f == nil {
+		if err := os.MkdirAll(d.dir, 0755); err != nil {
+			return err
+		}
+		flag := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		if !d.created[name] {
+			flag |= os.O_TRUNC
+		}
+		file, err := os.OpenFile(filepath.Join(d.dir, name), flag, 0644)
+		if err != nil {
+			return err
+		}
+		if d.created == nil {
+			d.created = make(map[string]bool)
+		}
+		d.created[name] = true
+		*f = file
+	}
+	_, err := fmt.Fprintln(*f, line)
+	return err
+}
+
+// Close closes the open log files
+func (d *jobLogDir) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var err error
+	for _, f := range []**os.File{&d.stepLog, &d.jobLog} {
+		if *f == nil {
+			continue
+		}
+		if closeErr := (*f).Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+		*f = nil
+	}
+	return err
+}
+
+// levelFormatter drops the entries less severe than level, so the console keeps its level while the log files get everything
+type levelFormatter struct {
+	formatter logrus.Formatter
+	level     logrus.Level
+}
+
+func (f *levelFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if entry.Level > f.level {
+		return nil, nil
+	}
+	return f.formatter.Format(entry)
+}
@@ -0,0 +1,102 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestLogFileName(t *testing.T) {
+	assert.Equal(t, "actions_checkout_v2", logFileName("actions/checkout@v2"))
+	assert.Equal(t, "Run_make_test", logFileName("Run make test"))
+	assert.Equal(t, "test-1", logFileName("test-1"))
+	assert.Len(t, logFileName(strings.Repeat("a", 100)), maxLogFileNameLen)
+}
+
+func TestLogDir(t *testing.T) {
+	level := logrus.GetLevel()
+	defer logrus.SetLevel(level)
+	logrus.SetLevel(logrus.InfoLevel)
+
+	dir := t.TempDir()
+	console := &bytes.Buffer{}
+	rc := &RunContext{
+		Name: "test-1",
+		Run: &model.Run{
+			JobID: "test",
+			Workflow: &model.Workflow{
+				Name: "CI",
+				Jobs: map[string]*model.Job{
+					"test": {Steps: []*model.Step{
+						{ID: "checkout", Uses: "actions/checkout@v2"},
+						{ID: "1", Run: "make test"},
+					}},
+				},
+			},
+		},
+		Config: &Config{
+			LogDir:    dir,
+			LogWriter: console,
+			Secrets:   map[string]string{"TOKEN": "s3cr3t"},
+		},
+	}
+
+	ctx := WithJobLogger(context.Background(), "test", "CI/test-1", rc.Config, &rc.Masks)
+	closeLogDir := rc.attachLogDir(ctx)
+
+	common.Logger(ctx).Infof("Start image=node:16")
+	common.Logger(ctx).WithField("raw_output", true).Infof("pulling image\n")
+
+	checkout := withStepLogger(ctx, "checkout", "actions/checkout@v2")
+	common.Logger(checkout).Infof("Run actions/checkout@v2")
+	common.Logger(checkout).Debugf("Cloning repository")
+	// output of the job container does not contain the step
+	common.Logger(ctx).WithField("raw_output", true).Debugf("checked out s3cr3t\n")
+
+	test := withStepLogger(ctx, "1", "make test")
+	common.Logger(test).Infof("Run make test")
+	rc.Masks = append(rc.Masks, "hidden")
+	common.Logger(ctx).WithField("raw_output", true).Debugf("ok hidden\n")
+	// a retry of the step after a pause appends to its log
+	common.Logger(ctx).Infof("Retrying make test")
+	common.Logger(test).Infof("Run make test")
+	common.Logger(ctx).WithField("raw_output", true).Debugf("ok again\n")
+	common.Logger(ctx).Infof("Job succeeded")
+	closeLogDir()
+
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(dir, "CI", "test-1", name))
+		assert.NoError(t, err)
+		return string(b)
+	}
+	assert.Equal(t, "Start image=node:16\n  | pulling image\nRun actions/checkout@v2\n  | checked out ***\nRun make test\n  | ok ***\nRetrying make test\nRun make test\n  | ok again\nJob succeeded\n", read("job.log"))
+	assert.Equal(t, "checked out ***\n", read("1_actions_checkout_v2.log"))
+	assert.Equal(t, "ok ***\nok again\n", read("2_make_test.log"))
+
+	// the console keeps its level
+	assert.NotContains(t, console.String(), "Cloning repository")
+	assert.NotContains(t, console.String(), "ok ***")
+	assert.Contains(t, console.String(), "Run make test")
+}
+
+func TestLogDirReplacesPreviousRun(t *testing.T) {
+	dir := t.TempDir()
+	for _, line := range []string{"first run", "second run"} {
+		d := &jobLogDir{dir: dir}
+		assert.NoError(t, d.write(&d.jobLog, "job.log", line))
+		assert.NoError(t, d.Close())
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "job.log"))
+	assert.NoError(t, err)
+	assert.Equal(t, "second run\n", string(b))
+}
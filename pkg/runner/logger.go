@@ -8,6 +8,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/nektos/act/pkg/common"
 
@@ -38,7 +39,7 @@ func init() {
 }
 
 // WithJobLogger attaches a new logger to context that is aware of steps
-func WithJobLogger(ctx context.Context, jobName string, config *Config, masks *[]string) context.Context {
+func WithJobLogger(ctx context.Context, jobID string, jobName string, config *Config, masks *[]string) context.Context {
 	mux.Lock()
 	defer mux.Unlock()
 
@@ -57,17 +58,44 @@ func WithJobLogger(ctx context.Context, jobName string, config *Config, masks *[
 
 	nextColor++
 
+	var out io.Writer = os.Stdout
+	if config.LogWriter != nil {
+		out = config.LogWriter
+	}
+
 	logger := logrus.New()
 	logger.SetFormatter(formatter)
-	logger.SetOutput(os.Stdout)
+	logger.SetOutput(jobLogWriter(out, jobName))
 	logger.SetLevel(logrus.GetLevel())
-	rtn := logger.WithFields(logrus.Fields{"job": jobName, "dryrun": common.Dryrun(ctx)})
+	rtn := logger.WithFields(logrus.Fields{"job": jobName, "jobID": jobID, "dryrun": common.Dryrun(ctx)})
 
 	return common.WithLogger(ctx, rtn)
 }
 
-func withStepLogger(ctx context.Context, stepName string) context.Context {
-	rtn := common.Logger(ctx).WithFields(logrus.Fields{"step": stepName})
+// JobLogWriter is implemented by LogWriters that keep the logs of the jobs apart, see Config.LogWriter
+type JobLogWriter interface {
+	io.Writer
+	// JobWriter returns the writer for the logs of the job with the name, it includes the index of the matrix
+	JobWriter(jobName string) io.Writer
+}
+
+// LogEntry is an entry written by the logger of a job with Config.JSONLogger
+type LogEntry struct {
+	Time        time.Time         `json:"time"`
+	Level       string            `json:"level"`
+	Message     string            `json:"msg"`
+	Job         string            `json:"job"` // name of the job padded to the length of the longest name
+	JobID       string            `json:"jobID"`
+	Step        string            `json:"step"`
+	StepID      string            `json:"stepID"`
+	RawOutput   bool              `json:"raw_output"` // line printed by a container
+	StepResult  string            `json:"stepResult"` // conclusion of the step, set on the entry logging its result
+	StepOutcome string            `json:"stepOutcome"`
+	StepOutputs map[string]string `json:"stepOutputs"`
+}
+
+func withStepLogger(ctx context.Context, stepID string, stepName string) context.Context {
+	rtn := common.Logger(ctx).WithFields(logrus.Fields{"step": stepName, "stepID": stepID})
 	return common.WithLogger(ctx, rtn)
 }
 
@@ -79,15 +107,34 @@ func valueMasker(insecureSecrets bool, secrets map[string]string, masks *[]strin
 			return entry
 		}
 
-		for _, v := range secrets {
-			if v != "" {
-				entry.Message = strings.ReplaceAll(entry.Message, v, "***")
+		mask := func(s string) string {
+			for _, v := range secrets {
+				if v != "" {
+					s = strings.ReplaceAll(s, v, "***")
+				}
+			}
+
+			for _, v := range *masks {
+				if v != "" {
+					s = strings.ReplaceAll(s, v, "***")
+				}
 			}
+			return s
 		}
 
-		for _, v := range *masks {
-			if v != "" {
-				entry.Message = strings.ReplaceAll(entry.Message, v, "***")
+		entry.Message = mask(entry.Message)
+
+		// fields like the outputs of a step end up in the json log as well
+		for k, v := range entry.Data {
+			switch value := v.(type) {
+			case string:
+				entry.Data[k] = mask(value)
+			case map[string]string:
+				masked := make(map[string]string, len(value))
+				for name, s := range value {
+					masked[name] = mask(s)
+				}
+				entry.Data[k] = masked
 			}
 		}
 
@@ -95,6 +142,30 @@ func valueMasker(insecureSecrets bool, secrets map[string]string, masks *[]strin
 	}
 }
 
+// secretMasker replaces secrets and values masked with `add-mask` in the values passed to hooks, it is not safe for concurrent use
+type secretMasker struct {
+	secrets map[string]string
+	masks   []string
+}
+
+func (m *secretMasker) add(value string) {
+	m.masks = append(m.masks, value)
+}
+
+func (m *secretMasker) mask(s string) string {
+	for _, v := range m.secrets {
+		if v != "" {
+			s = strings.ReplaceAll(s, v, "***")
+		}
+	}
+	for _, v := range m.masks {
+		if v != "" {
+			s = strings.ReplaceAll(s, v, "***")
+		}
+	}
+	return s
+}
+
 type jobLogFormatter struct {
 	color  int
 	masker entryProcessor
@@ -159,6 +230,10 @@ func checkIfTerminal(w io.Writer) bool {
 	switch v := w.(type) {
 	case *os.File:
 		return term.IsTerminal(int(v.Fd()))
+	case *GroupedOutput:
+		return checkIfTerminal(v.out)
+	case *groupedJobWriter:
+		return checkIfTerminal(v.output.out)
 	default:
 		return false
 	}
@@ -0,0 +1,29 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueMasker(t *testing.T) {
+	masks := []string{"masked"}
+	masker := valueMasker(false, map[string]string{"TOKEN": "s3cr3t"}, &masks)
+
+	outputs := map[string]string{"token": "s3cr3t", "value": "masked value"}
+	entry := masker(&logrus.Entry{
+		Message: "s3cr3t and masked",
+		Data: logrus.Fields{
+			"step":        "echo s3cr3t",
+			"stepOutputs": outputs,
+			"dryrun":      false,
+		},
+	})
+
+	assert.Equal(t, "*** and ***", entry.Message)
+	assert.Equal(t, "echo ***", entry.Data["step"])
+	assert.Equal(t, map[string]string{"token": "***", "value": "*** value"}, entry.Data["stepOutputs"])
+	assert.Equal(t, false, entry.Data["dryrun"])
+	assert.Equal(t, "s3cr3t", outputs["token"], "the logged map must not be modified")
+}
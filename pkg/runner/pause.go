@@ -0,0 +1,283 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/model"
+)
+
+type pauseChoice int
+
+const (
+	pauseContinue pauseChoice = iota
+	pauseRetry
+	pauseSkip
+	pauseAbort
+)
+
+// errPauseAborted is returned by a step the user aborted the job at, so the steps running a composite action don't pause again
+var errPauseAborted = errors.New("aborted by the user")
+
+// pauser pauses jobs at failed steps, or before steps, to let the user inspect the job container in an interactive shell.
+// The input is read by a single goroutine once the first job pauses, it is passed to the open shell or the prompt.
+type pauser struct {
+	mu          sync.Mutex // only one job pauses at a time
+	in          io.Reader
+	out         io.Writer
+	interactive bool
+
+	once  sync.Once
+	input chan []byte
+	buf   []byte // input read but not yet consumed by a prompt
+
+	warning sync.Once
+}
+
+// stdinPauser pauses jobs on the terminal act runs in
+var stdinPauser = newPauser(os.Stdin, os.Stdout)
+
+func newPauser(in *os.File, out io.Writer) *pauser {
+	return &pauser{
+		in:          in,
+		out:         out,
+		interactive: term.IsTerminal(int(in.Fd())),
+	}
+}
+
+// readInput starts to read the input, the channel is closed at the end of the input
+func (p *pauser) readInput() {
+	p.once.Do(func() {
+		p.input = make(chan []byte)
+		go func() {
+			defer close(p.input)
+			for {
+				buf := make([]byte, 256)
+				n, err := p.in.Read(buf)
+				if n > 0 {
+					p.input <- buf[:n]
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+	})
+}
+
+// readLine reads a line of the input, the lock has to be held
+func (p *pauser) readLine(ctx context.Context) (string, error) {
+	p.readInput()
+	for {
+		if i := bytes.IndexByte(p.buf, '\n'); i >= 0 {
+			line := string(p.buf[:i])
+			p.buf = p.buf[i+1:]
+			return strings.TrimSpace(line), nil
+		}
+		select {
+		case b, ok := <-p.input:
+			if !ok {
+				return "", io.EOF
+			}
+			p.buf = append(p.buf, b...)
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// shellInput returns a reader passing the input to a shell until the returned function is called, the lock has to be held
+func (p *pauser) shellInput() (io.Reader, func()) {
+	p.readInput()
+	r, w := io.Pipe()
+	pending := p.buf
+	p.buf = nil
+	done := make(chan struct{})
+	go func() {
+		defer w.Close()
+		if len(pending) > 0 {
+			if _, err := w.Write(pending); err != nil {
+				return
+			}
+		}
+		for {
+			select {
+			case b, ok := <-p.input:
+				if !ok {
+					return
+				}
+				if _, err := w.Write(b); err != nil {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return r, func() {
+		close(done)
+		// unblocks a write of input the shell did not read anymore
+		r.Close()
+	}
+}
+
+// pauseOption is an answer to the prompt of a paused job, it can be given by its first letter
+type pauseOption struct {
+	answer string
+	choice pauseChoice
+}
+
+// shellOption opens a shell and asks again once it exits
+var shellOption = pauseOption{answer: "open a shell", choice: -1}
+
+// prompt asks for one of the options until the user picks one, the lock has to be held
+func (p *pauser) prompt(ctx context.Context, options []pauseOption) (pauseOption, error) {
+	answers := make([]string, 0, len(options))
+	for _, option := range options {
+		answers = append(answers, fmt.Sprintf("[%s]%s", option.answer[:1], option.answer[1:]))
+	}
+	question := strings.Join(answers, ", ")
+	for {
+		fmt.Fprintf(p.out, "%s? ", question)
+		line, err := p.readLine(ctx)
+		if err != nil {
+			return pauseOption{}, err
+		}
+		line = strings.ToLower(line)
+		for _, option := range options {
+			if line != "" && (line == option.answer || line == option.answer[:1]) {
+				return option, nil
+			}
+		}
+	}
+}
+
+// printStep prints the environment of the step and how it runs in the job container
+func printStep(out io.Writer, sc *StepContext, masker *secretMasker) {
+	keys := make([]string, 0, len(sc.Env))
+	for k := range sc.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Fprintln(out, "Environment:")
+	for _, k := range keys {
+		fmt.Fprintf(out, "  %s=%s\n", k, masker.mask(sc.Env[k]))
+	}
+	if sc.ScriptPath != "" {
+		fmt.Fprintf(out, "Script: %s\n", sc.ScriptPath)
+	}
+	if len(sc.Cmd) > 0 {
+		fmt.Fprintf(out, "Command: %s\n", masker.mask(strings.Join(sc.Cmd, " ")))
+	}
+	if sc.Step.WorkingDirectory != "" {
+		fmt.Fprintf(out, "Working directory: %s\n", sc.Step.WorkingDirectory)
+	}
+}
+
+// shell opens an interactive shell in the job container with the environment of the step, the lock has to be held
+func (p *pauser) shell(ctx context.Context, sc *StepContext) error {
+	rc := sc.RunContext
+	if rc.JobContainer == nil {
+		return fmt.Errorf("the job has no container")
+	}
+	if f, ok := p.in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		state, err := term.MakeRaw(int(f.Fd()))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = term.Restore(int(f.Fd()), state)
+		}()
+	}
+	stdin, stop := p.shellInput()
+	defer stop()
+	return rc.JobContainer.Shell(container.DefaultShell, sc.Env, "", sc.Step.WorkingDirectory, stdin, p.out)(ctx)
+}
+
+// pause prints the step and lets the user open shells in the job container until one of the options is picked
+func (p *pauser) pause(ctx context.Context, sc *StepContext, reason string, options ...pauseOption) (pauseChoice, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rc := sc.RunContext
+	masker := &secretMasker{}
+	if !rc.Config.InsecureSecrets {
+		masker = &secretMasker{secrets: rc.Config.Secrets, masks: rc.Masks}
+	}
+
+	fmt.Fprintf(p.out, "\n\u23F8  [%s] %s\n", strings.TrimSpace(rc.String()), reason)
+	printStep(p.out, sc, masker)
+
+	options = append([]pauseOption{shellOption}, options...)
+	for {
+		option, err := p.prompt(ctx, options)
+		if err != nil {
+			return pauseAbort, err
+		}
+		if option != shellOption {
+			return option.choice, nil
+		}
+		fmt.Fprintln(p.out, "Opening a shell in the job container, exit it to return")
+		if err := p.shell(ctx, sc); err != nil {
+			fmt.Fprintf(p.out, "Failed to open a shell: %v\n", err)
+		}
+	}
+}
+
+// warnNotInteractive warns once that jobs don't pause
+func (p *pauser) warnNotInteractive(ctx context.Context) {
+	p.warning.Do(func() {
+		common.Logger(ctx).Warnf("Not pausing the job because the input is not a terminal")
+	})
+}
+
+// pauseBefore pauses the job before the step if its id is one of the steps to pause before
+func (rc *RunContext) pauseBefore(ctx context.Context, sc *StepContext) (pauseChoice, error) {
+	for _, id := range rc.Config.PauseBefore {
+		if id != sc.Step.ID {
+			continue
+		}
+		if !stdinPauser.interactive {
+			stdinPauser.warnNotInteractive(ctx)
+			return pauseContinue, nil
+		}
+		return stdinPauser.pause(ctx, sc, fmt.Sprintf("Paused before %s", sc.Step),
+			pauseOption{"continue", pauseContinue},
+			pauseOption{"skip", pauseSkip},
+			pauseOption{"abort", pauseAbort},
+		)
+	}
+	return pauseContinue, nil
+}
+
+// pauseOnFailure pauses the job after the step failed, the job continues with the failure unless the user picks another option
+func (rc *RunContext) pauseOnFailure(ctx context.Context, sc *StepContext, stepErr error) (pauseChoice, error) {
+	if !stdinPauser.interactive {
+		stdinPauser.warnNotInteractive(ctx)
+		return pauseContinue, nil
+	}
+	return stdinPauser.pause(ctx, sc, fmt.Sprintf("%s failed: %v", sc.Step, stepErr),
+		pauseOption{"retry", pauseRetry},
+		pauseOption{"skip", pauseSkip},
+		pauseOption{"abort", pauseAbort},
+	)
+}
+
+// skipStep records the step as skipped by the user
+func (rc *RunContext) skipStep(ctx context.Context, sc *StepContext) {
+	result := rc.StepResults[rc.CurrentStep]
+	result.Outcome = model.StepStatusSkipped
+	result.Conclusion = model.StepStatusSkipped
+	common.Logger(ctx).WithFields(stepResultFields(result)).Infof("  \u23ED  Skipped %s", sc.Step)
+}
@@ -0,0 +1,115 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestPausePrompt(t *testing.T) {
+	out := &bytes.Buffer{}
+	p := &pauser{in: strings.NewReader("x\n\nRetry\ns\n"), out: out, interactive: true}
+	options := []pauseOption{{"retry", pauseRetry}, {"skip", pauseSkip}}
+
+	option, err := p.prompt(context.Background(), options)
+	assert.NoError(t, err)
+	assert.Equal(t, pauseRetry, option.choice)
+	assert.Equal(t, strings.Repeat("[r]etry, [s]kip? ", 3), out.String())
+
+	option, err = p.prompt(context.Background(), options)
+	assert.NoError(t, err)
+	assert.Equal(t, pauseSkip, option.choice)
+
+	_, err = p.prompt(context.Background(), options)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestPausePromptCancelled(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	p := &pauser{in: r, out: ioutil.Discard, interactive: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := p.prompt(ctx, []pauseOption{{"abort", pauseAbort}})
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestPauseShellInput(t *testing.T) {
+	r, w := io.Pipe()
+	p := &pauser{in: r, out: ioutil.Discard, interactive: true}
+	p.readInput()
+	p.buf = []byte("ls\n")
+
+	stdin, stop := p.shellInput()
+	go func() {
+		_, _ = w.Write([]byte("exit\n"))
+	}()
+	buf := make([]byte, 5)
+	_, err := io.ReadFull(stdin, buf[:3])
+	assert.NoError(t, err)
+	assert.Equal(t, "ls\n", string(buf[:3]))
+	_, err = io.ReadFull(stdin, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "exit\n", string(buf))
+	stop()
+
+	// the input goes to the prompt again once the shell exited
+	go func() {
+		_, _ = w.Write([]byte("a\n"))
+	}()
+	line, err := p.readLine(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "a", line)
+}
+
+func TestPause(t *testing.T) {
+	rc := &RunContext{
+		Name: "test",
+		Run: &model.Run{
+			Workflow: &model.Workflow{Name: "CI"},
+		},
+		Config: &Config{
+			Secrets: map[string]string{"TOKEN": "s3cr3t"},
+		},
+		Masks: []string{"masked"},
+	}
+	sc := &StepContext{
+		RunContext: rc,
+		Step:       &model.Step{ID: "1", Run: "make test", WorkingDirectory: "src"},
+		Env: map[string]string{
+			"TOKEN": "s3cr3t",
+			"CI":    "true",
+			"VALUE": "a masked value",
+		},
+		Cmd:        []string{"bash", "-e", "/var/run/act/workflow/1.sh"},
+		ScriptPath: "/var/run/act/workflow/1.sh",
+	}
+
+	out := &bytes.Buffer{}
+	p := &pauser{in: strings.NewReader("retry\n"), out: out, interactive: true}
+	choice, err := p.pause(context.Background(), sc, "Run make test failed", pauseOption{"retry", pauseRetry}, pauseOption{"abort", pauseAbort})
+	assert.NoError(t, err)
+	assert.Equal(t, pauseRetry, choice)
+	assert.Equal(t, `
+⏸  [CI/test] Run make test failed
+Environment:
+  CI=true
+  TOKEN=***
+  VALUE=a *** value
+Script: /var/run/act/workflow/1.sh
+Command: bash -e /var/run/act/workflow/1.sh
+Working directory: src
+[o]pen a shell, [r]etry, [a]bort? `, out.String())
+
+	out.Reset()
+	printStep(out, sc, &secretMasker{})
+	assert.Contains(t, out.String(), "TOKEN=s3cr3t\n")
+}
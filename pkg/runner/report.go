@@ -0,0 +1,368 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// maxFailureOutputLines is the number of output lines kept for a failed step
+const maxFailureOutputLines = 100
+
+// Report collects the results of all jobs and steps to write them as JUnit XML or JSON.
+// Secrets and values masked with `add-mask` are replaced with `***`.
+type Report struct {
+	mu     sync.Mutex
+	masker secretMasker
+	jobs   []*ReportJob
+	output map[string][]string // output lines of the running steps by job and step
+}
+
+// ReportJob is a planned job with the runs of its matrix
+type ReportJob struct {
+	Workflow string          `json:"workflow"`
+	JobID    string          `json:"job_id"`
+	Name     string          `json:"name"`
+	Result   string          `json:"result"` // result of the job, "skipped" if it did not run
+	Runs     []*ReportJobRun `json:"runs"`
+}
+
+// ReportJobRun is a run of a job, one for every combination of the matrix
+type ReportJobRun struct {
+	Name       string                 `json:"name"` // name of the job, includes the index of the matrix
+	Matrix     map[string]interface{} `json:"matrix,omitempty"`
+	Result     string                 `json:"result"`
+	Outputs    map[string]string      `json:"outputs,omitempty"`
+	DurationMs int64                  `json:"duration_ms"`
+	Steps      []*ReportStep          `json:"steps"`
+	Output     []string               `json:"output,omitempty"` // output while setting up the job if it failed or was cancelled without a failed step
+}
+
+// ReportStep is the result of a step
+type ReportStep struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Outcome       string            `json:"outcome"`
+	Conclusion    string            `json:"conclusion"`
+	Outputs       map[string]string `json:"outputs,omitempty"`
+	DurationMs    int64             `json:"duration_ms"`
+	SkippedReason string            `json:"skipped_reason,omitempty"`
+	Output        []string          `json:"output,omitempty"` // last lines of the output if the step failed
+}
+
+// NewReport creates an empty report, secrets are masked in all values printed by the workflow
+func NewReport(secrets map[string]string) *Report {
+	return &Report{
+		masker: secretMasker{secrets: secrets},
+		jobs:   make([]*ReportJob, 0),
+		output: make(map[string][]string),
+	}
+}
+
+func outputKey(job string, stepID string) string {
+	return job + "\x00" + stepID
+}
+
+func (r *Report) job(event *JobEvent) *ReportJob {
+	for _, job := range r.jobs {
+		if job.Workflow == event.Run.Workflow.Name && job.JobID == event.Run.JobID {
+			return job
+		}
+	}
+	job := &ReportJob{
+		Workflow: event.Run.Workflow.Name,
+		JobID:    event.Run.JobID,
+		Name:     event.Run.String(),
+		Result:   "skipped",
+		Runs:     make([]*ReportJobRun, 0),
+	}
+	r.jobs = append(r.jobs, job)
+	return job
+}
+
+func (r *Report) jobRun(event *JobEvent) *ReportJobRun {
+	job := r.job(event)
+	for _, run := range job.Runs {
+		if run.Name == event.Name {
+			return run
+		}
+	}
+	run := &ReportJobRun{
+		Name:   event.Name,
+		Matrix: event.Matrix,
+		Steps:  make([]*ReportStep, 0),
+	}
+	job.Runs = append(job.Runs, run)
+	return run
+}
+
+// takeOutput returns the last output lines of a step and forgets them, the lock has to be held
+func (r *Report) takeOutput(job string, stepID string) []string {
+	key := outputKey(job, stepID)
+	lines := r.output[key]
+	delete(r.output, key)
+	if len(lines) > maxFailureOutputLines {
+		lines = lines[len(lines)-maxFailureOutputLines:]
+	}
+	return lines
+}
+
+// PlanStarted adds the planned jobs, jobs that never start are reported as skipped
+func (r *Report) PlanStarted(ctx context.Context, event *PlanEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stage := range event.Plan.Stages {
+		for _, run := range stage.Runs {
+			r.job(&JobEvent{Run: run})
+		}
+	}
+}
+
+// PlanCompleted does nothing
+func (r *Report) PlanCompleted(ctx context.Context, event *PlanEvent) {}
+
+// JobStarted adds a run of a job
+func (r *Report) JobStarted(ctx context.Context, event *JobEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobRun(event)
+}
+
+// JobCompleted records the result of a run of a job
+func (r *Report) JobCompleted(ctx context.Context, event *JobEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run := r.jobRun(event)
+	run.Result = event.Result
+	run.DurationMs = event.Duration.Milliseconds()
+	run.Outputs = make(map[string]string, len(event.Outputs))
+	for k, v := range event.Outputs {
+		run.Outputs[k] = r.masker.mask(v)
+	}
+
+	output := r.takeOutput(event.Name, "")
+	if run.Result != "success" && !run.stepsFailed() {
+		run.Output = output
+	}
+
+	// a job failed if any run of its matrix failed, it was cancelled if a run was cancelled and none failed
+	job := r.job(event)
+	switch {
+	case job.Result == "failure":
+	case job.Result == "cancelled" && run.Result != "failure":
+	default:
+		job.Result = run.Result
+	}
+}
+
+// StepStarted does nothing, steps are added once they completed
+func (r *Report) StepStarted(ctx context.Context, event *StepEvent) {}
+
+// StepCompleted records the result of a step
+func (r *Report) StepCompleted(ctx context.Context, event *StepEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	step := &ReportStep{
+		ID:         event.Step.ID,
+		Name:       event.Step.String(),
+		Outcome:    event.Result.Outcome.String(),
+		Conclusion: event.Result.Conclusion.String(),
+		Outputs:    make(map[string]string, len(event.Result.Outputs)),
+		DurationMs: event.Duration.Milliseconds(),
+	}
+	for k, v := range event.Result.Outputs {
+		step.Outputs[k] = r.masker.mask(v)
+	}
+	output := r.takeOutput(event.Job.Name, event.Step.ID)
+	switch {
+	case step.Conclusion == "skipped":
+		step.SkippedReason = fmt.Sprintf("skipped due to '%s'", event.Step.If.Value)
+	case step.Outcome == "failure":
+		step.Output = output
+	}
+	run := r.jobRun(event.Job)
+	run.Steps = append(run.Steps, step)
+}
+
+// Command masks the values of `add-mask` in all following values
+func (r *Report) Command(ctx context.Context, event *CommandEvent) {
+	if event.Command == "add-mask" {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.masker.add(event.Value)
+	}
+}
+
+// Output keeps the output of the running step to report it if the step fails
+func (r *Report) Output(ctx context.Context, event *OutputEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := outputKey(event.Job.Name, event.StepID)
+	lines := append(r.output[key], r.masker.mask(event.Line))
+	if len(lines) > 2*maxFailureOutputLines {
+		lines = lines[len(lines)-maxFailureOutputLines:]
+	}
+	r.output[key] = lines
+}
+
+// Jobs returns the jobs of the report
+func (r *Report) Jobs() []*ReportJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.jobs
+}
+
+// WriteJSON writes the report as JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]interface{}{
+		"jobs": r.Jobs(),
+	})
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Skipped   *junitMessage `xml:"skipped"`
+	Failure   *junitMessage `xml:"failure"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func junitTime(ms int64) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
+
+// stepsFailed returns true if a step of the run failed
+func (run *ReportJobRun) stepsFailed() bool {
+	for _, step := range run.Steps {
+		if step.Conclusion == "failure" {
+			return true
+		}
+	}
+	return false
+}
+
+func junitStepTestCase(run *ReportJobRun, step *ReportStep) junitTestCase {
+	testCase := junitTestCase{
+		Name:      step.Name,
+		ClassName: run.Name,
+		Time:      junitTime(step.DurationMs),
+	}
+	switch {
+	case step.Conclusion == "skipped":
+		testCase.Skipped = &junitMessage{Message: step.SkippedReason}
+	case step.Conclusion == "failure":
+		testCase.Failure = &junitMessage{Message: "step failed", Body: strings.Join(step.Output, "\n")}
+	case step.Outcome == "failure":
+		// failed with continue-on-error
+		testCase.SystemOut = strings.Join(step.Output, "\n")
+	}
+	return testCase
+}
+
+func junitJobTestSuite(job *ReportJob) junitTestSuite {
+	suite := junitTestSuite{Name: fmt.Sprintf("%s/%s", job.Workflow, job.Name)}
+	if len(job.Runs) == 0 {
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      job.Name,
+			ClassName: suite.Name,
+			Time:      junitTime(0),
+			Skipped:   &junitMessage{Message: "job did not run"},
+		})
+	}
+
+	var ms int64
+	for _, run := range job.Runs {
+		ms += run.DurationMs
+		if run.Result == "failure" && !run.stepsFailed() {
+			// the job failed before or after its steps, e.g. while starting its container
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      "Set up job",
+				ClassName: run.Name,
+				Time:      junitTime(0),
+				Failure:   &junitMessage{Message: "job failed", Body: strings.Join(run.Output, "\n")},
+			})
+		}
+		for _, step := range run.Steps {
+			suite.TestCases = append(suite.TestCases, junitStepTestCase(run, step))
+		}
+		if run.Result == "cancelled" {
+			// the steps that didn't run are missing, so the cancelled job fails like on GitHub
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      "Complete job",
+				ClassName: run.Name,
+				Time:      junitTime(0),
+				Failure:   &junitMessage{Message: "job was cancelled", Body: strings.Join(run.Output, "\n")},
+			})
+		}
+	}
+	suite.Time = junitTime(ms)
+
+	for _, testCase := range suite.TestCases {
+		suite.Tests++
+		if testCase.Failure != nil {
+			suite.Failures++
+		}
+		if testCase.Skipped != nil {
+			suite.Skipped++
+		}
+	}
+	return suite
+}
+
+// WriteJUnit writes the report as JUnit XML with a test suite for every job and a test case for every step of every run of its matrix
+func (r *Report) WriteJUnit(w io.Writer) error {
+	suites := junitTestSuites{}
+	var ms int64
+	for _, job := range r.Jobs() {
+		suite := junitJobTestSuite(job)
+		for _, run := range job.Runs {
+			ms += run.DurationMs
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+	suites.Time = junitTime(ms)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
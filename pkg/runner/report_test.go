@@ -0,0 +1,150 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func newTestReport() *Report {
+	ctx := context.Background()
+	r := NewReport(map[string]string{"TOKEN": "s3cr3t"})
+
+	workflow := &model.Workflow{
+		Name: "ci",
+		Jobs: map[string]*model.Job{
+			"test":   {Name: "Test"},
+			"deploy": {},
+		},
+	}
+	test := &model.Run{Workflow: workflow, JobID: "test"}
+	deploy := &model.Run{Workflow: workflow, JobID: "deploy"}
+	r.PlanStarted(ctx, &PlanEvent{Plan: &model.Plan{Stages: []*model.Stage{
+		{Runs: []*model.Run{test}},
+		{Runs: []*model.Run{deploy}},
+	}}})
+
+	build := &model.Step{ID: "build", Run: "make"}
+	lint := &model.Step{ID: "lint", Run: "make lint", If: yaml.Node{Kind: yaml.ScalarNode, Value: "github.event_name == 'push'"}}
+	unit := &model.Step{ID: "unit", Run: "make test"}
+
+	leg1 := &JobEvent{Run: test, Name: "ci/Test-1", Matrix: map[string]interface{}{"node": 14}}
+	r.JobStarted(ctx, leg1)
+	r.Output(ctx, &OutputEvent{Job: leg1, StepID: "build", Line: "compiling"})
+	r.StepCompleted(ctx, &StepEvent{Job: leg1, Step: build, Duration: 1500 * time.Millisecond, Result: &model.StepResult{
+		Outputs: map[string]string{"version": "1.0"},
+	}})
+	r.StepCompleted(ctx, &StepEvent{Job: leg1, Step: lint, Result: &model.StepResult{
+		Outcome:    model.StepStatusSkipped,
+		Conclusion: model.StepStatusSkipped,
+	}})
+	r.Command(ctx, &CommandEvent{Job: leg1, StepID: "unit", Command: "add-mask", Value: "hidden"})
+	r.Output(ctx, &OutputEvent{Job: leg1, StepID: "unit", Line: "token s3cr3t is hidden"})
+	r.Output(ctx, &OutputEvent{Job: leg1, StepID: "unit", Line: "FAIL <app>"})
+	r.StepCompleted(ctx, &StepEvent{Job: leg1, Step: unit, Duration: 2 * time.Second, Result: &model.StepResult{
+		Outcome:    model.StepStatusFailure,
+		Conclusion: model.StepStatusFailure,
+	}})
+	r.JobCompleted(ctx, &JobEvent{Run: test, Name: leg1.Name, Matrix: leg1.Matrix, Result: "failure", Duration: 4 * time.Second})
+
+	leg2 := &JobEvent{Run: test, Name: "ci/Test-2", Matrix: map[string]interface{}{"node": 16}}
+	r.JobStarted(ctx, leg2)
+	r.Output(ctx, &OutputEvent{Job: leg2, Line: "pull access denied"})
+	r.JobCompleted(ctx, &JobEvent{Run: test, Name: leg2.Name, Matrix: leg2.Matrix, Result: "failure", Duration: time.Second})
+	return r
+}
+
+func TestReportJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, newTestReport().WriteJSON(buf))
+
+	report := struct {
+		Jobs []*ReportJob `json:"jobs"`
+	}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Len(t, report.Jobs, 2)
+
+	test := report.Jobs[0]
+	assert.Equal(t, "Test", test.Name)
+	assert.Equal(t, "failure", test.Result)
+	assert.Len(t, test.Runs, 2)
+	assert.Equal(t, map[string]interface{}{"node": float64(14)}, test.Runs[0].Matrix)
+	assert.Equal(t, int64(4000), test.Runs[0].DurationMs)
+	assert.Equal(t, []*ReportStep{
+		{ID: "build", Name: "make", Outcome: "success", Conclusion: "success", Outputs: map[string]string{"version": "1.0"}, DurationMs: 1500},
+		{ID: "lint", Name: "make lint", Outcome: "skipped", Conclusion: "skipped", SkippedReason: "skipped due to 'github.event_name == 'push''"},
+		{ID: "unit", Name: "make test", Outcome: "failure", Conclusion: "failure", DurationMs: 2000, Output: []string{"token *** is ***", "FAIL <app>"}},
+	}, test.Runs[0].Steps)
+	assert.Equal(t, []string{"pull access denied"}, test.Runs[1].Output)
+
+	assert.Equal(t, "deploy", report.Jobs[1].Name)
+	assert.Equal(t, "skipped", report.Jobs[1].Result)
+	assert.Empty(t, report.Jobs[1].Runs)
+}
+
+func TestReportJUnit(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, newTestReport().WriteJUnit(buf))
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="5" failures="2" skipped="2" time="5.000">
+  <testsuite name="ci/Test" tests="4" failures="2" skipped="1" time="5.000">
+    <testcase name="make" classname="ci/Test-1" time="1.500"></testcase>
+    <testcase name="make lint" classname="ci/Test-1" time="0.000">
+      <skipped message="skipped due to &#39;github.event_name == &#39;push&#39;&#39;"></skipped>
+    </testcase>
+    <testcase name="make test" classname="ci/Test-1" time="2.000">
+      <failure message="step failed">token *** is ***&#xA;FAIL &lt;app&gt;</failure>
+    </testcase>
+    <testcase name="Set up job" classname="ci/Test-2" time="0.000">
+      <failure message="job failed">pull access denied</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="ci/deploy" tests="1" failures="0" skipped="1" time="0.000">
+    <testcase name="deploy" classname="ci/deploy" time="0.000">
+      <skipped message="job did not run"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, buf.String())
+}
+
+func TestReportCancelled(t *testing.T) {
+	ctx := context.Background()
+	r := NewReport(nil)
+
+	workflow := &model.Workflow{Name: "ci", Jobs: map[string]*model.Job{"test": {}}}
+	test := &model.Run{Workflow: workflow, JobID: "test"}
+	r.PlanStarted(ctx, &PlanEvent{Plan: &model.Plan{Stages: []*model.Stage{{Runs: []*model.Run{test}}}}})
+
+	build := &model.Step{ID: "build", Run: "make"}
+	leg1 := &JobEvent{Run: test, Name: "ci/test-1"}
+	r.JobStarted(ctx, leg1)
+	r.StepCompleted(ctx, &StepEvent{Job: leg1, Step: build, Duration: time.Second, Result: &model.StepResult{}})
+	r.JobCompleted(ctx, &JobEvent{Run: test, Name: leg1.Name, Result: "cancelled", Duration: time.Second})
+	leg2 := &JobEvent{Run: test, Name: "ci/test-2"}
+	r.JobStarted(ctx, leg2)
+	r.JobCompleted(ctx, &JobEvent{Run: test, Name: leg2.Name, Result: "success", Duration: time.Second})
+
+	assert.Equal(t, "cancelled", r.Jobs()[0].Result)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, r.WriteJUnit(buf))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="2" failures="1" skipped="0" time="2.000">
+  <testsuite name="ci/test" tests="2" failures="1" skipped="0" time="2.000">
+    <testcase name="make" classname="ci/test-1" time="1.000"></testcase>
+    <testcase name="Complete job" classname="ci/test-1" time="0.000">
+      <failure message="job was cancelled"></failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, buf.String())
+}
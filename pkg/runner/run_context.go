@@ -3,12 +3,14 @@ package runner
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/google/shlex"
 	"github.com/spf13/pflag"
@@ -47,6 +49,13 @@ type RunContext struct {
 	Inputs           map[string]interface{}
 	Parent           *RunContext
 	Masks            []string
+	restoredSteps    map[string]*StepState // steps before Config.FromStep with their state in the previous run
+	restoredEnv      map[string]string     // env written to GITHUB_ENV by the restored steps
+	githubContext    *model.GithubContext  // github context without the fields of the current step, see getGithubContext
+	githubEventJSON  string                // EventJSON githubContext was computed for
+	environment      *model.Environment    // deployment environment of the job with its name interpolated, see startEnvironment
+	workspace        *workspaceCopy        // copy of the job container workspace for hashFiles in the current step
+	cancel           context.CancelFunc
 }
 
 func (rc *RunContext) AddMask(mask string) {
@@ -82,7 +91,11 @@ func (rc *RunContext) GetEnv() map[string]string {
 }
 
 func (rc *RunContext) jobContainerName() string {
-	return createContainerName("act", rc.String())
+	prefix := rc.Config.ContainerNamePrefix
+	if prefix == "" {
+		prefix = "act"
+	}
+	return createContainerName(prefix, rc.String())
 }
 
 // Returns the binds and mounts for the container, resolving paths as appopriate
@@ -118,22 +131,36 @@ func (rc *RunContext) GetBindsAndMounts() ([]string, map[string]string) {
 	return binds, mounts
 }
 
+// outputHandler logs the lines printed by a container and passes them to the hooks
+func (rc *RunContext) outputHandler(ctx context.Context) common.LineHandler {
+	rawLogger := common.Logger(ctx).WithField("raw_output", true)
+	return func(s string) bool {
+		if hooks, ok := rc.Config.Hooks.(OutputHooks); ok {
+			event := &OutputEvent{
+				Job:    rc.jobEvent(),
+				StepID: rc.CurrentStep,
+				Line:   strings.TrimRight(s, "\r\n"),
+			}
+			event.Job.Outputs = nil
+			hooks.Output(ctx, event)
+		}
+		if rc.Config.LogOutput {
+			rawLogger.Infof("%s", s)
+		} else {
+			rawLogger.Debugf("%s", s)
+		}
+		return true
+	}
+}
+
 func (rc *RunContext) startJobContainer() common.Executor {
 	image := rc.platformImage()
 	hostname := rc.hostname()
 
 	return func(ctx context.Context) error {
-		rawLogger := common.Logger(ctx).WithField("raw_output", true)
-		logWriter := common.NewLineWriter(rc.commandHandler(ctx), func(s string) bool {
-			if rc.Config.LogOutput {
-				rawLogger.Infof("%s", s)
-			} else {
-				rawLogger.Debugf("%s", s)
-			}
-			return true
-		})
+		logWriter := common.NewLineWriter(rc.commandHandler(ctx), rc.outputHandler(ctx))
 
-		username, password, err := rc.handleCredentials()
+		username, password, err := rc.handleCredentials(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to handle credentials: %s", err)
 		}
@@ -192,7 +219,7 @@ func (rc *RunContext) startJobContainer() common.Executor {
 			}, &container.FileEntry{
 				Name: "workflow/envs.txt",
 				Mode: 0666,
-				Body: "",
+				Body: envFile(rc.restoredEnv),
 			}, &container.FileEntry{
 				Name: "workflow/paths.txt",
 				Mode: 0666,
@@ -223,6 +250,11 @@ func (rc *RunContext) stopJobContainer() common.Executor {
 
 // ActionCacheDir is for rc
 func (rc *RunContext) ActionCacheDir() string {
+	return cacheDir()
+}
+
+// cacheDir returns the directory act caches actions and the state of runs in
+func cacheDir() string {
 	var xdgCache string
 	var ok bool
 	if xdgCache, ok = os.LookupEnv("XDG_CACHE_HOME"); !ok || xdgCache == "" {
@@ -238,13 +270,14 @@ func (rc *RunContext) ActionCacheDir() string {
 // Interpolate outputs after a job is done
 func (rc *RunContext) interpolateOutputs() common.Executor {
 	return func(ctx context.Context) error {
-		ee := rc.NewExpressionEvaluator()
+		ee := rc.NewExpressionEvaluator(ctx)
 		for k, v := range rc.Run.Job().Outputs {
 			interpolated := ee.Interpolate(v)
 			if v != interpolated {
 				rc.Run.Job().Outputs[k] = interpolated
 			}
 		}
+		rc.recordJob("", rc.Run.Job().Outputs)
 		return nil
 	}
 }
@@ -257,6 +290,10 @@ func (rc *RunContext) stopContainer() common.Executor {
 	return rc.stopJobContainer()
 }
 
+func (rc *RunContext) resetCurrentStep() {
+	rc.CurrentStep = ""
+}
+
 func (rc *RunContext) closeContainer() common.Executor {
 	return func(ctx context.Context) error {
 		if rc.JobContainer != nil {
@@ -272,6 +309,22 @@ func (rc *RunContext) matrix() map[string]interface{} {
 
 func (rc *RunContext) result(result string) {
 	rc.Run.Job().Result = result
+	rc.recordJob(result, nil)
+}
+
+func (rc *RunContext) hooks() Hooks {
+	return rc.Config.hooks()
+}
+
+func (rc *RunContext) jobEvent() *JobEvent {
+	return &JobEvent{
+		Run:       rc.Run,
+		Name:      rc.String(),
+		Matrix:    rc.Matrix,
+		Outputs:   rc.Run.Job().Outputs,
+		Cancel:    rc.cancel,
+		Container: rc.JobContainer,
+	}
 }
 
 func (rc *RunContext) steps() []*model.Step {
@@ -287,8 +340,9 @@ func (rc *RunContext) Executor() common.Executor {
 		}
 
 		if isEnabled {
-			return newJobExecutor(rc)(ctx)
+			return newJobExecutor(rc).Finally(rc.logEnvironmentURL())(ctx)
 		}
+		rc.result("skipped")
 
 		return nil
 	}
@@ -330,49 +384,135 @@ func (rc *RunContext) newStepExecutor(step *model.Step) common.Executor {
 	}
 	return func(ctx context.Context) error {
 		rc.CurrentStep = sc.Step.ID
-		rc.StepResults[rc.CurrentStep] = &model.StepResult{
-			Outcome:    model.StepStatusSuccess,
-			Conclusion: model.StepStatusSuccess,
-			Outputs:    make(map[string]string),
+		rc.resetStepResult()
+		rc.workspace = &workspaceCopy{}
+		defer func() {
+			rc.workspace.remove()
+			rc.workspace = nil
+		}()
+
+		hooks := rc.hooks()
+		event := &StepEvent{Job: rc.jobEvent(), Step: sc.Step}
+		if rc.Parent != nil {
+			event.Composite = rc.Parent.CurrentStep
 		}
-
-		runStep, err := sc.isEnabled(ctx)
-		if err != nil {
-			rc.StepResults[rc.CurrentStep].Conclusion = model.StepStatusFailure
-			rc.StepResults[rc.CurrentStep].Outcome = model.StepStatusFailure
-			return err
+		event.Job.Outputs = nil
+		hooks.StepStarted(ctx, event)
+		startTime := time.Now()
+
+		err := rc.runStep(ctx, sc)
+		for err != nil && rc.Config.PauseOnFailure && ctx.Err() == nil && !errors.Is(err, errPauseAborted) {
+			choice, pauseErr := rc.pauseOnFailure(ctx, sc, err)
+			if pauseErr != nil {
+				common.Logger(ctx).Errorf("Failed to pause the job: %v", pauseErr)
+				break
+			}
+			if choice == pauseRetry {
+				rc.resetStepResult()
+				err = rc.runStep(ctx, sc)
+				continue
+			}
+			if choice == pauseSkip {
+				rc.skipStep(ctx, sc)
+				err = nil
+			} else if choice == pauseAbort {
+				err = fmt.Errorf("%v: %w", err, errPauseAborted)
+			}
+			break
 		}
+		rc.recordStep(ctx, sc)
+
+		result := *rc.StepResults[sc.Step.ID]
+		result.Outputs = copyStringMap(result.Outputs)
+		hooks.StepCompleted(ctx, &StepEvent{
+			Job:       event.Job,
+			Step:      sc.Step,
+			Composite: event.Composite,
+			Result:    &result,
+			Duration:  time.Since(startTime),
+		})
+		return err
+	}
+}
 
-		if !runStep {
-			log.Debugf("Skipping step '%s' due to '%s'", sc.Step.String(), sc.Step.If.Value)
-			rc.StepResults[rc.CurrentStep].Conclusion = model.StepStatusSkipped
-			rc.StepResults[rc.CurrentStep].Outcome = model.StepStatusSkipped
-			return nil
-		}
+// resetStepResult records the current step as successful until it fails
+func (rc *RunContext) resetStepResult() {
+	rc.StepResults[rc.CurrentStep] = &model.StepResult{
+		Outcome:    model.StepStatusSuccess,
+		Conclusion: model.StepStatusSuccess,
+		Outputs:    make(map[string]string),
+	}
+}
 
-		exprEval, err := sc.setupEnv(ctx)
-		if err != nil {
-			return err
-		}
-		rc.ExprEval = exprEval
+// runStep evaluates the condition of the step and executes it, the result is recorded in rc.StepResults
+func (rc *RunContext) runStep(ctx context.Context, sc *StepContext) error {
+	if rc.restoreStep(ctx, sc) || rc.skipUnselectedStep(ctx, sc) {
+		return nil
+	}
+
+	runStep, err := sc.isEnabled(ctx)
+	if err != nil {
+		rc.StepResults[rc.CurrentStep].Conclusion = model.StepStatusFailure
+		rc.StepResults[rc.CurrentStep].Outcome = model.StepStatusFailure
+		return err
+	}
 
-		common.Logger(ctx).Infof("\u2B50  Run %s", sc.Step)
-		err = sc.Executor(ctx)(ctx)
+	if !runStep {
+		rc.StepResults[rc.CurrentStep].Conclusion = model.StepStatusSkipped
+		rc.StepResults[rc.CurrentStep].Outcome = model.StepStatusSkipped
+		common.Logger(ctx).WithFields(stepResultFields(rc.StepResults[rc.CurrentStep])).Debugf("Skipping step '%s' due to '%s'", sc.Step.String(), sc.Step.If.Value)
+		return nil
+	}
+
+	exprEval, err := sc.setupEnv(ctx)
+	if err != nil {
+		return err
+	}
+	rc.ExprEval = exprEval
+
+	switch choice, err := rc.pauseBefore(ctx, sc); {
+	case err != nil || choice == pauseAbort:
+		rc.StepResults[rc.CurrentStep].Conclusion = model.StepStatusFailure
+		rc.StepResults[rc.CurrentStep].Outcome = model.StepStatusFailure
 		if err == nil {
-			common.Logger(ctx).Infof("  \u2705  Success - %s", sc.Step)
+			err = errPauseAborted
+		}
+		return err
+	case choice == pauseSkip:
+		rc.skipStep(ctx, sc)
+		return nil
+	}
+
+	common.Logger(ctx).Infof("\u2B50  Run %s", sc.Step)
+	err = sc.Executor(ctx)(ctx)
+	// the step changed the workspace
+	rc.workspace.remove()
+	result := rc.StepResults[rc.CurrentStep]
+	if err == nil {
+		common.Logger(ctx).WithFields(stepResultFields(result)).Infof("  \u2705  Success - %s", sc.Step)
+	} else {
+		result.Outcome = model.StepStatusFailure
+		if sc.Step.ContinueOnError {
+			result.Conclusion = model.StepStatusSuccess
 		} else {
-			common.Logger(ctx).Errorf("  \u274C  Failure - %s", sc.Step)
+			result.Conclusion = model.StepStatusFailure
+		}
 
-			rc.StepResults[rc.CurrentStep].Outcome = model.StepStatusFailure
-			if sc.Step.ContinueOnError {
-				common.Logger(ctx).Infof("Failed but continue next step")
-				err = nil
-				rc.StepResults[rc.CurrentStep].Conclusion = model.StepStatusSuccess
-			} else {
-				rc.StepResults[rc.CurrentStep].Conclusion = model.StepStatusFailure
-			}
+		common.Logger(ctx).WithFields(stepResultFields(result)).Errorf("  \u274C  Failure - %s", sc.Step)
+		if sc.Step.ContinueOnError {
+			common.Logger(ctx).Infof("Failed but continue next step")
+			err = nil
 		}
-		return err
+	}
+	return err
+}
+
+// stepResultFields returns the log fields describing the result of a step
+func stepResultFields(result *model.StepResult) log.Fields {
+	return log.Fields{
+		"stepResult":  result.Conclusion.String(),
+		"stepOutcome": result.Outcome.String(),
+		"stepOutputs": copyStringMap(result.Outputs),
 	}
 }
 
@@ -424,7 +564,7 @@ func (rc *RunContext) hostname() string {
 func (rc *RunContext) isEnabled(ctx context.Context) (bool, error) {
 	job := rc.Run.Job()
 	l := common.Logger(ctx)
-	runJob, err := EvalBool(rc.ExprEval, job.If.Value)
+	runJob, err := EvalBool(traceExpressions(ctx, rc.Config, rc.ExprEval), job.If.Value)
 	if err != nil {
 		return false, fmt.Errorf("  \u274C  Error in if-expression: \"if: %s\" (%s)", job.If.Value, err)
 	}
@@ -471,7 +611,7 @@ func createContainerName(parts ...string) string {
 			re := regexp.MustCompile("-[0-9]+$")
 			num := re.FindStringSubmatch(part)
 			if len(num) > 0 {
-				name = append(name, trimToLen(pattern.ReplaceAllString(part, "-"), partLen-len(num[0])))
+				name = append(name, trimToLen(pattern.ReplaceAllString(strings.TrimSuffix(part, num[0]), "-"), partLen-len(num[0])))
 				name = append(name, num[0])
 			} else {
 				name = append(name, trimToLen(pattern.ReplaceAllString(part, "-"), partLen))
@@ -508,7 +648,27 @@ func (rc *RunContext) getStepsContext() map[string]*model.StepResult {
 	return rc.StepResults
 }
 
+// getGithubContext returns the github context of the current step. The rest of it needs git lookups and parsing the event,
+// it is computed once per job and again only if the event changes.
 func (rc *RunContext) getGithubContext() *model.GithubContext {
+	if rc.githubContext == nil || rc.githubEventJSON != rc.EventJSON {
+		rc.githubContext = rc.newGithubContext()
+		rc.githubEventJSON = rc.EventJSON
+	}
+	ghc := *rc.githubContext
+	// the token can be a secret of the deployment environment, which is resolved when the job starts
+	if token, ok := lookupFold(rc.getSecretsContext(), "GITHUB_TOKEN"); ok {
+		ghc.Token = token
+	}
+	ghc.Action = rc.CurrentStep
+	ghc.ActionPath = rc.ActionPath
+	ghc.ActionRef = rc.ActionRef
+	ghc.ActionRepository = rc.ActionRepository
+	return &ghc
+}
+
+// newGithubContext computes the github context of the job, the fields of the current step and the token are set by getGithubContext
+func (rc *RunContext) newGithubContext() *model.GithubContext {
 	ghc := &model.GithubContext{
 		Event:            make(map[string]interface{}),
 		EventPath:        ActPath + "/workflow/event.json",
@@ -518,11 +678,6 @@ func (rc *RunContext) getGithubContext() *model.GithubContext {
 		Actor:            rc.Config.Actor,
 		EventName:        rc.Config.EventName,
 		Workspace:        rc.Config.ContainerWorkdir(),
-		Action:           rc.CurrentStep,
-		Token:            rc.Config.Secrets["GITHUB_TOKEN"],
-		ActionPath:       rc.ActionPath,
-		ActionRef:        rc.ActionRef,
-		ActionRepository: rc.ActionRepository,
 		RepositoryOwner:  rc.Config.Env["GITHUB_REPOSITORY_OWNER"],
 		RetentionDays:    rc.Config.Env["GITHUB_RETENTION_DAYS"],
 		RunnerPerflog:    rc.Config.Env["RUNNER_PERFLOG"],
@@ -717,7 +872,7 @@ func (rc *RunContext) localCheckoutPath() (string, bool) {
 	return "", false
 }
 
-func (rc *RunContext) handleCredentials() (username, password string, err error) {
+func (rc *RunContext) handleCredentials(ctx context.Context) (username, password string, err error) {
 	// TODO: remove below 2 lines when we can release act with breaking changes
 	username = rc.Config.Secrets["DOCKER_USERNAME"]
 	password = rc.Config.Secrets["DOCKER_PASSWORD"]
@@ -732,7 +887,7 @@ func (rc *RunContext) handleCredentials() (username, password string, err error)
 		return
 	}
 
-	ee := rc.NewExpressionEvaluator()
+	ee := rc.NewExpressionEvaluator(ctx)
 	if username = ee.Interpolate(container.Credentials["username"]); username == "" {
 		err = fmt.Errorf("failed to interpolate container.credentials.username")
 		return
@@ -63,7 +63,7 @@ func TestRunContext_EvalBool(t *testing.T) {
 			},
 		},
 	}
-	rc.ExprEval = rc.NewExpressionEvaluator()
+	rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
 
 	tables := []struct {
 		in      string
@@ -295,6 +295,21 @@ func TestRunContext_GetBindsAndMounts(t *testing.T) {
 	}
 }
 
+func TestRunContextJobContainerName(t *testing.T) {
+	rc := &RunContext{
+		Name:   "build",
+		Config: &Config{},
+		Run: &model.Run{
+			JobID:    "build",
+			Workflow: &model.Workflow{Name: "CI", Jobs: map[string]*model.Job{"build": {}}},
+		},
+	}
+	assert.Equal(t, "act-CI-build", rc.jobContainerName())
+
+	rc.Config.ContainerNamePrefix = "act-12"
+	assert.Equal(t, "act-12-CI-build", rc.jobContainerName())
+}
+
 func TestGetGitHubContext(t *testing.T) {
 	log.SetLevel(log.DebugLevel)
 
@@ -351,6 +366,40 @@ func TestGetGitHubContext(t *testing.T) {
 	assert.Equal(t, ghc.Token, rc.Config.Secrets["GITHUB_TOKEN"])
 }
 
+func TestGetGitHubContextCache(t *testing.T) {
+	rc := &RunContext{
+		Config: &Config{
+			EventName: "push",
+			Workdir:   ".",
+		},
+		Run: &model.Run{
+			Workflow: &model.Workflow{
+				Name: "GitHubContextTest",
+			},
+		},
+		EventJSON:   `{"ref": "refs/heads/main"}`,
+		CurrentStep: "build",
+	}
+
+	ghc := rc.getGithubContext()
+	assert.Equal(t, "build", ghc.Action)
+	assert.Equal(t, "refs/heads/main", ghc.Ref)
+	cached := rc.githubContext
+
+	rc.CurrentStep = "test"
+	rc.ActionPath = "/var/run/act/actions/test"
+	ghc = rc.getGithubContext()
+	assert.Same(t, cached, rc.githubContext)
+	assert.Equal(t, "test", ghc.Action)
+	assert.Equal(t, "/var/run/act/actions/test", ghc.ActionPath)
+	assert.Equal(t, "", cached.Action)
+
+	rc.EventJSON = `{"ref": "refs/heads/release"}`
+	ghc = rc.getGithubContext()
+	assert.NotSame(t, cached, rc.githubContext)
+	assert.Equal(t, "refs/heads/release", ghc.Ref)
+}
+
 func createIfTestRunContext(jobs map[string]*model.Job) *RunContext {
 	rc := &RunContext{
 		Config: &Config{
@@ -368,7 +417,7 @@ func createIfTestRunContext(jobs map[string]*model.Job) *RunContext {
 			},
 		},
 	}
-	rc.ExprEval = rc.NewExpressionEvaluator()
+	rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
 
 	return rc
 }
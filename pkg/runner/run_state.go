@@ -0,0 +1,274 @@
+package runner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+)
+
+// runStateVersion is the version of the format of the run state file
+const runStateVersion = 1
+
+// RunState is the state of the jobs of a run with the results, outputs and env of their steps.
+// It is written after a run to rerun the failed jobs or resume a job from a step in the next run.
+type RunState struct {
+	mu      sync.Mutex
+	Version int         `json:"version"`
+	Jobs    []*JobState `json:"jobs"`
+}
+
+// JobState is the state of a run of a job, one for every combination of its matrix
+type JobState struct {
+	Workflow string                 `json:"workflow"`
+	JobID    string                 `json:"job_id"`
+	Name     string                 `json:"name"` // name of the run, includes the workflow and the index of the matrix
+	Matrix   map[string]interface{} `json:"matrix,omitempty"`
+	Result   string                 `json:"result"`
+	Outputs  map[string]string      `json:"outputs,omitempty"`
+	Steps    []*StepState           `json:"steps"`
+}
+
+// StepState is the result of a step of a job
+type StepState struct {
+	ID string `json:"id"`
+	model.StepResult
+	Env map[string]string `json:"env,omitempty"` // values written to GITHUB_ENV up to and including the step
+}
+
+// DefaultRunStatePath returns the path of the state of the last run in a working directory, in the cache of act
+func DefaultRunStatePath(workdir string) string {
+	hash := sha256.Sum256([]byte(workdir))
+	return filepath.Join(cacheDir(), "state", fmt.Sprintf("%s-%x.json", logFileName(filepath.Base(workdir)), hash[:8]))
+}
+
+// ReadRunState reads the state of a previous run
+func ReadRunState(path string) (*RunState, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	state := &RunState{}
+	if err := json.Unmarshal(b, state); err != nil {
+		return nil, fmt.Errorf("failed to read the run state %s: %w", path, err)
+	}
+	if state.Version != runStateVersion {
+		return nil, fmt.Errorf("the run state %s has the unsupported version %d", path, state.Version)
+	}
+	return state, nil
+}
+
+// Write writes the state to a file
+func (s *RunState) Write(path string) error {
+	s.mu.Lock()
+	s.Version = runStateVersion
+	b, err := json.MarshalIndent(s, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// the env of the steps can contain secrets
+	return ioutil.WriteFile(path, b, 0600)
+}
+
+// job returns the state of a run of a job, nil if it did not run, the lock has to be held
+func (s *RunState) job(name string) *JobState {
+	for _, job := range s.Jobs {
+		if job.Name == name {
+			return job
+		}
+	}
+	return nil
+}
+
+// succeeded returns the outputs of a run of a job if it succeeded
+func (s *RunState) succeeded(name string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := s.job(name)
+	if job == nil || job.Result != "success" {
+		return nil, false
+	}
+	return copyStringMap(job.Outputs), true
+}
+
+// startJob replaces the state of a run of a job with an empty one, it returns the state of the previous run
+func (s *RunState) startJob(rc *RunContext) *JobState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := &JobState{
+		Workflow: rc.Run.Workflow.Name,
+		JobID:    rc.Run.JobID,
+		Name:     rc.String(),
+		Matrix:   rc.Matrix,
+		Result:   "failure", // until the job completed
+		Steps:    make([]*StepState, 0),
+	}
+	for i, previous := range s.Jobs {
+		if previous.Name == job.Name {
+			s.Jobs[i] = job
+			return previous
+		}
+	}
+	s.Jobs = append(s.Jobs, job)
+	return nil
+}
+
+// update changes the state of a run of a job
+func (s *RunState) update(name string, f func(job *JobState)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job := s.job(name); job != nil {
+		f(job)
+	}
+}
+
+// restoreJob skips a run of a job that succeeded in the previous run if only the failed jobs are rerun,
+// its outputs are restored for the jobs that need it
+func (rc *RunContext) restoreJob() bool {
+	if !rc.Config.RerunFailed || rc.Config.RunState == nil {
+		return false
+	}
+	outputs, ok := rc.Config.RunState.succeeded(rc.String())
+	if !ok {
+		return false
+	}
+	job := rc.Run.Job()
+	if job.Outputs == nil {
+		job.Outputs = make(map[string]string)
+	}
+	for k, v := range outputs {
+		job.Outputs[k] = v
+	}
+	job.Result = "success"
+	log.Infof("\u23E9  Skipping %s, it succeeded in the previous run", rc.String())
+	return true
+}
+
+// startRunState records the state of the job, the results of the steps before Config.FromStep are taken from the previous run
+func (rc *RunContext) startRunState() {
+	state := rc.Config.RunState
+	if state == nil {
+		return
+	}
+	previous := state.startJob(rc)
+	rc.restoredSteps = nil
+	rc.restoredEnv = nil
+	if rc.Config.FromStep == "" {
+		return
+	}
+
+	restored := make(map[string]*StepState)
+	for i, step := range rc.steps() {
+		// the ids of steps without one are assigned once the job starts
+		id := step.ID
+		if id == "" {
+			id = fmt.Sprintf("%d", i)
+		}
+		if MatchStep(rc.Config.FromStep, i, step) {
+			rc.restoredSteps = restored
+			return
+		}
+		var stepState *StepState
+		if previous != nil {
+			for _, s := range previous.Steps {
+				if s.ID == id {
+					stepState = s
+				}
+			}
+		}
+		if stepState == nil {
+			log.Warnf("Running %s from the start, step %s did not run in the previous run", rc.String(), step)
+			rc.restoredEnv = nil
+			return
+		}
+		restored[id] = stepState
+		rc.restoredEnv = stepState.Env
+	}
+	rc.restoredEnv = nil
+}
+
+// restoreStep sets the result of the step from the previous run if the job is resumed from a later step
+func (rc *RunContext) restoreStep(ctx context.Context, sc *StepContext) bool {
+	stepState, ok := rc.restoredSteps[sc.Step.ID]
+	if !ok || rc.Parent != nil {
+		return false
+	}
+	result := rc.StepResults[rc.CurrentStep]
+	result.Outcome = stepState.Outcome
+	result.Conclusion = stepState.Conclusion
+	result.Outputs = copyStringMap(stepState.Outputs)
+	common.Logger(ctx).WithFields(stepResultFields(result)).Infof("\u23E9  Restored %s from the previous run", sc.Step)
+	return true
+}
+
+// recordStep records the result of a step of the job with the env written to GITHUB_ENV so far. The env is only read from
+// the job container if it is reused, --from-step requires that.
+func (rc *RunContext) recordStep(ctx context.Context, sc *StepContext) {
+	if rc.Config.RunState == nil || rc.Parent != nil {
+		return
+	}
+	stepState := &StepState{ID: sc.Step.ID, StepResult: *rc.StepResults[sc.Step.ID]}
+	stepState.Outputs = copyStringMap(stepState.Outputs)
+	if restored, ok := rc.restoredSteps[sc.Step.ID]; ok {
+		stepState.Env = restored.Env
+	} else if rc.JobContainer != nil && rc.Config.ReuseContainers {
+		env := make(map[string]string)
+		if err := rc.JobContainer.UpdateFromEnv(ActPath+"/workflow/envs.txt", &env)(ctx); err != nil {
+			common.Logger(ctx).Warnf("Failed to read the env of step %s: %v", sc.Step, err)
+		}
+		stepState.Env = env
+	}
+	rc.Config.RunState.update(rc.String(), func(job *JobState) {
+		job.Steps = append(job.Steps, stepState)
+	})
+}
+
+// recordJob records the result and outputs of the job
+func (rc *RunContext) recordJob(result string, outputs map[string]string) {
+	if rc.Config.RunState == nil {
+		return
+	}
+	rc.Config.RunState.update(rc.String(), func(job *JobState) {
+		if result != "" {
+			job.Result = result
+		}
+		if outputs != nil {
+			job.Outputs = copyStringMap(outputs)
+		}
+	})
+}
+
+// envFile returns the content of GITHUB_ENV setting the env, multiline values are written with a delimiter
+func envFile(env map[string]string) string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	const delimiter = "ACT_RESTORED_ENV_EOF"
+	b := &strings.Builder{}
+	for _, k := range keys {
+		if strings.Contains(env[k], "\n") {
+			fmt.Fprintf(b, "%s<<%s\n%s\n%s\n", k, delimiter, env[k], delimiter)
+		} else {
+			fmt.Fprintf(b, "%s=%s\n", k, env[k])
+		}
+	}
+	return b.String()
+}
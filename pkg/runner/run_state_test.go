@@ -0,0 +1,195 @@
+package runner
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/model"
+)
+
+func newRunStateTestContext(config *Config) *RunContext {
+	return &RunContext{
+		Config: config,
+		Name:   "build",
+		Run: &model.Run{
+			JobID: "build",
+			Workflow: &model.Workflow{
+				Name: "CI",
+				Jobs: map[string]*model.Job{
+					"build": {
+						Steps: []*model.Step{
+							{ID: "checkout", Uses: "actions/checkout@v2"},
+							{Run: "make"},
+							{ID: "test", Run: "make test"},
+						},
+					},
+				},
+			},
+		},
+		StepResults: make(map[string]*model.StepResult),
+	}
+}
+
+func TestRunStateWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "run.json")
+	state := &RunState{}
+	rc := newRunStateTestContext(&Config{RunState: state})
+	rc.startRunState()
+	rc.result("failure")
+	rc.recordJob("", map[string]string{"version": "1.0"})
+
+	assert.NoError(t, state.Write(path))
+	read, err := ReadRunState(path)
+	assert.NoError(t, err)
+	assert.Equal(t, runStateVersion, read.Version)
+	assert.Equal(t, []*JobState{{
+		Workflow: "CI",
+		JobID:    "build",
+		Name:     "CI/build",
+		Result:   "failure",
+		Outputs:  map[string]string{"version": "1.0"},
+		Steps:    []*StepState{},
+	}}, read.Jobs)
+
+	_, err = ReadRunState(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestRunStateRestoreJob(t *testing.T) {
+	state := &RunState{Jobs: []*JobState{
+		{Name: "CI/build", Result: "success", Outputs: map[string]string{"version": "1.0"}},
+		{Name: "CI/test", Result: "failure"},
+	}}
+
+	rc := newRunStateTestContext(&Config{RunState: state})
+	assert.False(t, rc.restoreJob())
+
+	rc.Config.RerunFailed = true
+	assert.True(t, rc.restoreJob())
+	assert.Equal(t, "success", rc.Run.Job().Result)
+	assert.Equal(t, map[string]string{"version": "1.0"}, rc.Run.Job().Outputs)
+
+	rc.Name = "test"
+	assert.False(t, rc.restoreJob())
+	rc.Name = "lint"
+	assert.False(t, rc.restoreJob())
+}
+
+func TestRunStateFromStep(t *testing.T) {
+	previous := &JobState{
+		Name:   "CI/build",
+		Result: "failure",
+		Steps: []*StepState{
+			{
+				ID:         "checkout",
+				StepResult: model.StepResult{Outcome: model.StepStatusSuccess, Conclusion: model.StepStatusSuccess, Outputs: map[string]string{"ref": "main"}},
+			},
+			{
+				ID:         "1",
+				StepResult: model.StepResult{Outcome: model.StepStatusSuccess, Conclusion: model.StepStatusSuccess},
+				Env:        map[string]string{"VERSION": "1.0"},
+			},
+			{
+				ID:         "test",
+				StepResult: model.StepResult{Outcome: model.StepStatusFailure, Conclusion: model.StepStatusFailure},
+			},
+		},
+	}
+	state := &RunState{Jobs: []*JobState{previous}}
+	rc := newRunStateTestContext(&Config{RunState: state, FromStep: "test"})
+	rc.startRunState()
+	assert.Len(t, rc.restoredSteps, 2)
+	assert.Equal(t, map[string]string{"VERSION": "1.0"}, rc.restoredEnv)
+
+	// the previous run is replaced by the new one
+	assert.Equal(t, "failure", state.Jobs[0].Result)
+	assert.Empty(t, state.Jobs[0].Steps)
+
+	ctx := context.Background()
+	step := &model.Step{ID: "checkout"}
+	sc := &StepContext{RunContext: rc, Step: step}
+	rc.CurrentStep = step.ID
+	rc.resetStepResult()
+	assert.True(t, rc.restoreStep(ctx, sc))
+	assert.Equal(t, map[string]string{"ref": "main"}, rc.StepResults["checkout"].Outputs)
+	rc.recordStep(ctx, sc)
+	assert.Equal(t, []*StepState{previous.Steps[0]}, state.Jobs[0].Steps)
+
+	sc = &StepContext{RunContext: rc, Step: &model.Step{ID: "test"}}
+	rc.CurrentStep = "test"
+	rc.resetStepResult()
+	assert.False(t, rc.restoreStep(ctx, sc))
+}
+
+func TestRunStateFromStepNotRecorded(t *testing.T) {
+	state := &RunState{Jobs: []*JobState{{
+		Name:  "CI/build",
+		Steps: []*StepState{{ID: "checkout"}},
+	}}}
+	rc := newRunStateTestContext(&Config{RunState: state, FromStep: "test"})
+	rc.startRunState()
+	assert.Nil(t, rc.restoredSteps)
+	assert.Nil(t, rc.restoredEnv)
+}
+
+type envContainer struct {
+	container.Container
+	reads int
+}
+
+func (c *envContainer) UpdateFromEnv(srcPath string, env *map[string]string) common.Executor {
+	return func(ctx context.Context) error {
+		c.reads++
+		(*env)["VERSION"] = "1.0"
+		return nil
+	}
+}
+
+func TestRunStateRecordEnv(t *testing.T) {
+	for _, reuse := range []bool{false, true} {
+		state := &RunState{}
+		jobContainer := &envContainer{}
+		rc := newRunStateTestContext(&Config{RunState: state, ReuseContainers: reuse})
+		rc.JobContainer = jobContainer
+		rc.startRunState()
+
+		sc := &StepContext{RunContext: rc, Step: &model.Step{ID: "checkout"}}
+		rc.CurrentStep = "checkout"
+		rc.resetStepResult()
+		rc.recordStep(context.Background(), sc)
+
+		// the env is only needed by --from-step, which requires --reuse
+		if reuse {
+			assert.Equal(t, 1, jobContainer.reads)
+			assert.Equal(t, map[string]string{"VERSION": "1.0"}, state.Jobs[0].Steps[0].Env)
+		} else {
+			assert.Equal(t, 0, jobContainer.reads)
+			assert.Nil(t, state.Jobs[0].Steps[0].Env)
+		}
+	}
+}
+
+func TestEnvFile(t *testing.T) {
+	assert.Equal(t, "", envFile(nil))
+	assert.Equal(t, strings.Join([]string{
+		"A=1",
+		"B<<ACT_RESTORED_ENV_EOF",
+		"line 1",
+		"line 2",
+		"ACT_RESTORED_ENV_EOF",
+		"",
+	}, "\n"), envFile(map[string]string{"B": "line 1\nline 2", "A": "1"}))
+}
+
+func TestDefaultRunStatePath(t *testing.T) {
+	path := DefaultRunStatePath("/home/user/my project")
+	assert.Equal(t, "state", filepath.Base(filepath.Dir(path)))
+	assert.True(t, strings.HasPrefix(filepath.Base(path), "my_project-"))
+	assert.NotEqual(t, path, DefaultRunStatePath("/home/other/my project"))
+}
@@ -3,13 +3,16 @@ package runner
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"path/filepath"
 	"regexp"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/exprparser"
 	"github.com/nektos/act/pkg/model"
 	log "github.com/sirupsen/logrus"
 )
@@ -21,33 +24,55 @@ type Runner interface {
 
 // Config contains the config for a new runner
 type Config struct {
-	Actor                 string                       // the user that triggered the event
-	Workdir               string                       // path to working directory
-	BindWorkdir           bool                         // bind the workdir to the job container
-	EventName             string                       // name of event to run
-	EventPath             string                       // path to JSON file to use for event.json in containers
-	DefaultBranch         string                       // name of the main branch for this repository
-	ReuseContainers       bool                         // reuse containers to maintain state
-	ForcePull             bool                         // force pulling of the image, even if already present
-	ForceRebuild          bool                         // force rebuilding local docker image action
-	LogOutput             bool                         // log the output from docker run
-	JSONLogger            bool                         // use json or text logger
-	Env                   map[string]string            // env for containers
-	Secrets               map[string]string            // list of secrets
-	InsecureSecrets       bool                         // switch hiding output when printing to terminal
-	Platforms             map[string]string            // list of platforms
-	Privileged            bool                         // use privileged mode
-	UsernsMode            string                       // user namespace to use
-	ContainerArchitecture string                       // Desired OS/architecture platform for running containers
-	ContainerDaemonSocket string                       // Path to Docker daemon socket
-	UseGitIgnore          bool                         // controls if paths in .gitignore should not be copied into container, default true
-	GitHubInstance        string                       // GitHub instance to use, default "github.com"
-	ContainerCapAdd       []string                     // list of kernel capabilities to add to the containers
-	ContainerCapDrop      []string                     // list of kernel capabilities to remove from the containers
-	AutoRemove            bool                         // controls if the container is automatically removed upon workflow completion
-	ArtifactServerPath    string                       // the path where the artifact server stores uploads
-	ArtifactServerPort    string                       // the port the artifact server binds to
-	CompositeRestrictions *model.CompositeRestrictions // describes which features are available in composite actions
+	Actor                 string                          // the user that triggered the event
+	Workdir               string                          // path to working directory
+	BindWorkdir           bool                            // bind the workdir to the job container
+	EventName             string                          // name of event to run
+	EventPath             string                          // path to JSON file to use for event.json in containers
+	EventJSON             string                          // JSON to use for event.json in containers, takes precedence over EventPath
+	DefaultBranch         string                          // name of the main branch for this repository
+	ReuseContainers       bool                            // reuse containers to maintain state
+	ForcePull             bool                            // force pulling of the image, even if already present
+	ForceRebuild          bool                            // force rebuilding local docker image action
+	LogOutput             bool                            // log the output from docker run
+	JSONLogger            bool                            // use json or text logger
+	LogWriter             io.Writer                       // writer for the job logs, defaults to os.Stdout
+	LogDir                string                          // directory to write the logs of every job and the output of its steps to
+	Env                   map[string]string               // env for containers
+	Secrets               map[string]string               // list of secrets
+	Vars                  map[string]string               // configuration variables of the repository for the vars context
+	EnvironmentVars       map[string]map[string]string    // configuration variables of the deployment environments by their name, they override Vars
+	EnvironmentSecrets    map[string]map[string]string    // secrets of the deployment environments by their name, they override Secrets
+	ReviewDeployments     bool                            // ask to approve or reject every job with a deployment environment before it runs
+	InsecureSecrets       bool                            // switch hiding output when printing to terminal
+	Platforms             map[string]string               // list of platforms
+	Privileged            bool                            // use privileged mode
+	UsernsMode            string                          // user namespace to use
+	ContainerArchitecture string                          // Desired OS/architecture platform for running containers
+	ContainerDaemonSocket string                          // Path to Docker daemon socket
+	UseGitIgnore          bool                            // controls if paths in .gitignore should not be copied into container, default true
+	GitHubInstance        string                          // GitHub instance to use, default "github.com"
+	ContainerCapAdd       []string                        // list of kernel capabilities to add to the containers
+	ContainerCapDrop      []string                        // list of kernel capabilities to remove from the containers
+	AutoRemove            bool                            // controls if the container is automatically removed upon workflow completion
+	ContainerNamePrefix   string                          // prefix of the names of the job containers, "act" if empty, distinguishes concurrent runs of the same jobs
+	NextRunID             func() int                      // returns the GITHUB_RUN_ID of the next run of a chained workflow, counts up from the one in Env if nil
+	ArtifactServerPath    string                          // the path where the artifact server stores uploads
+	ArtifactServerPort    string                          // the port the artifact server binds to
+	CompositeRestrictions *model.CompositeRestrictions    // describes which features are available in composite actions
+	Hooks                 Hooks                           // receives the lifecycle events of plans, jobs, steps and commands
+	PauseOnFailure        bool                            // pause jobs at failed steps to inspect the job container in a shell
+	PauseBefore           []string                        // ids of the steps to pause jobs before
+	RunState              *RunState                       // records the state of the jobs, holds the previous run to rerun failed jobs or resume jobs from a step
+	RerunFailed           bool                            // run only the jobs that did not succeed in RunState, the outputs of the others are restored
+	FromStep              string                          // skip the steps before the step with this id, name or index, their results are restored from RunState
+	Steps                 []string                        // ids, names or indexes of the steps to run, all steps run if empty
+	SkipSteps             []string                        // ids, names or indexes of the steps to skip
+	RunAlwaysSteps        bool                            // run the steps with always() in their condition even if they are not selected
+	TraceExpressions      bool                            // log the evaluation of the sub-expressions of the `if` conditions of jobs and steps at debug level
+	StrictExpressions     bool                            // fail on dereferencing a property that doesn't exist on a context with a known shape like github or runner
+	ExpressionFunctions   map[string]*exprparser.Function // functions available in expressions in addition to the built-in ones
+	ExpressionContexts    map[string]interface{}          // contexts available in expressions in addition to the built-in ones
 }
 
 // Resolves the equivalent host path inside the container
@@ -101,16 +126,27 @@ func New(runnerConfig *Config) (Runner, error) {
 		config: runnerConfig,
 	}
 
-	runner.eventJSON = "{}"
-	if runnerConfig.EventPath != "" {
-		log.Debugf("Reading event.json from %s", runner.config.EventPath)
-		eventJSONBytes, err := ioutil.ReadFile(runner.config.EventPath)
+	eventJSON, err := readEventJSON(runnerConfig)
+	if err != nil {
+		return nil, err
+	}
+	runner.eventJSON = eventJSON
+	return runner, nil
+}
+
+func readEventJSON(config *Config) (string, error) {
+	if config.EventJSON != "" {
+		return config.EventJSON, nil
+	}
+	if config.EventPath != "" {
+		log.Debugf("Reading event.json from %s", config.EventPath)
+		eventJSONBytes, err := ioutil.ReadFile(config.EventPath)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
-		runner.eventJSON = string(eventJSONBytes)
+		return string(eventJSONBytes), nil
 	}
-	return runner, nil
+	return "{}", nil
 }
 
 func (runner *runnerImpl) NewPlanExecutor(plan *model.Plan) common.Executor {
@@ -127,7 +163,7 @@ func (runner *runnerImpl) NewPlanExecutor(plan *model.Plan) common.Executor {
 				job := run.Job()
 				if job.Strategy != nil {
 					strategyRc := runner.newRunContext(run, nil)
-					if err := strategyRc.NewExpressionEvaluator().EvaluateYamlNode(&job.Strategy.RawMatrix); err != nil {
+					if err := strategyRc.NewExpressionEvaluator(context.Background()).EvaluateYamlNode(&job.Strategy.RawMatrix); err != nil {
 						log.Errorf("Error while evaluating matrix: %v", err)
 					}
 				}
@@ -150,8 +186,18 @@ func (runner *runnerImpl) NewPlanExecutor(plan *model.Plan) common.Executor {
 					if len(rc.String()) > maxJobNameLen {
 						maxJobNameLen = len(rc.String())
 					}
+					if rc.restoreJob() {
+						continue
+					}
 					stageExecutor = append(stageExecutor, func(ctx context.Context) error {
 						jobName := fmt.Sprintf("%-*s", maxJobNameLen, rc.String())
+						ctx, cancel := context.WithCancel(ctx)
+						defer cancel()
+						rc.cancel = cancel
+						ctx = common.WithJobErrorContainer(WithJobLogger(ctx, rc.Run.JobID, jobName, rc.Config, &rc.Masks))
+						closeLogDir := rc.attachLogDir(ctx)
+						defer closeLogDir()
+						rc.startRunState()
 						return rc.Executor().Finally(func(ctx context.Context) error {
 							isLastRunningContainer := func(currentStage int, currentRun int) bool {
 								return currentStage == len(plan.Stages)-1 && currentRun == len(stage.Runs)-1
@@ -165,7 +211,7 @@ func (runner *runnerImpl) NewPlanExecutor(plan *model.Plan) common.Executor {
 							}
 
 							return nil
-						})(common.WithJobErrorContainer(WithJobLogger(ctx, jobName, rc.Config, &rc.Masks)))
+						})(ctx)
 					})
 				}
 				pipeline = append(pipeline, common.NewParallelExecutor(maxParallel, stageExecutor...))
@@ -174,15 +220,26 @@ func (runner *runnerImpl) NewPlanExecutor(plan *model.Plan) common.Executor {
 		})
 	}
 
-	return common.NewPipelineExecutor(stagePipeline...).Then(handleFailure(plan))
+	planExecutor := common.NewPipelineExecutor(stagePipeline...).Then(handleFailure(plan))
+	return func(ctx context.Context) error {
+		hooks := runner.config.hooks()
+		hooks.PlanStarted(ctx, &PlanEvent{Plan: plan})
+		startTime := time.Now()
+		err := planExecutor(ctx)
+		hooks.PlanCompleted(ctx, &PlanEvent{Plan: plan, Err: err, Duration: time.Since(startTime)})
+		return err
+	}
 }
 
 func handleFailure(plan *model.Plan) common.Executor {
 	return func(ctx context.Context) error {
 		for _, stage := range plan.Stages {
 			for _, run := range stage.Runs {
-				if run.Job().Result == "failure" {
+				switch run.Job().Result {
+				case "failure":
 					return fmt.Errorf("Job '%s' failed", run.String())
+				case "cancelled":
+					return fmt.Errorf("Job '%s' was cancelled", run.String())
 				}
 			}
 		}
@@ -198,7 +255,7 @@ func (runner *runnerImpl) newRunContext(run *model.Run, matrix map[string]interf
 		StepResults: make(map[string]*model.StepResult),
 		Matrix:      matrix,
 	}
-	rc.ExprEval = rc.NewExpressionEvaluator()
+	rc.ExprEval = rc.NewExpressionEvaluator(context.Background())
 	rc.Name = rc.ExprEval.Interpolate(run.String())
 	return rc
 }
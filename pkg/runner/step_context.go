@@ -28,6 +28,7 @@ type StepContext struct {
 	Step       *model.Step
 	Env        map[string]string
 	Cmd        []string
+	ScriptPath string // path of the script of a run step in the job container
 	Action     *model.Action
 	Needs      *model.Job
 }
@@ -169,7 +170,7 @@ func (sc *StepContext) interpolateEnv(exprEval ExpressionEvaluator) {
 }
 
 func (sc *StepContext) isEnabled(ctx context.Context) (bool, error) {
-	runStep, err := EvalBool(sc.NewExpressionEvaluator(), sc.Step.If.Value)
+	runStep, err := EvalBool(traceExpressions(ctx, sc.RunContext.Config, sc.NewExpressionEvaluator(ctx)), sc.Step.If.Value)
 	if err != nil {
 		return false, fmt.Errorf("  \u274C  Error in if-expression: \"if: %s\" (%s)", sc.Step.If.Value, err)
 	}
@@ -195,7 +196,7 @@ func (sc *StepContext) setupEnv(ctx context.Context) (ExpressionEvaluator, error
 		}
 	}
 	sc.Env = mergeMaps(sc.Env, sc.Step.GetEnv()) // step env should not be overwritten
-	evaluator := sc.NewExpressionEvaluator()
+	evaluator := sc.NewExpressionEvaluator(ctx)
 	sc.interpolateEnv(evaluator)
 
 	common.Logger(ctx).Debugf("setupEnv => %v", sc.Env)
@@ -253,10 +254,8 @@ func getScriptName(rc *RunContext, step *model.Step) string {
 	return fmt.Sprintf("workflow/%s", scriptName)
 }
 
-// TODO: Currently we just ignore top level keys, BUT we should return proper error on them
-// BUTx2 I leave this for when we rewrite act to use actionlint for workflow validation
-// so we return proper errors before any execution or spawning containers
-// it will error anyway with:
+// Invalid workflows are reported with their position by the lint package before the run, unless --no-lint is passed,
+// otherwise they error here with e.g.:
 // OCI runtime exec failed: exec failed: container_linux.go:380: starting container process caused: exec: "${{": executable file not found in $PATH: unknown
 func (sc *StepContext) setupShellCommand() (name, script string, err error) {
 	sc.setupShell()
@@ -292,8 +291,8 @@ func (sc *StepContext) setupShellCommand() (name, script string, err error) {
 
 	log.Debugf("Wrote command \n%s\n to '%s'", script, name)
 
-	scriptPath := fmt.Sprintf("%s/%s", ActPath, name)
-	sc.Cmd, err = shellquote.Split(strings.Replace(scCmd, `{0}`, scriptPath, 1))
+	sc.ScriptPath = fmt.Sprintf("%s/%s", ActPath, name)
+	sc.Cmd, err = shellquote.Split(strings.Replace(scCmd, `{0}`, sc.ScriptPath, 1))
 
 	return name, script, err
 }
@@ -317,15 +316,7 @@ func (sc *StepContext) setupShellCommandExecutor() common.Executor {
 func (sc *StepContext) newStepContainer(ctx context.Context, image string, cmd []string, entrypoint []string) container.Container {
 	rc := sc.RunContext
 	step := sc.Step
-	rawLogger := common.Logger(ctx).WithField("raw_output", true)
-	logWriter := common.NewLineWriter(rc.commandHandler(ctx), func(s string) bool {
-		if rc.Config.LogOutput {
-			rawLogger.Infof("%s", s)
-		} else {
-			rawLogger.Debugf("%s", s)
-		}
-		return true
-	})
+	logWriter := common.NewLineWriter(rc.commandHandler(ctx), rc.outputHandler(ctx))
 	envList := make([]string, 0)
 	for k, v := range sc.Env {
 		envList = append(envList, fmt.Sprintf("%s=%s", k, v))
@@ -363,7 +354,7 @@ func (sc *StepContext) runUsesContainer() common.Executor {
 	step := sc.Step
 	return func(ctx context.Context) error {
 		image := strings.TrimPrefix(step.Uses, "docker://")
-		eval := sc.RunContext.NewExpressionEvaluator()
+		eval := sc.RunContext.NewExpressionEvaluator(ctx)
 		cmd, err := shellquote.Split(eval.Interpolate(step.With["args"]))
 		if err != nil {
 			return err
@@ -492,7 +483,7 @@ func (sc *StepContext) runAction(actionDir string, actionPath string, actionRepo
 	}
 }
 
-func (sc *StepContext) evalDockerArgs(action *model.Action, cmd *[]string) {
+func (sc *StepContext) evalDockerArgs(ctx context.Context, action *model.Action, cmd *[]string) {
 	rc := sc.RunContext
 	step := sc.Step
 	oldInputs := rc.Inputs
@@ -500,7 +491,7 @@ func (sc *StepContext) evalDockerArgs(action *model.Action, cmd *[]string) {
 		rc.Inputs = oldInputs
 	}()
 	inputs := make(map[string]interface{})
-	eval := sc.RunContext.NewExpressionEvaluator()
+	eval := sc.RunContext.NewExpressionEvaluator(ctx)
 	// Set Defaults
 	for k, input := range action.Inputs {
 		inputs[k] = eval.Interpolate(input.Default)
@@ -511,13 +502,13 @@ func (sc *StepContext) evalDockerArgs(action *model.Action, cmd *[]string) {
 		}
 	}
 	rc.Inputs = inputs
-	stepEE := sc.NewExpressionEvaluator()
+	stepEE := sc.NewExpressionEvaluator(ctx)
 	for i, v := range *cmd {
 		(*cmd)[i] = stepEE.Interpolate(v)
 	}
 	sc.Env = mergeMaps(sc.Env, action.Runs.Env)
 
-	ee := sc.NewExpressionEvaluator()
+	ee := sc.NewExpressionEvaluator(ctx)
 	for k, v := range sc.Env {
 		sc.Env[k] = ee.Interpolate(v)
 	}
@@ -577,14 +568,14 @@ func (sc *StepContext) execAsDocker(ctx context.Context, action *model.Action, a
 			log.Debugf("image '%s' for architecture '%s' already exists", image, rc.Config.ContainerArchitecture)
 		}
 	}
-	eval := sc.NewExpressionEvaluator()
+	eval := sc.NewExpressionEvaluator(ctx)
 	cmd, err := shellquote.Split(eval.Interpolate(step.With["args"]))
 	if err != nil {
 		return err
 	}
 	if len(cmd) == 0 {
 		cmd = action.Runs.Args
-		sc.evalDockerArgs(action, &cmd)
+		sc.evalDockerArgs(ctx, action, &cmd)
 	}
 	entrypoint := strings.Fields(eval.Interpolate(step.With["entrypoint"]))
 	if len(entrypoint) == 0 {
@@ -622,7 +613,7 @@ func (sc *StepContext) execAsComposite(ctx context.Context, step *model.Step, _
 		}
 	}
 	inputs := make(map[string]interface{})
-	eval := sc.RunContext.NewExpressionEvaluator()
+	eval := sc.RunContext.NewExpressionEvaluator(ctx)
 	// Set Defaults
 	for k, input := range action.Inputs {
 		inputs[k] = eval.Interpolate(input.Default)
@@ -658,14 +649,14 @@ func (sc *StepContext) execAsComposite(ctx context.Context, step *model.Step, _
 		compositerc.Env[k] = ev
 	}
 	compositerc.Inputs = inputs
-	compositerc.ExprEval = compositerc.NewExpressionEvaluator()
+	compositerc.ExprEval = compositerc.NewExpressionEvaluator(ctx)
 	err = compositerc.CompositeExecutor()(ctx)
 
 	// Map outputs to parent rc
 	eval = (&StepContext{
 		Env:        compositerc.Env,
 		RunContext: compositerc,
-	}).NewExpressionEvaluator()
+	}).NewExpressionEvaluator(ctx)
 	for outputName, output := range action.Outputs {
 		backup.setOutput(ctx, map[string]string{
 			"name": outputName,
@@ -0,0 +1,46 @@
+package runner
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/model"
+)
+
+// MatchStep returns true if the filter is the id, the name or the index of the step in its job, starting at 0
+func MatchStep(filter string, index int, step *model.Step) bool {
+	return filter == step.ID || filter == step.Name || filter == strconv.Itoa(index)
+}
+
+// selectsStep returns true if the step of a job is selected by Config.Steps and not excluded by Config.SkipSteps.
+// Steps that run always() are selected as well if Config.RunAlwaysSteps is set.
+func (config *Config) selectsStep(index int, step *model.Step) bool {
+	selected := len(config.Steps) == 0
+	for _, filter := range config.Steps {
+		selected = selected || MatchStep(filter, index, step)
+	}
+	for _, filter := range config.SkipSteps {
+		selected = selected && !MatchStep(filter, index, step)
+	}
+	return selected || config.RunAlwaysSteps && exprparser.CallsAlways(step.If.Value)
+}
+
+// skipUnselectedStep records the step as skipped if it is not selected to run, steps of composite actions always run
+func (rc *RunContext) skipUnselectedStep(ctx context.Context, sc *StepContext) bool {
+	if rc.Parent != nil {
+		return false
+	}
+	for i, step := range rc.steps() {
+		if step != sc.Step || rc.Config.selectsStep(i, step) {
+			continue
+		}
+		result := rc.StepResults[rc.CurrentStep]
+		result.Outcome = model.StepStatusSkipped
+		result.Conclusion = model.StepStatusSkipped
+		common.Logger(ctx).WithFields(stepResultFields(result)).Infof("\u23ED  Skipping %s, it is not selected", sc.Step)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestMatchStep(t *testing.T) {
+	step := &model.Step{ID: "test", Name: "Run tests"}
+	assert.True(t, MatchStep("test", 2, step))
+	assert.True(t, MatchStep("Run tests", 2, step))
+	assert.True(t, MatchStep("2", 2, step))
+	assert.False(t, MatchStep("1", 2, step))
+	assert.False(t, MatchStep("build", 2, step))
+}
+
+func TestConfigSelectsStep(t *testing.T) {
+	build := &model.Step{ID: "build"}
+	test := &model.Step{ID: "test"}
+	cleanup := &model.Step{ID: "cleanup", If: yaml.Node{Value: "${{ always () }}"}}
+	notAlways := &model.Step{ID: "report", If: yaml.Node{Value: "!always()"}}
+
+	tables := []struct {
+		config   *Config
+		selected []bool
+	}{
+		{&Config{}, []bool{true, true, true, true}},
+		{&Config{Steps: []string{"test"}}, []bool{false, true, false, false}},
+		{&Config{Steps: []string{"test"}, RunAlwaysSteps: true}, []bool{false, true, true, false}},
+		{&Config{SkipSteps: []string{"0", "cleanup"}}, []bool{false, true, false, true}},
+		{&Config{Steps: []string{"build", "test"}, SkipSteps: []string{"test"}}, []bool{true, false, false, false}},
+	}
+	for _, table := range tables {
+		selected := []bool{
+			table.config.selectsStep(0, build),
+			table.config.selectsStep(1, test),
+			table.config.selectsStep(2, cleanup),
+			table.config.selectsStep(3, notAlways),
+		}
+		assert.Equal(t, table.selected, selected, "%+v", table.config)
+	}
+}
+
+func TestSkipUnselectedStep(t *testing.T) {
+	rc := newRunStateTestContext(&Config{Steps: []string{"test"}})
+	steps := rc.steps()
+	for _, step := range steps {
+		rc.CurrentStep = step.ID
+		rc.resetStepResult()
+		skipped := rc.skipUnselectedStep(context.Background(), &StepContext{RunContext: rc, Step: step})
+		assert.Equal(t, step.ID != "test", skipped)
+	}
+	assert.Equal(t, model.StepStatusSkipped, rc.StepResults["checkout"].Conclusion)
+	assert.Equal(t, model.StepStatusSuccess, rc.StepResults["test"].Conclusion)
+
+	// steps of composite actions run with the step using the action
+	composite := rc.Clone()
+	composite.Parent = rc
+	composite.CurrentStep = "checkout"
+	composite.resetStepResult()
+	assert.False(t, composite.skipUnselectedStep(context.Background(), &StepContext{RunContext: composite, Step: steps[0]}))
+}
@@ -0,0 +1,189 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+)
+
+// NewChainedPlanExecutor runs the plan and afterwards the workflows triggered by `workflow_run` events of the completed workflows.
+// Chaining stops after maxDepth follow-up plans, GitHub allows at most three levels.
+// The chained runs take their GITHUB_RUN_ID from Config.NextRunID, or count up from the one of the run.
+func NewChainedPlanExecutor(config *Config, planner model.WorkflowPlanner, plan *model.Plan, maxDepth int) common.Executor {
+	if config.NextRunID == nil {
+		chainedConfig := *config
+		runID := runIDFromEnv(config.Env)
+		chainedConfig.NextRunID = func() int {
+			runID++
+			return runID
+		}
+		config = &chainedConfig
+	}
+	return newChainedPlanExecutor(config, planner, plan, 0, maxDepth)
+}
+
+func newChainedPlanExecutor(config *Config, planner model.WorkflowPlanner, plan *model.Plan, depth int, maxDepth int) common.Executor {
+	return func(ctx context.Context) error {
+		r, err := New(config)
+		if err != nil {
+			return err
+		}
+
+		planErr := r.NewPlanExecutor(plan)(ctx)
+		if ctx.Err() != nil {
+			return planErr
+		}
+
+		runID := runIDFromEnv(config.Env)
+		branch := workflowRunBranch(config.Workdir)
+		for _, w := range planWorkflows(plan) {
+			followUp := planner.PlanWorkflowRun(w.Name, "completed", branch)
+			if len(followUp.Stages) == 0 {
+				continue
+			}
+			if depth >= maxDepth {
+				log.Warnf("Not running workflows triggered by workflow_run of '%s', chain depth of %d reached", w.Name, maxDepth)
+				continue
+			}
+
+			conclusion := workflowConclusion(plan, w)
+			eventJSON, err := newWorkflowRunEvent(config, w, conclusion, runID, branch)
+			if err != nil {
+				return err
+			}
+
+			chainedRunID := config.NextRunID()
+			chainedConfig := *config
+			chainedConfig.EventName = "workflow_run"
+			chainedConfig.EventPath = ""
+			chainedConfig.EventJSON = eventJSON
+			chainedConfig.Env = mergeMaps(config.Env, map[string]string{
+				"GITHUB_RUN_ID":     strconv.Itoa(chainedRunID),
+				"GITHUB_RUN_NUMBER": strconv.Itoa(chainedRunID),
+			})
+
+			log.Infof("Workflow '%s' completed with '%s', running workflows triggered by workflow_run", w.Name, conclusion)
+			err = newChainedPlanExecutor(&chainedConfig, planner, followUp, depth+1, maxDepth)(ctx)
+			if planErr == nil {
+				planErr = err
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		return planErr
+	}
+}
+
+// planWorkflows returns the distinct workflows of the plan in order of appearance
+func planWorkflows(plan *model.Plan) []*model.Workflow {
+	workflows := make([]*model.Workflow, 0)
+	seen := make(map[*model.Workflow]bool)
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			if !seen[run.Workflow] {
+				seen[run.Workflow] = true
+				workflows = append(workflows, run.Workflow)
+			}
+		}
+	}
+	return workflows
+}
+
+// workflowConclusion returns the conclusion of the run of the workflow in the plan like GitHub: failure if a job failed,
+// cancelled if a job was cancelled, skipped if all jobs were skipped and success otherwise
+func workflowConclusion(plan *model.Plan, w *model.Workflow) string {
+	conclusion := "skipped"
+	for _, stage := range plan.Stages {
+		for _, run := range stage.Runs {
+			if run.Workflow != w {
+				continue
+			}
+			switch run.Job().Result {
+			case "failure":
+				return "failure"
+			case "cancelled":
+				conclusion = "cancelled"
+			case "success":
+				if conclusion == "skipped" {
+					conclusion = "success"
+				}
+			}
+		}
+	}
+	return conclusion
+}
+
+func runIDFromEnv(env map[string]string) int {
+	if runID, err := strconv.Atoi(env["GITHUB_RUN_ID"]); err == nil {
+		return runID
+	}
+	return 1
+}
+
+func workflowRunBranch(workdir string) string {
+	ref, err := common.FindGitRef(workdir)
+	if err != nil {
+		log.Warningf("unable to get git ref: %v", err)
+		return ""
+	}
+	return strings.TrimPrefix(ref, "refs/heads/")
+}
+
+// newWorkflowRunEvent synthesizes the payload of a `workflow_run` event for a completed run of the workflow
+// Reference: https://docs.github.com/en/developers/webhooks-and-events/webhooks/webhook-events-and-payloads#workflow_run
+func newWorkflowRunEvent(config *Config, w *model.Workflow, conclusion string, runID int, branch string) (string, error) {
+	headSha := ""
+	if _, sha, err := common.FindGitRevision(config.Workdir); err != nil {
+		log.Warningf("unable to get git revision: %v", err)
+	} else {
+		headSha = sha
+	}
+
+	workflowPath := path.Join(".github/workflows", w.File)
+	event := map[string]interface{}{
+		"action": "completed",
+		"workflow": map[string]interface{}{
+			"name": w.Name,
+			"path": workflowPath,
+		},
+		"workflow_run": map[string]interface{}{
+			"id":          runID,
+			"run_number":  runID,
+			"name":        w.Name,
+			"path":        workflowPath,
+			"event":       config.EventName,
+			"status":      "completed",
+			"conclusion":  conclusion,
+			"head_branch": branch,
+			"head_sha":    headSha,
+			"run_attempt": 1,
+		},
+	}
+
+	// keep the repository of the triggering event so `github.event.repository` stays available
+	parentJSON, err := readEventJSON(config)
+	if err != nil {
+		return "", err
+	}
+	parent := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(parentJSON), &parent); err != nil {
+		log.Warningf("unable to read repository from triggering event: %v", err)
+	}
+	if repository, ok := parent["repository"]; ok {
+		event["repository"] = repository
+	}
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	return string(eventJSON), nil
+}
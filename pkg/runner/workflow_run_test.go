@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+func TestWorkflowRunEvent(t *testing.T) {
+	build := &model.Workflow{
+		Name: "build",
+		File: "build.yml",
+		Jobs: map[string]*model.Job{
+			"lint": {Result: "success"},
+			"test": {Result: "failure"},
+		},
+	}
+	other := &model.Workflow{
+		Name: "other",
+		Jobs: map[string]*model.Job{
+			"job": {Result: "success"},
+		},
+	}
+	plan := &model.Plan{
+		Stages: []*model.Stage{
+			{Runs: []*model.Run{{Workflow: build, JobID: "lint"}, {Workflow: other, JobID: "job"}}},
+			{Runs: []*model.Run{{Workflow: build, JobID: "test"}}},
+		},
+	}
+
+	assert.Equal(t, []*model.Workflow{build, other}, planWorkflows(plan))
+	assert.Equal(t, "failure", workflowConclusion(plan, build))
+	assert.Equal(t, "success", workflowConclusion(plan, other))
+
+	other.Jobs["job"].Result = "skipped"
+	assert.Equal(t, "skipped", workflowConclusion(plan, other))
+	other.Jobs["job"].Result = "cancelled"
+	assert.Equal(t, "cancelled", workflowConclusion(plan, other))
+
+	config := &Config{
+		Workdir:   "testdata",
+		EventName: "push",
+		EventJSON: `{"repository": {"default_branch": "main"}}`,
+	}
+	eventJSON, err := newWorkflowRunEvent(config, build, "failure", 7, "main")
+	assert.NoError(t, err)
+
+	event := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(eventJSON), &event))
+	assert.Equal(t, "completed", event["action"])
+	assert.Equal(t, map[string]interface{}{"default_branch": "main"}, event["repository"])
+
+	workflowRun := event["workflow_run"].(map[string]interface{})
+	assert.Equal(t, "build", workflowRun["name"])
+	assert.Equal(t, ".github/workflows/build.yml", workflowRun["path"])
+	assert.Equal(t, "push", workflowRun["event"])
+	assert.Equal(t, "failure", workflowRun["conclusion"])
+	assert.Equal(t, "main", workflowRun["head_branch"])
+	assert.Equal(t, float64(7), workflowRun["id"])
+
+	assert.Equal(t, 1, runIDFromEnv(nil))
+	assert.Equal(t, 42, runIDFromEnv(map[string]string{"GITHUB_RUN_ID": "42"}))
+}
@@ -0,0 +1,206 @@
+package runner
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/nektos/act/pkg/container"
+	log "github.com/sirupsen/logrus"
+)
+
+// workspaceCopy is a copy of search paths of hashFiles in the workspace of the job container. A step copies them
+// once before it runs, so that e.g. the key and the restore keys of a cache don't copy the workspace each.
+type workspaceCopy struct {
+	dir         string
+	searchPaths []string // copied search paths, including the ones that don't exist
+}
+
+// withJobContainerWorkspace calls fn with the files of the search paths in the workspace of the job container for
+// hashFiles. Without Config.BindWorkdir they can differ from the files in Config.Workdir, e.g. the files ignored by
+// .gitignore aren't copied and steps create files, so the cache keys would differ from the ones on GitHub.
+// The files in Config.Workdir are used if the job container isn't running, e.g. in a dryrun.
+func (rc *RunContext) withJobContainerWorkspace(ctx context.Context, searchPaths []string, fn func(workspace fs.FS) error) error {
+	if rc.JobContainer == nil {
+		return fn(os.DirFS(rc.Config.Workdir))
+	}
+
+	workspace := rc.workspace
+	if workspace == nil {
+		// outside of a step, e.g. in the outputs of the job
+		workspace = &workspaceCopy{}
+		defer workspace.remove()
+	}
+	ok, err := workspace.copy(ctx, rc.JobContainer, rc.Config.ContainerWorkdir(), searchPaths)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("Unable to copy the workspace of the job container: %v", err)
+	}
+	if !ok {
+		return fn(os.DirFS(rc.Config.Workdir))
+	}
+	return fn(os.DirFS(workspace.dir))
+}
+
+// copy copies the search paths that aren't in the copy yet from the workspace of the job container, it returns
+// false if the container can't provide them, e.g. because it isn't running
+func (w *workspaceCopy) copy(ctx context.Context, jobContainer container.Container, containerWorkdir string, searchPaths []string) (bool, error) {
+	if w.dir == "" {
+		dir, err := ioutil.TempDir("", "act-workspace")
+		if err != nil {
+			return false, err
+		}
+		w.dir = dir
+	}
+
+	copied := false
+	for _, searchPath := range searchPaths {
+		if w.contains(searchPath) {
+			continue
+		}
+		// the archive of a path contains its last element, the one of the workspace only its content
+		srcPath := path.Join(containerWorkdir, searchPath)
+		if searchPath == "." {
+			srcPath = containerWorkdir + "/."
+		}
+		archive, err := jobContainer.GetContainerArchive(ctx, srcPath)
+		if errors.Is(err, os.ErrNotExist) {
+			w.searchPaths = append(w.searchPaths, searchPath)
+			continue
+		} else if err != nil {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			log.Debugf("Using the files in the working directory for hashFiles: %v", err)
+			return false, nil
+		}
+		err = extractWorkspace(archive, w.dir, path.Dir(searchPath), containerWorkdir)
+		archive.Close()
+		if err != nil {
+			return false, err
+		}
+		w.searchPaths = append(w.searchPaths, searchPath)
+		copied = true
+	}
+	if copied {
+		if err := removeEscapingLinks(w.dir); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// contains returns true if the search path or one of its parents was copied
+func (w *workspaceCopy) contains(searchPath string) bool {
+	for _, copied := range w.searchPaths {
+		if copied == "." || copied == searchPath || strings.HasPrefix(searchPath, copied+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// remove deletes the copy, the next hashFiles copies the workspace again
+func (w *workspaceCopy) remove() {
+	if w == nil || w.dir == "" {
+		return
+	}
+	if err := os.RemoveAll(w.dir); err != nil {
+		log.Debugf("Unable to remove the copy of the workspace %s: %v", w.dir, err)
+	}
+	w.dir = ""
+	w.searchPaths = nil
+}
+
+// extractWorkspace writes the directories, files and symbolic links of the tar archive of the directory parent of the
+// workspace to dir. Symbolic links to absolute paths in the workspace are changed to point into dir, the ones outside
+// of it are skipped like relative links out of dir.
+func extractWorkspace(archive io.Reader, dir string, parent string, containerWorkdir string) error {
+	reader := tar.NewReader(archive)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Join(parent, strings.TrimPrefix(header.Name, "/"))
+		if name == "." || name == ".." || strings.HasPrefix(name, "../") {
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, reader)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			link := header.Linkname
+			if path.IsAbs(link) {
+				rel := strings.TrimPrefix(link, containerWorkdir)
+				if rel == link || (rel != "" && !strings.HasPrefix(rel, "/")) {
+					continue
+				}
+				link = filepath.Join(dir, filepath.FromSlash(rel))
+			} else if target := path.Join(path.Dir(name), link); target == ".." || strings.HasPrefix(target, "../") {
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// removeEscapingLinks removes the symbolic links in dir that don't resolve to a path in it, e.g. a relative link
+// through another link to a parent directory, so the files of the host are never read
+func removeEscapingLinks(dir string) error {
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	return filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			return err
+		}
+		target, err := filepath.EvalSymlinks(name)
+		if err == nil {
+			rel, relErr := filepath.Rel(root, target)
+			if relErr == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return nil
+			}
+		}
+		return os.Remove(name)
+	})
+}
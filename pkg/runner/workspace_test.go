@@ -0,0 +1,260 @@
+package runner
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// archiveContainer returns the archives of the paths in the container, it isn't running without archives
+type archiveContainer struct {
+	container.Container
+	archives map[string][]byte
+	srcPaths []string
+}
+
+func (c *archiveContainer) GetContainerArchive(ctx context.Context, srcPath string) (io.ReadCloser, error) {
+	c.srcPaths = append(c.srcPaths, srcPath)
+	if c.archives == nil {
+		return nil, fmt.Errorf("container is not running")
+	}
+	archive, ok := c.archives[srcPath]
+	if !ok {
+		return nil, fmt.Errorf("Could not find the file %s in container: %w", srcPath, os.ErrNotExist)
+	}
+	return ioutil.NopCloser(bytes.NewReader(archive)), nil
+}
+
+// archiveEntry is a file, directory or symbolic link of a tar archive, content is the target of a link
+type archiveEntry struct {
+	name     string
+	typeflag byte
+	content  string
+}
+
+func workspaceArchive(t *testing.T, entries ...archiveEntry) []byte {
+	var buf bytes.Buffer
+	writer := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Typeflag: entry.typeflag, Mode: 0755}
+		switch entry.typeflag {
+		case tar.TypeReg:
+			header.Size = int64(len(entry.content))
+		case tar.TypeSymlink:
+			header.Linkname = entry.content
+		}
+		assert.NoError(t, writer.WriteHeader(header))
+		if entry.typeflag == tar.TypeReg {
+			_, err := writer.Write([]byte(entry.content))
+			assert.NoError(t, err)
+		}
+	}
+	assert.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestExtractWorkspace(t *testing.T) {
+	archive := workspaceArchive(t,
+		archiveEntry{"./", tar.TypeDir, ""},
+		archiveEntry{"./package-lock.json", tar.TypeReg, "lock"},
+		archiveEntry{"./web/", tar.TypeDir, ""},
+		archiveEntry{"./web/index.js", tar.TypeReg, "index"},
+		archiveEntry{"./web/lock.json", tar.TypeSymlink, "/work/act/package-lock.json"},
+		archiveEntry{"./web/relative.json", tar.TypeSymlink, "../package-lock.json"},
+		archiveEntry{"./web/passwd", tar.TypeSymlink, "/etc/passwd"},
+		archiveEntry{"./web/up", tar.TypeSymlink, "../.."},
+		archiveEntry{"./web/host.json", tar.TypeSymlink, "../../host.json"},
+		archiveEntry{"../escaped", tar.TypeReg, "escaped"},
+	)
+
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "host.json"), []byte("host"), 0600))
+	assert.NoError(t, extractWorkspace(bytes.NewReader(archive), filepath.Join(dir, "workspace"), ".", "/work/act"))
+
+	for file, content := range map[string]string{
+		"package-lock.json":   "lock",
+		"web/index.js":        "index",
+		"web/lock.json":       "lock",
+		"web/relative.json":   "lock",
+		"../escaped":          "",
+		"web/passwd":          "",
+		"web/up/host.json":    "",
+		"web/host.json":       "",
+		"web/missing-file.js": "",
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "workspace", file))
+		if content == "" {
+			assert.True(t, os.IsNotExist(err), file)
+			continue
+		}
+		assert.NoError(t, err, file)
+		assert.Equal(t, content, string(data), file)
+	}
+}
+
+func TestExtractWorkspaceParent(t *testing.T) {
+	archive := workspaceArchive(t,
+		archiveEntry{"lib/", tar.TypeDir, ""},
+		archiveEntry{"lib/lib.go", tar.TypeReg, "lib"},
+		archiveEntry{"lib/main.go", tar.TypeSymlink, "../main.go"},
+		archiveEntry{"lib/escaped.go", tar.TypeSymlink, "../../../main.go"},
+	)
+
+	dir := t.TempDir()
+	assert.NoError(t, extractWorkspace(bytes.NewReader(archive), dir, "src", "/work/act"))
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "src", "lib", "lib.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "lib", string(data))
+	link, err := os.Readlink(filepath.Join(dir, "src", "lib", "main.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "../main.go", link)
+	_, err = os.Lstat(filepath.Join(dir, "src", "lib", "escaped.go"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRemoveEscapingLinks(t *testing.T) {
+	parent := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(parent, "host.json"), []byte("host"), 0600))
+	dir := filepath.Join(parent, "workspace")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "web"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "package.json"), []byte("package"), 0600))
+
+	// each link stays in dir on its own, but web/host.json resolves through web/root to the parent of dir
+	assert.NoError(t, os.Symlink("..", filepath.Join(dir, "web", "root")))
+	assert.NoError(t, os.Symlink("root/../host.json", filepath.Join(dir, "web", "host.json")))
+	assert.NoError(t, os.Symlink("../package.json", filepath.Join(dir, "web", "package.json")))
+	assert.NoError(t, os.Symlink("missing.json", filepath.Join(dir, "web", "missing.json")))
+
+	assert.NoError(t, removeEscapingLinks(dir))
+	for file, exists := range map[string]bool{
+		"web/root":         true,
+		"web/package.json": true,
+		"web/host.json":    false,
+		"web/missing.json": false,
+	} {
+		_, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(file)))
+		assert.Equal(t, exists, err == nil, file)
+	}
+}
+
+func TestHashFilesJobContainer(t *testing.T) {
+	workdir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(workdir, "package-lock.json"), []byte("host"), 0600))
+
+	jobContainer := &archiveContainer{}
+	rc := &RunContext{
+		Config: &Config{Workdir: workdir},
+		Run: &model.Run{
+			JobID: "job1",
+			Workflow: &model.Workflow{
+				Jobs: map[string]*model.Job{"job1": {}},
+			},
+		},
+		JobContainer: jobContainer,
+	}
+	containerWorkdir := rc.Config.ContainerWorkdir()
+	archives := map[string][]byte{
+		containerWorkdir + "/.": workspaceArchive(t,
+			archiveEntry{"./package-lock.json", tar.TypeReg, "container"},
+		),
+		containerWorkdir + "/web": workspaceArchive(t,
+			archiveEntry{"web/package-lock.json", tar.TypeReg, "web"},
+		),
+	}
+	jobContainer.archives = archives
+
+	hash := func(contents ...string) string {
+		hasher := sha256.New()
+		for _, content := range contents {
+			fileHash := sha256.Sum256([]byte(content))
+			hasher.Write(fileHash[:])
+		}
+		return hex.EncodeToString(hasher.Sum(nil))
+	}
+
+	ee := rc.NewExpressionEvaluator(context.Background())
+	assert.Equal(t, hash("container"), ee.Interpolate("${{ hashFiles('**/package-lock.json') }}"))
+	assert.Equal(t, []string{containerWorkdir + "/."}, jobContainer.srcPaths)
+
+	// only the search paths of the patterns are copied, missing ones have no files
+	jobContainer.srcPaths = nil
+	assert.Equal(t, hash("web"), ee.Interpolate("${{ hashFiles('web/**/package-lock.json', 'missing/*.json') }}"))
+	assert.Equal(t, []string{containerWorkdir + "/web", containerWorkdir + "/missing"}, jobContainer.srcPaths)
+
+	jobContainer.archives = nil
+	assert.Equal(t, hash("host"), ee.Interpolate("${{ hashFiles('**/package-lock.json') }}"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := rc.NewExpressionEvaluator(ctx).evaluate("hashFiles('**/package-lock.json')", false)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	jobContainer.archives = archives
+	rc.Config.BindWorkdir = true
+	assert.Equal(t, hash("host"), rc.NewExpressionEvaluator(context.Background()).Interpolate("${{ hashFiles('**/package-lock.json') }}"))
+}
+
+func TestHashFilesStepWorkspaceCopy(t *testing.T) {
+	jobContainer := &archiveContainer{}
+	rc := &RunContext{
+		Config: &Config{Workdir: t.TempDir()},
+		Run: &model.Run{
+			JobID: "job1",
+			Workflow: &model.Workflow{
+				Jobs: map[string]*model.Job{"job1": {}},
+			},
+		},
+		JobContainer: jobContainer,
+		workspace:    &workspaceCopy{},
+	}
+	containerWorkdir := rc.Config.ContainerWorkdir()
+	jobContainer.archives = map[string][]byte{
+		containerWorkdir + "/.": workspaceArchive(t,
+			archiveEntry{"./package-lock.json", tar.TypeReg, "root"},
+			archiveEntry{"./node_modules/a/index.js", tar.TypeReg, "a"},
+			archiveEntry{"./web/package-lock.json", tar.TypeReg, "web"},
+		),
+		containerWorkdir + "/web": workspaceArchive(t,
+			archiveEntry{"web/package-lock.json", tar.TypeReg, "web"},
+		),
+	}
+	hash := func(contents ...string) string {
+		hasher := sha256.New()
+		for _, content := range contents {
+			fileHash := sha256.Sum256([]byte(content))
+			hasher.Write(fileHash[:])
+		}
+		return hex.EncodeToString(hasher.Sum(nil))
+	}
+
+	// the expressions of a step copy the workspace once, the search paths in it aren't copied again
+	ee := rc.NewExpressionEvaluator(context.Background())
+	assert.Equal(t, hash("root", "web"), ee.Interpolate("${{ hashFiles('**/package-lock.json') }}"))
+	assert.Equal(t, hash("root", "web"), ee.Interpolate("${{ hashFiles('**/package-lock.json') }}"))
+	assert.Equal(t, hash("web"), ee.Interpolate("${{ hashFiles('web/*.json') }}"))
+	assert.Equal(t, []string{containerWorkdir + "/."}, jobContainer.srcPaths)
+	dir := rc.workspace.dir
+	assert.DirExists(t, dir)
+
+	// after the step ran the workspace is copied again
+	rc.workspace.remove()
+	assert.NoDirExists(t, dir)
+	jobContainer.srcPaths = nil
+	assert.Equal(t, hash("web"), ee.Interpolate("${{ hashFiles('web/*.json') }}"))
+	assert.Equal(t, hash("web"), ee.Interpolate("${{ hashFiles('web/package-lock.json') }}"))
+	assert.Equal(t, []string{containerWorkdir + "/web"}, jobContainer.srcPaths)
+	rc.workspace.remove()
+}
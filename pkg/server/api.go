@@ -0,0 +1,192 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+)
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Errorf("Failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, map[string]string{
+		"message": fmt.Sprintf(format, args...),
+	})
+}
+
+// authenticate requires the configured token as bearer token
+func (s *Server) authenticate(handle httprouter.Handle) httprouter.Handle {
+	if s.config.Token == "" {
+		return handle
+	}
+	expected := []byte("Bearer " + s.config.Token)
+	return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		if subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "invalid or missing bearer token")
+			return
+		}
+		handle(w, req, params)
+	}
+}
+
+func (s *Server) handleListRuns(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	writeJSON(w, http.StatusOK, s.Runs())
+}
+
+func (s *Server) handleCreateRun(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	request := &RunRequest{}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxPayloadSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request: %v", err)
+		return
+	}
+	if request.Event == "" {
+		writeError(w, http.StatusBadRequest, "missing event")
+		return
+	}
+
+	run, err := s.Enqueue(request, "")
+	if errors.Is(err, ErrQueueFull) {
+		writeError(w, http.StatusServiceUnavailable, "unable to queue run: %v", err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusBadRequest, "unable to plan event '%s': %v", request.Event, err)
+		return
+	}
+	if run == nil {
+		if request.Job != "" {
+			writeError(w, http.StatusNotFound, "job '%s' not found", request.Job)
+		} else {
+			writeError(w, http.StatusNotFound, "no workflows triggered by event '%s'", request.Event)
+		}
+		return
+	}
+
+	log.Infof("Queued run %d for event '%s'", run.ID, run.Event)
+	s.writeRun(w, http.StatusCreated, run)
+}
+
+// writeRun writes a snapshot of a run that was just queued
+func (s *Server) writeRun(w http.ResponseWriter, status int, run *Run) {
+	s.mu.RLock()
+	snapshot := run.snapshot()
+	s.mu.RUnlock()
+	w.Header().Set("Location", "/runs/"+strconv.Itoa(snapshot.ID))
+	writeJSON(w, status, snapshot)
+}
+
+func runID(w http.ResponseWriter, params httprouter.Params) (int, bool) {
+	id, err := strconv.Atoi(params.ByName("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid run id '%s'", params.ByName("id"))
+		return 0, false
+	}
+	return id, true
+}
+
+func (s *Server) handleGetRun(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	id, ok := runID(w, params)
+	if !ok {
+		return
+	}
+	run, ok := s.Run(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "run %d not found", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+func (s *Server) handleCancelRun(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	id, ok := runID(w, params)
+	if !ok {
+		return
+	}
+	run, err := s.Cancel(id)
+	if errors.Is(err, ErrRunCompleted) {
+		writeError(w, http.StatusConflict, "run %d already completed", id)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusNotFound, "%v", err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, run)
+}
+
+// findJob returns the run and job addressed by the request, the job can be narrowed down with the `workflow` query parameter
+func (s *Server) findJob(w http.ResponseWriter, req *http.Request, params httprouter.Params) (*Run, *JobRun, bool) {
+	id, ok := runID(w, params)
+	if !ok {
+		return nil, nil, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	run := s.run(id)
+	if run == nil {
+		writeError(w, http.StatusNotFound, "run %d not found", id)
+		return nil, nil, false
+	}
+
+	jobID := params.ByName("job")
+	workflow := req.URL.Query().Get("workflow")
+	var found *JobRun
+	for _, job := range run.Jobs {
+		if job.JobID != jobID || (workflow != "" && job.Workflow != workflow) {
+			continue
+		}
+		if found != nil {
+			writeError(w, http.StatusBadRequest, "job '%s' exists in multiple workflows, set the 'workflow' query parameter", jobID)
+			return nil, nil, false
+		}
+		found = job
+	}
+	if found == nil {
+		writeError(w, http.StatusNotFound, "job '%s' not found in run %d", jobID, id)
+		return nil, nil, false
+	}
+	return run, found, true
+}
+
+func (s *Server) handleGetJob(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	run, job, ok := s.findJob(w, req, params)
+	if !ok {
+		return
+	}
+	s.mu.RLock()
+	var snapshot *JobRun
+	for _, j := range run.Jobs {
+		if j == job {
+			snapshot = j.snapshot()
+			break
+		}
+	}
+	s.mu.RUnlock()
+	if snapshot == nil {
+		writeError(w, http.StatusNotFound, "job '%s' not found in run %d", job.JobID, run.ID)
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshot)
+}
+
+func (s *Server) handleJobLogs(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	run, job, ok := s.findJob(w, req, params)
+	if !ok {
+		return
+	}
+	s.streamJobLogs(w, req, run, job)
+}
@@ -0,0 +1,215 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/runner"
+)
+
+func request(s *Server, method string, path string, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rr := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rr, req)
+	return rr
+}
+
+func TestCreateRun(t *testing.T) {
+	assert := assert.New(t)
+	s, configs := newTestServer("")
+	s.config.Runner.Secrets = map[string]string{"A": "server", "B": "server"}
+
+	rr := request(s, "POST", "/runs", `{"event":"workflow_dispatch","job":"test","payload":{"ref":"refs/heads/main"},"inputs":{"name":"value"},"secrets":{"B":"run"}}`, nil)
+	assert.Equal(http.StatusCreated, rr.Code, rr.Body.String())
+	assert.Equal("/runs/1", rr.Header().Get("Location"))
+
+	run := Run{}
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &run))
+	assert.Equal("workflow_dispatch", run.Event)
+	assert.Equal("test", run.Job)
+	assert.Equal(map[string]string{"name": "value"}, run.Inputs)
+	assert.Len(run.Jobs, 2, "the job filter keeps the needed jobs")
+	assert.NotContains(rr.Body.String(), "secrets", "secrets must not be returned")
+
+	s.execute(context.Background(), <-s.queue)
+	config := (*configs)[0]
+	assert.Equal("workflow_dispatch", config.EventName)
+	assert.JSONEq(`{"ref":"refs/heads/main","inputs":{"name":"value"}}`, config.EventJSON)
+	assert.Equal(map[string]string{"A": "server", "B": "run"}, config.Secrets)
+	assert.Equal(map[string]string{"A": "server", "B": "server"}, s.config.Runner.Secrets)
+}
+
+func TestCreateRunInvalid(t *testing.T) {
+	s, _ := newTestServer("")
+	tables := []struct {
+		body   string
+		status int
+	}{
+		{`{`, http.StatusBadRequest},
+		{`{"job":"build"}`, http.StatusBadRequest},
+		{`{"event":"push","unknown":true}`, http.StatusBadRequest},
+		{`{"event":"push","payload":[],"inputs":{"a":"b"}}`, http.StatusBadRequest},
+		{`{"event":"issues"}`, http.StatusNotFound},
+		{`{"event":"push","job":"deploy"}`, http.StatusNotFound},
+	}
+	for _, table := range tables {
+		assert.Equal(t, table.status, request(s, "POST", "/runs", table.body, nil).Code, table.body)
+	}
+	assert.Empty(t, s.Runs())
+}
+
+func TestAuthentication(t *testing.T) {
+	assert := assert.New(t)
+	s, _ := newTestServer("")
+	s.config.Token = "t0k3n"
+
+	assert.Equal(http.StatusUnauthorized, request(s, "GET", "/runs", "", nil).Code)
+	assert.Equal(http.StatusUnauthorized, request(s, "GET", "/runs", "", map[string]string{"Authorization": "Bearer wrong"}).Code)
+	assert.Equal(http.StatusOK, request(s, "GET", "/runs", "", map[string]string{"Authorization": "Bearer t0k3n"}).Code)
+	// webhooks are verified by their signature instead
+	assert.Equal(http.StatusAccepted, deliver(s, "push", `{}`, "").Code)
+}
+
+func TestCancelQueuedRun(t *testing.T) {
+	assert := assert.New(t)
+	s, configs := newTestServer("")
+	assert.Equal(http.StatusCreated, request(s, "POST", "/runs", `{"event":"push"}`, nil).Code)
+
+	rr := request(s, "POST", "/runs/1/cancel", "", nil)
+	assert.Equal(http.StatusAccepted, rr.Code)
+	run, _ := s.Run(1)
+	assert.Equal(RunStatusCompleted, run.Status)
+	assert.Equal("cancelled", run.Conclusion)
+
+	s.execute(context.Background(), <-s.queue)
+	assert.Empty(*configs, "cancelled runs are not executed")
+
+	assert.Equal(http.StatusConflict, request(s, "POST", "/runs/1/cancel", "", nil).Code)
+	assert.Equal(http.StatusNotFound, request(s, "POST", "/runs/2/cancel", "", nil).Code)
+}
+
+func TestCancelRunningRun(t *testing.T) {
+	assert := assert.New(t)
+	s, _ := newTestServer("")
+	started := make(chan struct{})
+	s.newExecutor = func(config *runner.Config, run *Run) common.Executor {
+		return func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}
+	}
+	assert.Equal(http.StatusCreated, request(s, "POST", "/runs", `{"event":"push"}`, nil).Code)
+
+	done := make(chan struct{})
+	go func() {
+		s.execute(context.Background(), <-s.queue)
+		close(done)
+	}()
+	<-started
+
+	assert.Equal(http.StatusAccepted, request(s, "POST", "/runs/1/cancel", "", nil).Code)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		assert.FailNow("run was not cancelled")
+	}
+	run, _ := s.Run(1)
+	assert.Equal("cancelled", run.Conclusion)
+}
+
+func TestJobLogs(t *testing.T) {
+	assert := assert.New(t)
+	s, _ := newTestServer("")
+	proceed := make(chan struct{})
+	s.newExecutor = func(config *runner.Config, run *Run) common.Executor {
+		return func(ctx context.Context) error {
+			masks := make([]string, 0)
+			ctx = runner.WithJobLogger(ctx, "build", "push/build   ", config, &masks)
+			logger := common.Logger(ctx).WithFields(map[string]interface{}{"step": "echo", "stepID": "0"})
+			logger.WithField("raw_output", true).Infof("token is %s\n", config.Secrets["TOKEN"])
+			<-proceed
+			logger.WithFields(map[string]interface{}{
+				"stepResult":  "success",
+				"stepOutcome": "failure",
+				"stepOutputs": map[string]string{"token": config.Secrets["TOKEN"]},
+			}).Infof("Success - echo")
+			run.plan.Stages[0].Runs[0].Job().Result = "success"
+			return nil
+		}
+	}
+	assert.Equal(http.StatusCreated, request(s, "POST", "/runs", `{"event":"push","secrets":{"TOKEN":"s3cr3t"}}`, nil).Code)
+	go s.execute(context.Background(), <-s.queue)
+
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/runs/1/jobs/build/logs")
+	assert.NoError(err)
+	defer resp.Body.Close()
+	assert.Equal("text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := make([]string, 0)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "data: ") {
+			events = append(events, strings.TrimPrefix(line, "data: "))
+			if len(events) == 1 {
+				// the second line is only logged after the first one was streamed
+				close(proceed)
+			}
+		}
+	}
+	assert.Len(events, 3)
+	logLine := LogLine{}
+	assert.NoError(json.Unmarshal([]byte(events[0]), &logLine))
+	assert.Equal("push/build", logLine.Job)
+	assert.Equal("echo", logLine.Step)
+	assert.Equal("token is ***", logLine.Message)
+	assert.True(logLine.RawOutput)
+	assert.JSONEq(`{"result":"success"}`, events[2])
+
+	rr := request(s, "GET", "/runs/1/jobs/build", "", nil)
+	assert.Equal(http.StatusOK, rr.Code)
+	job := JobRun{}
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &job))
+	assert.Equal("success", job.Result)
+	assert.Equal([]*StepRun{{
+		Job:        "push/build",
+		ID:         "0",
+		Name:       "echo",
+		Outcome:    "failure",
+		Conclusion: "success",
+		Outputs:    map[string]string{"token": "***"},
+	}}, job.Steps)
+
+	// resuming skips the events the client already received
+	req, _ := http.NewRequest("GET", server.URL+"/runs/1/jobs/build/logs", nil)
+	req.Header.Set("Last-Event-ID", "0")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(err)
+	defer resp.Body.Close()
+	scanner = bufio.NewScanner(resp.Body)
+	ids := make([]string, 0)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "id: ") {
+			ids = append(ids, strings.TrimPrefix(scanner.Text(), "id: "))
+		}
+	}
+	assert.Equal([]string{"1"}, ids)
+
+	assert.Equal(http.StatusNotFound, request(s, "GET", "/runs/1/jobs/deploy", "", nil).Code)
+	assert.Equal(http.StatusNotFound, request(s, "GET", "/runs/2/jobs/build/logs", "", nil).Code)
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nektos/act/pkg/runner"
+	log "github.com/sirupsen/logrus"
+)
+
+// LogLine is a line of the log of a job
+type LogLine struct {
+	Time      time.Time `json:"time"`
+	Job       string    `json:"job"` // name of the job, includes the index of the matrix
+	Step      string    `json:"step,omitempty"`
+	Level     string    `json:"level"`
+	Message   string    `json:"msg"`
+	RawOutput bool      `json:"raw_output,omitempty"`
+}
+
+// runLogWriter collects the json logs of the jobs of a run
+type runLogWriter struct {
+	server *Server
+	run    *Run
+	buf    []byte
+}
+
+func (w *runLogWriter) Write(p []byte) (int, error) {
+	w.server.mu.Lock()
+	defer w.server.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := w.buf[:i]
+		w.buf = w.buf[i+1:]
+
+		entry := runner.LogEntry{}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			log.Debugf("Ignoring invalid log entry of run %d: %v", w.run.ID, err)
+			continue
+		}
+		w.add(&entry)
+	}
+	return len(p), nil
+}
+
+// add stores the entry with its job, the lock has to be held
+func (w *runLogWriter) add(entry *runner.LogEntry) {
+	job := w.run.findJob(entry.JobID, strings.TrimSpace(entry.Job))
+	logLine := LogLine{
+		Time:      entry.Time,
+		Job:       strings.TrimSpace(entry.Job),
+		Step:      entry.Step,
+		Level:     entry.Level,
+		Message:   strings.TrimSuffix(entry.Message, "\n"),
+		RawOutput: entry.RawOutput,
+	}
+	job.logs = append(job.logs, logLine)
+
+	if entry.StepResult != "" {
+		job.Steps = append(job.Steps, &StepRun{
+			Job:        logLine.Job,
+			ID:         entry.StepID,
+			Name:       entry.Step,
+			Outcome:    entry.StepOutcome,
+			Conclusion: entry.StepResult,
+			Outputs:    entry.StepOutputs,
+		})
+	}
+
+	if logLine.RawOutput {
+		fmt.Fprintf(w.server.output, "[run %d] [%s]   | %s\n", w.run.ID, logLine.Job, logLine.Message)
+	} else {
+		fmt.Fprintf(w.server.output, "[run %d] [%s] %s\n", w.run.ID, logLine.Job, logLine.Message)
+	}
+	w.run.notify()
+}
+
+// findJob returns the job a log line belongs to, jobs of chained workflows are added on their first line
+func (run *Run) findJob(jobID string, jobName string) *JobRun {
+	for _, job := range run.Jobs {
+		if job.JobID == jobID && strings.HasPrefix(jobName, job.Workflow+"/") {
+			return job
+		}
+	}
+	job := &JobRun{
+		JobID: jobID,
+		Name:  jobName,
+		Steps: make([]*StepRun, 0),
+	}
+	if i := strings.Index(jobName, "/"); i >= 0 {
+		job.Workflow = jobName[:i]
+	}
+	run.Jobs = append(run.Jobs, job)
+	return job
+}
+
+// streamJobLogs streams the log lines of a job as server-sent events until the run completed
+func (s *Server) streamJobLogs(w http.ResponseWriter, req *http.Request, run *Run, job *JobRun) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	// clients resume with the id of the last event they received
+	next := 0
+	if lastEventID, err := strconv.Atoi(req.Header.Get("Last-Event-ID")); err == nil {
+		next = lastEventID + 1
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	for {
+		s.mu.RLock()
+		var lines []LogLine
+		if next < len(job.logs) {
+			lines = append(lines, job.logs[next:]...)
+		}
+		completed := run.Status == RunStatusCompleted
+		result := job.Result
+		updated := run.updated
+		s.mu.RUnlock()
+
+		for _, line := range lines {
+			data, err := json.Marshal(line)
+			if err != nil {
+				log.Errorf("Failed to write log line: %v", err)
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: log\ndata: %s\n\n", next, data)
+			next++
+		}
+
+		if completed {
+			data, _ := json.Marshal(map[string]string{"result": result})
+			fmt.Fprintf(w, "event: end\ndata: %s\n\n", data)
+			flusher.Flush()
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-updated:
+		case <-req.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,433 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+)
+
+// Config contains the config for a new server
+type Config struct {
+	Runner            *runner.Config // template for the config of every run
+	WorkflowsPath     string         // path to workflow file(s)
+	NoWorkflowRecurse bool           // don't load workflows from subdirectories of WorkflowsPath
+	Secret            string         // shared secret to verify webhook signatures with, verification is disabled if empty
+	Token             string         // bearer token required by the API, authentication is disabled if empty
+	Concurrency       int            // number of runs executed at the same time
+	QueueSize         int            // number of runs waiting for execution before deliveries are rejected
+	ChainDepth        int            // maximum number of chained `workflow_run` levels, 0 disables chaining
+}
+
+var (
+	// ErrQueueFull is returned by Enqueue if the queue has no room for another run
+	ErrQueueFull = errors.New("queue is full")
+	// ErrRunCompleted is returned by Cancel if the run already completed
+	ErrRunCompleted = errors.New("run already completed")
+)
+
+// Run statuses, following the GitHub check run statuses
+const (
+	RunStatusQueued     = "queued"
+	RunStatusInProgress = "in_progress"
+	RunStatusCompleted  = "completed"
+)
+
+// RunRequest describes the event a run is started for
+type RunRequest struct {
+	Event   string            `json:"event"`             // name of the event
+	Payload json.RawMessage   `json:"payload,omitempty"` // event payload, defaults to an empty object
+	Job     string            `json:"job,omitempty"`     // only run the job with this id
+	Inputs  map[string]string `json:"inputs,omitempty"`  // inputs added to the payload like for `workflow_dispatch`
+	Secrets map[string]string `json:"secrets,omitempty"` // secrets added to the secrets of the server
+}
+
+// Run is a queued, running or completed execution of the workflows triggered by an event
+type Run struct {
+	ID          int               `json:"id"`
+	Event       string            `json:"event"`
+	Delivery    string            `json:"delivery,omitempty"`
+	Job         string            `json:"job,omitempty"`
+	Inputs      map[string]string `json:"inputs,omitempty"`
+	Status      string            `json:"status"`
+	Conclusion  string            `json:"conclusion,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	Jobs        []*JobRun         `json:"jobs"`
+	CreatedAt   time.Time         `json:"created_at"`
+	StartedAt   *time.Time        `json:"started_at,omitempty"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+
+	eventJSON string
+	secrets   map[string]string
+	plan      *model.Plan
+	planner   model.WorkflowPlanner
+	cancel    context.CancelFunc
+	cancelled bool
+	// updated is closed and replaced whenever logs are added or the run completes
+	updated chan struct{}
+}
+
+// JobRun is a job planned for a run
+type JobRun struct {
+	Workflow string            `json:"workflow"`
+	JobID    string            `json:"job_id"`
+	Name     string            `json:"name"`
+	Result   string            `json:"result,omitempty"`
+	Outputs  map[string]string `json:"outputs,omitempty"`
+	Steps    []*StepRun        `json:"steps"`
+
+	logs []LogLine
+}
+
+// StepRun is the result of an executed step of a job
+type StepRun struct {
+	Job        string            `json:"job"` // name of the job, includes the index of the matrix
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Outcome    string            `json:"outcome"`
+	Conclusion string            `json:"conclusion"`
+	Outputs    map[string]string `json:"outputs"`
+}
+
+// Server executes workflows for the events it receives
+type Server struct {
+	config *Config
+	queue  chan *Run
+	output io.Writer // where the logs of all runs are echoed to
+
+	mu     sync.RWMutex
+	runs   []*Run
+	nextID int
+
+	// newExecutor creates the executor of a run, replaced in tests
+	newExecutor func(config *runner.Config, run *Run) common.Executor
+}
+
+// New creates a new server
+func New(config *Config) *Server {
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 100
+	}
+	s := &Server{
+		config: config,
+		queue:  make(chan *Run, config.QueueSize),
+		output: os.Stdout,
+		nextID: 1,
+	}
+	s.newExecutor = s.newRunExecutor
+	return s
+}
+
+// Handler returns the http handler of the server
+func (s *Server) Handler() http.Handler {
+	router := httprouter.New()
+	router.POST("/webhook", s.handleWebhook)
+	router.GET("/runs", s.authenticate(s.handleListRuns))
+	router.POST("/runs", s.authenticate(s.handleCreateRun))
+	router.GET("/runs/:id", s.authenticate(s.handleGetRun))
+	router.POST("/runs/:id/cancel", s.authenticate(s.handleCancelRun))
+	router.GET("/runs/:id/jobs/:job", s.authenticate(s.handleGetJob))
+	router.GET("/runs/:id/jobs/:job/logs", s.authenticate(s.handleJobLogs))
+	return router
+}
+
+// Serve listens on addr and executes queued runs until the context is cancelled
+func (s *Server) Serve(ctx context.Context, addr string) error {
+	for i := 0; i < s.config.Concurrency; i++ {
+		go s.work(ctx)
+	}
+
+	server := &http.Server{Addr: addr, Handler: s.Handler()}
+	go func() {
+		<-ctx.Done()
+		if err := server.Shutdown(context.Background()); err != nil {
+			log.Errorf("Failed shutdown gracefully - force shutdown: %v", err)
+			server.Close()
+		}
+	}()
+
+	log.Infof("Start server on http://%s", addr)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Enqueue plans the workflows for the request and queues their execution, the run is nil if no workflow is triggered
+func (s *Server) Enqueue(request *RunRequest, delivery string) (*Run, error) {
+	eventJSON, err := request.eventJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	planner, err := model.NewWorkflowPlanner(s.config.WorkflowsPath, s.config.NoWorkflowRecurse)
+	if err != nil {
+		return nil, err
+	}
+	var plan *model.Plan
+	if request.Job != "" {
+		plan = planner.PlanJob(request.Job)
+	} else {
+		plan = planner.PlanEvent(request.Event)
+	}
+	if len(plan.Stages) == 0 {
+		return nil, nil
+	}
+
+	run := &Run{
+		Event:     request.Event,
+		Delivery:  delivery,
+		Job:       request.Job,
+		Inputs:    request.Inputs,
+		Status:    RunStatusQueued,
+		CreatedAt: time.Now(),
+		eventJSON: eventJSON,
+		secrets:   request.Secrets,
+		plan:      plan,
+		planner:   planner,
+		updated:   make(chan struct{}),
+	}
+	for _, stage := range plan.Stages {
+		for _, r := range stage.Runs {
+			run.Jobs = append(run.Jobs, &JobRun{
+				Workflow: r.Workflow.Name,
+				JobID:    r.JobID,
+				Name:     r.String(),
+				Steps:    make([]*StepRun, 0),
+			})
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case s.queue <- run:
+	default:
+		return nil, ErrQueueFull
+	}
+	run.ID = s.newRunID()
+	s.runs = append(s.runs, run)
+	return run, nil
+}
+
+// eventJSON returns the payload with the inputs added
+func (request *RunRequest) eventJSON() (string, error) {
+	if len(request.Inputs) == 0 && len(request.Payload) > 0 {
+		return string(request.Payload), nil
+	}
+
+	payload := make(map[string]interface{})
+	if len(request.Payload) > 0 {
+		if err := json.Unmarshal(request.Payload, &payload); err != nil {
+			return "", fmt.Errorf("payload is not a JSON object: %v", err)
+		}
+	}
+	if len(request.Inputs) > 0 {
+		payload["inputs"] = request.Inputs
+	}
+	eventJSON, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return string(eventJSON), nil
+}
+
+// Runs returns a snapshot of all runs
+func (s *Server) Runs() []Run {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	runs := make([]Run, 0, len(s.runs))
+	for _, run := range s.runs {
+		runs = append(runs, run.snapshot())
+	}
+	return runs
+}
+
+// Run returns a snapshot of the run with the given id
+func (s *Server) Run(id int) (Run, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if run := s.run(id); run != nil {
+		return run.snapshot(), true
+	}
+	return Run{}, false
+}
+
+// Cancel cancels a queued or running run
+func (s *Server) Cancel(id int) (Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run := s.run(id)
+	if run == nil {
+		return Run{}, fmt.Errorf("run %d not found", id)
+	}
+
+	switch {
+	case run.Status == RunStatusCompleted:
+		return run.snapshot(), ErrRunCompleted
+	case run.Status == RunStatusQueued:
+		// the worker skips the run once it is dequeued
+		run.complete("cancelled", nil)
+	case !run.cancelled:
+		log.Infof("Cancelling run %d", run.ID)
+		run.cancel()
+	}
+	run.cancelled = true
+	return run.snapshot(), nil
+}
+
+func (s *Server) run(id int) *Run {
+	for _, run := range s.runs {
+		if run.ID == id {
+			return run
+		}
+	}
+	return nil
+}
+
+func (run *Run) snapshot() Run {
+	snapshot := *run
+	snapshot.Jobs = make([]*JobRun, 0, len(run.Jobs))
+	for _, job := range run.Jobs {
+		snapshot.Jobs = append(snapshot.Jobs, job.snapshot())
+	}
+	return snapshot
+}
+
+// snapshot returns a copy of the job and its steps without its logs, the lock has to be held
+func (job *JobRun) snapshot() *JobRun {
+	j := *job
+	j.Steps = make([]*StepRun, 0, len(job.Steps))
+	for _, step := range job.Steps {
+		st := *step
+		j.Steps = append(j.Steps, &st)
+	}
+	j.logs = nil
+	return &j
+}
+
+// notify wakes up everyone waiting for updates of the run, the lock has to be held
+func (run *Run) notify() {
+	close(run.updated)
+	run.updated = make(chan struct{})
+}
+
+// complete marks the run as completed, the lock has to be held
+func (run *Run) complete(conclusion string, err error) {
+	completedAt := time.Now()
+	run.Status = RunStatusCompleted
+	run.Conclusion = conclusion
+	run.CompletedAt = &completedAt
+	if err != nil {
+		run.Error = err.Error()
+	}
+	run.notify()
+}
+
+func (s *Server) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case run := <-s.queue:
+			s.execute(ctx, run)
+		}
+	}
+}
+
+func (s *Server) execute(ctx context.Context, run *Run) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.mu.Lock()
+	if run.cancelled {
+		s.mu.Unlock()
+		return
+	}
+	startedAt := time.Now()
+	run.Status = RunStatusInProgress
+	run.StartedAt = &startedAt
+	run.cancel = cancel
+	config := *s.config.Runner
+	s.mu.Unlock()
+
+	config.EventName = run.Event
+	config.EventPath = ""
+	config.EventJSON = run.eventJSON
+	config.Env = mergeMaps(config.Env, map[string]string{
+		"GITHUB_RUN_ID":     strconv.Itoa(run.ID),
+		"GITHUB_RUN_NUMBER": strconv.Itoa(run.ID),
+	})
+	config.Secrets = mergeMaps(config.Secrets, run.secrets)
+	// the containers of concurrent runs of the same jobs need different names
+	config.ContainerNamePrefix = fmt.Sprintf("act-%d", run.ID)
+	// the chained workflow_run runs take their ids from the same counter as the queued runs
+	config.NextRunID = func() int {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.newRunID()
+	}
+	// the json logger masks secrets and keeps the job and step of every line
+	config.JSONLogger = true
+	config.LogWriter = &runLogWriter{server: s, run: run}
+
+	log.Infof("Starting run %d for event '%s'", run.ID, run.Event)
+	err := s.newExecutor(&config, run)(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := 0
+	for _, stage := range run.plan.Stages {
+		for _, r := range stage.Runs {
+			run.Jobs[i].Result = r.Job().Result
+			run.Jobs[i].Outputs = r.Job().Outputs
+			i++
+		}
+	}
+	switch {
+	case ctx.Err() != nil:
+		run.complete("cancelled", nil)
+	case err != nil:
+		run.complete("failure", err)
+	default:
+		run.complete("success", nil)
+	}
+	log.Infof("Completed run %d for event '%s' with '%s'", run.ID, run.Event, run.Conclusion)
+}
+
+// newRunID returns the id of the next run, the caller holds s.mu
+func (s *Server) newRunID() int {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+func (s *Server) newRunExecutor(config *runner.Config, run *Run) common.Executor {
+	return runner.NewChainedPlanExecutor(config, run.planner, run.plan, s.config.ChainDepth)
+}
+
+func mergeMaps(maps ...map[string]string) map[string]string {
+	rtnMap := make(map[string]string)
+	for _, m := range maps {
+		for k, v := range m {
+			rtnMap[k] = v
+		}
+	}
+	return rtnMap
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/runner"
+)
+
+func newTestServer(secret string) (*Server, *[]*runner.Config) {
+	s := New(&Config{
+		Runner:        &runner.Config{Env: map[string]string{"FOO": "bar"}},
+		WorkflowsPath: "testdata/workflows",
+		Secret:        secret,
+		QueueSize:     2,
+	})
+	s.output = io.Discard
+	configs := make([]*runner.Config, 0)
+	s.newExecutor = func(config *runner.Config, run *Run) common.Executor {
+		return func(ctx context.Context) error {
+			configs = append(configs, config)
+			for _, stage := range run.plan.Stages {
+				for _, r := range stage.Runs {
+					r.Job().Result = "success"
+				}
+			}
+			return nil
+		}
+	}
+	return s, &configs
+}
+
+func deliver(s *Server, event string, body string, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+	if event != "" {
+		req.Header.Set("X-GitHub-Event", event)
+	}
+	if signature != "" {
+		req.Header.Set("X-Hub-Signature-256", signature)
+	}
+	req.Header.Set("X-GitHub-Delivery", "72d3162e")
+	rr := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rr, req)
+	return rr
+}
+
+func sign(secret string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhook(t *testing.T) {
+	assert := assert.New(t)
+	s, configs := newTestServer("")
+
+	body := `{"ref":"refs/heads/main"}`
+	rr := deliver(s, "push", body, "")
+	assert.Equal(http.StatusAccepted, rr.Code)
+	assert.Equal("/runs/1", rr.Header().Get("Location"))
+
+	run := Run{}
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &run))
+	assert.Equal(1, run.ID)
+	assert.Equal("push", run.Event)
+	assert.Equal("72d3162e", run.Delivery)
+	assert.Equal(RunStatusQueued, run.Status)
+	assert.Len(run.Jobs, 2)
+
+	s.execute(context.Background(), <-s.queue)
+	assert.Len(*configs, 1)
+	config := (*configs)[0]
+	assert.Equal("push", config.EventName)
+	assert.Equal(body, config.EventJSON)
+	assert.Equal("1", config.Env["GITHUB_RUN_ID"])
+	assert.Equal("bar", config.Env["FOO"])
+	assert.Empty(s.config.Runner.Env["GITHUB_RUN_ID"], "template config must not be modified")
+
+	rr = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/runs/1", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &run))
+	assert.Equal(RunStatusCompleted, run.Status)
+	assert.Equal("success", run.Conclusion)
+	assert.Equal("success", run.Jobs[0].Result)
+	assert.NotNil(run.CompletedAt)
+}
+
+func TestConcurrentRuns(t *testing.T) {
+	assert := assert.New(t)
+	s, configs := newTestServer("")
+
+	assert.Equal(http.StatusAccepted, deliver(s, "push", `{}`, "").Code)
+	assert.Equal(http.StatusAccepted, deliver(s, "push", `{}`, "").Code)
+	first, second := <-s.queue, <-s.queue
+	s.execute(context.Background(), first)
+	s.execute(context.Background(), second)
+
+	assert.Len(*configs, 2)
+	assert.Equal("act-1", (*configs)[0].ContainerNamePrefix)
+	assert.Equal("act-2", (*configs)[1].ContainerNamePrefix)
+
+	// chained runs share the counter of the queued runs
+	assert.Equal(3, (*configs)[0].NextRunID())
+	assert.Equal(http.StatusAccepted, deliver(s, "push", `{}`, "").Code)
+	assert.Equal(4, (<-s.queue).ID)
+}
+
+func TestWebhookNoWorkflows(t *testing.T) {
+	s, _ := newTestServer("")
+	rr := deliver(s, "pull_request", `{}`, "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"triggered": false, "message": "no workflows triggered by event 'pull_request'"}`, rr.Body.String())
+	assert.Empty(t, s.Runs())
+}
+
+func TestWebhookInvalid(t *testing.T) {
+	s, _ := newTestServer("")
+	assert.Equal(t, http.StatusBadRequest, deliver(s, "", `{}`, "").Code)
+	assert.Equal(t, http.StatusBadRequest, deliver(s, "push", `not json`, "").Code)
+	assert.Empty(t, s.Runs())
+}
+
+func TestWebhookSignature(t *testing.T) {
+	s, _ := newTestServer("s3cr3t")
+	body := `{"ref":"refs/heads/main"}`
+
+	tables := []struct {
+		signature string
+		status    int
+	}{
+		{"", http.StatusUnauthorized},
+		{"sha256=zz", http.StatusUnauthorized},
+		{sign("wrong", body), http.StatusUnauthorized},
+		{strings.Replace(sign("s3cr3t", body), "sha256=", "sha1=", 1), http.StatusUnauthorized},
+		{sign("s3cr3t", body), http.StatusAccepted},
+	}
+	for _, table := range tables {
+		assert.Equal(t, table.status, deliver(s, "push", body, table.signature).Code, table.signature)
+	}
+}
+
+func TestWebhookQueueFull(t *testing.T) {
+	assert := assert.New(t)
+	s, _ := newTestServer("")
+	assert.Equal(http.StatusAccepted, deliver(s, "push", `{}`, "").Code)
+	assert.Equal(http.StatusAccepted, deliver(s, "push", `{}`, "").Code)
+	assert.Equal(http.StatusServiceUnavailable, deliver(s, "push", `{}`, "").Code)
+
+	runs := s.Runs()
+	assert.Len(runs, 2)
+	assert.Equal(1, runs[0].ID)
+	assert.Equal(2, runs[1].ID)
+}
+
+func TestGetRunNotFound(t *testing.T) {
+	s, _ := newTestServer("")
+	for path, status := range map[string]int{"/runs/1": http.StatusNotFound, "/runs/abc": http.StatusBadRequest} {
+		rr := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, status, rr.Code, path)
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxPayloadSize is the maximum size of a webhook payload accepted by GitHub
+const maxPayloadSize = 25 << 20
+
+func (s *Server) handleWebhook(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	eventName := req.Header.Get("X-GitHub-Event")
+	if eventName == "" {
+		writeError(w, http.StatusBadRequest, "missing X-GitHub-Event header")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxPayloadSize+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "unable to read payload: %v", err)
+		return
+	}
+	if len(body) > maxPayloadSize {
+		writeError(w, http.StatusRequestEntityTooLarge, "payload exceeds %d bytes", maxPayloadSize)
+		return
+	}
+
+	if s.config.Secret != "" && !verifySignature(s.config.Secret, req.Header.Get("X-Hub-Signature-256"), body) {
+		writeError(w, http.StatusUnauthorized, "invalid X-Hub-Signature-256 header")
+		return
+	}
+
+	if !json.Valid(body) {
+		writeError(w, http.StatusBadRequest, "payload is not valid JSON")
+		return
+	}
+
+	delivery := req.Header.Get("X-GitHub-Delivery")
+	log.Debugf("Received '%s' event (delivery '%s')", eventName, delivery)
+
+	run, err := s.Enqueue(&RunRequest{Event: eventName, Payload: body}, delivery)
+	if errors.Is(err, ErrQueueFull) {
+		writeError(w, http.StatusServiceUnavailable, "unable to queue run: %v", err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to plan event '%s': %v", eventName, err)
+		return
+	}
+	if run == nil {
+		// GitHub treats any other status as a failed delivery, a skipped event is answered with a normal response
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"triggered": false,
+			"message":   fmt.Sprintf("no workflows triggered by event '%s'", eventName),
+		})
+		return
+	}
+
+	log.Infof("Queued run %d for event '%s'", run.ID, eventName)
+	s.writeRun(w, http.StatusAccepted, run)
+}
+
+// verifySignature checks the `sha256=<hex>` HMAC signature GitHub computes over the payload with the webhook secret
+// Reference: https://docs.github.com/en/developers/webhooks-and-events/webhooks/securing-your-webhooks
+func verifySignature(secret string, signature string, body []byte) bool {
+	if !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/nektos/act/pkg/model"
+)
+
+const (
+	graphBranch     = "├─▶ "
+	graphLastBranch = "└─▶ "
+	graphLine       = "│   "
+	graphSpace      = "    "
+)
+
+// statusPriority orders the statuses of the runs of a matrix, the first one found is the status of the job in the graph
+var statusPriority = []status{statusRunning, statusFailure, statusCancelled, statusPending, statusSuccess, statusSkipped}
+
+// planGraph returns the dependency graph of the planned jobs, the lock has to be held
+func (ui *UI) planGraph() *model.Graph {
+	plan := &model.Plan{}
+	for _, j := range ui.jobs {
+		for len(plan.Stages) <= j.stage {
+			plan.Stages = append(plan.Stages, &model.Stage{})
+		}
+		stage := plan.Stages[j.stage]
+		planned := false
+		for _, run := range stage.Runs {
+			planned = planned || run.Workflow == j.run.Workflow && run.JobID == j.run.JobID
+		}
+		if !planned {
+			stage.Runs = append(stage.Runs, j.run)
+		}
+	}
+	return model.NewGraph(plan)
+}
+
+// nodeJobs returns the runs of the matrix of the job of a node, the lock has to be held
+func (ui *UI) nodeJobs(node *model.GraphNode) []*job {
+	jobs := make([]*job, 0)
+	for _, j := range ui.jobs {
+		if j.run.JobID == node.JobID && j.run.Workflow.Name == node.Workflow && j.run.Workflow.File == node.File {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs
+}
+
+// nodeStatus returns the status of the job of a node from the runs of its matrix
+func nodeStatus(jobs []*job) status {
+	for _, s := range statusPriority {
+		for _, j := range jobs {
+			if j.status == s {
+				return s
+			}
+		}
+	}
+	return statusPending
+}
+
+// graphLines renders the jobs of the plan as trees from the jobs that need no other job to the jobs that need them,
+// a job needing several jobs is expanded below the first one, the lock has to be held
+func (ui *UI) graphLines(width int, height int, now time.Time) []string {
+	graph := ui.planGraph()
+	nodes := make(map[string]*model.GraphNode)
+	for _, node := range graph.Nodes {
+		nodes[node.ID] = node
+	}
+	children := make(map[string][]*model.GraphNode)
+	needsJobs := make(map[string]bool)
+	for _, edge := range graph.Edges {
+		children[edge.From] = append(children[edge.From], nodes[edge.To])
+		needsJobs[edge.To] = true
+	}
+	selected, _ := ui.selectedJob()
+
+	lines := []string{styleBold + fit(" Plan graph", width) + styleReset}
+	shown := make(map[string]bool)
+	var addNode func(node *model.GraphNode, prefix string, branch string)
+	addNode = func(node *model.GraphNode, prefix string, branch string) {
+		jobs := ui.nodeJobs(node)
+		name := node.Name
+		if shown[node.ID] {
+			name += " (above)"
+		}
+		nameWidth := width - len([]rune(prefix+branch)) - 2
+		text := fit(name, nameWidth)
+		for _, j := range jobs {
+			if j == selected {
+				text = styleReverse + text + styleReset
+				break
+			}
+		}
+		if shown[node.ID] {
+			text = styleDim + text + styleReset
+		}
+		lines = append(lines, prefix+branch+icon(nodeStatus(jobs), now)+" "+text)
+		if shown[node.ID] {
+			return
+		}
+		shown[node.ID] = true
+
+		switch branch {
+		case graphBranch:
+			prefix += graphLine
+		case graphLastBranch:
+			prefix += graphSpace
+		}
+		for i, child := range children[node.ID] {
+			childBranch := graphBranch
+			if i == len(children[node.ID])-1 {
+				childBranch = graphLastBranch
+			}
+			addNode(child, prefix, childBranch)
+		}
+	}
+
+	workflow := ""
+	for _, node := range graph.Nodes {
+		if needsJobs[node.ID] {
+			continue
+		}
+		if key := strings.TrimSuffix(node.ID, "/"+node.JobID); key != workflow {
+			workflow = key
+			lines = append(lines, styleBold+fit(" "+node.Workflow, width)+styleReset)
+		}
+		addNode(node, " ", "")
+	}
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return lines
+}
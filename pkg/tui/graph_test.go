@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+)
+
+func TestGraphLines(t *testing.T) {
+	ctx := context.Background()
+	needs := func(jobs ...string) yaml.Node {
+		node := yaml.Node{Kind: yaml.SequenceNode}
+		for _, job := range jobs {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: job})
+		}
+		return node
+	}
+	workflow := &model.Workflow{
+		Name: "ci",
+		File: "ci.yml",
+		Jobs: map[string]*model.Job{
+			"build":  {},
+			"lint":   {},
+			"test":   {RawNeeds: needs("build", "lint")},
+			"deploy": {RawNeeds: needs("test")},
+		},
+	}
+	run := func(jobID string) *model.Run {
+		return &model.Run{Workflow: workflow, JobID: jobID}
+	}
+	plan := &model.Plan{Stages: []*model.Stage{
+		{Runs: []*model.Run{run("build"), run("lint")}},
+		{Runs: []*model.Run{run("test")}},
+		{Runs: []*model.Run{run("deploy")}},
+	}}
+
+	ui := New(nil, nil)
+	ui.PlanStarted(ctx, &runner.PlanEvent{Plan: plan})
+	build := &runner.JobEvent{Run: plan.Stages[0].Runs[0], Name: "ci/build"}
+	ui.JobStarted(ctx, build)
+	ui.JobCompleted(ctx, &runner.JobEvent{Run: build.Run, Name: "ci/build", Result: "success"})
+	// the runs of a matrix show the status of the job that matters most
+	lint := plan.Stages[0].Runs[1]
+	ui.JobStarted(ctx, &runner.JobEvent{Run: lint, Name: "ci/lint-1"})
+	ui.JobStarted(ctx, &runner.JobEvent{Run: lint, Name: "ci/lint-2"})
+	ui.JobCompleted(ctx, &runner.JobEvent{Run: lint, Name: "ci/lint-1", Result: "failure"})
+
+	lines := plainFrame(strings.Join(ui.graphLines(30, 9, time.Unix(0, 0)), "\r\n"))
+	assert.Equal(t, []string{
+		" Plan graph                   ",
+		" ci                           ",
+		" ✓ build                      ",
+		" └─▶ · test                   ",
+		"     └─▶ · deploy             ",
+		" ⠋ lint                       ",
+		" └─▶ · test (above)           ",
+		"                              ",
+		"                              ",
+	}, lines)
+
+	// the graph replaces the log next to the job list
+	ui.showGraph = true
+	frame := plainFrame(ui.render(70, 6, time.Unix(0, 0)))
+	for i, expected := range []string{"│ Plan graph", "│ ci", "│ ✓ build"} {
+		line := strings.TrimRight(frame[i+1], " ")
+		assert.Equal(t, expected, line[strings.Index(line, "│"):])
+	}
+}
@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"io"
+	"sync"
+)
+
+// key is a key pressed by the user, either a printable character or the name of a special key
+type key string
+
+const (
+	keyUp       key = "up"
+	keyDown     key = "down"
+	keyPageUp   key = "pgup"
+	keyPageDown key = "pgdown"
+	keyHome     key = "home"
+	keyEnd      key = "end"
+	keyCtrlC    key = "ctrl-c"
+)
+
+type action int
+
+const (
+	actionNone action = iota
+	actionQuit
+	actionShell
+)
+
+var escapeSequences = map[string]key{
+	"\x1b[A":  keyUp,
+	"\x1bOA":  keyUp,
+	"\x1b[B":  keyDown,
+	"\x1bOB":  keyDown,
+	"\x1b[5~": keyPageUp,
+	"\x1b[6~": keyPageDown,
+	"\x1b[H":  keyHome,
+	"\x1b[1~": keyHome,
+	"\x1b[F":  keyEnd,
+	"\x1b[4~": keyEnd,
+}
+
+// parseKeys returns the keys of the input read from a terminal in raw mode, unknown escape sequences are dropped
+func parseKeys(b []byte) []key {
+	keys := make([]key, 0)
+	for len(b) > 0 {
+		if b[0] != '\x1b' {
+			switch {
+			case b[0] == 3:
+				keys = append(keys, keyCtrlC)
+			case b[0] >= ' ' && b[0] < 127:
+				keys = append(keys, key(b[:1]))
+			}
+			b = b[1:]
+			continue
+		}
+
+		matched := false
+		for seq, k := range escapeSequences {
+			if len(b) >= len(seq) && string(b[:len(seq)]) == seq {
+				keys = append(keys, k)
+				b = b[len(seq):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			// skip the unknown sequence up to its final byte
+			i := 1
+			if len(b) > 1 && (b[1] == '[' || b[1] == 'O') {
+				for i = 2; i < len(b) && (b[i] < 0x40 || b[i] > 0x7e); i++ {
+				}
+				i++
+			}
+			if i > len(b) {
+				i = len(b)
+			}
+			b = b[i:]
+		}
+	}
+	return keys
+}
+
+// shellInput passes the input to an open shell instead of the UI
+type shellInput struct {
+	mu sync.Mutex
+	w  *io.PipeWriter
+}
+
+// start returns the reader of the input for a shell
+func (s *shellInput) start() io.Reader {
+	r, w := io.Pipe()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.w = w
+	return r
+}
+
+// stop passes the input to the UI again
+func (s *shellInput) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.w != nil {
+		s.w.Close()
+		s.w = nil
+	}
+}
+
+// write passes p to the shell, it returns false if no shell is open
+func (s *shellInput) write(p []byte) bool {
+	s.mu.Lock()
+	w := s.w
+	s.mu.Unlock()
+	if w == nil {
+		return false
+	}
+	_, _ = w.Write(p)
+	return true
+}
+
+// readKeys reads the input until it is closed and sends the keys to the UI, or the input to an open shell
+func readKeys(in io.Reader, keys chan<- key, shell *shellInput) {
+	buf := make([]byte, 256)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return
+		}
+		if shell.write(buf[:n]) {
+			continue
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			keys <- k
+		}
+	}
+}
+
+// handleKey changes the selection or acts on the selected job
+func (ui *UI) handleKey(k key) action {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
+	ui.message = ""
+	page := ui.logHeight - 1
+	if page < 1 {
+		page = 1
+	}
+	switch k {
+	case keyUp, "k":
+		ui.moveSelection(-1)
+	case keyDown, "j":
+		ui.moveSelection(1)
+	case keyPageUp:
+		ui.scroll += page
+	case keyPageDown:
+		ui.scroll -= page
+		if ui.scroll < 0 {
+			ui.scroll = 0
+		}
+	case keyHome, "g":
+		ui.scroll = maxLogLines
+	case keyEnd, "G":
+		ui.scroll = 0
+	case "c":
+		ui.cancelJob()
+	case "r":
+		ui.rerunJob()
+	case "s":
+		return actionShell
+	case "p":
+		ui.showGraph = !ui.showGraph
+	case "q", keyCtrlC:
+		return actionQuit
+	}
+	return actionNone
+}
+
+// moveSelection selects the row before or after the selected row, the lock has to be held
+func (ui *UI) moveSelection(delta int) {
+	rows := ui.rows()
+	i := ui.selectedRow(rows) + delta
+	if i < 0 || i >= len(rows) {
+		return
+	}
+	ui.selJob = rows[i].job
+	ui.selStep = rows[i].step
+	ui.scroll = 0
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+)
+
+func TestParseKeys(t *testing.T) {
+	assert.Equal(t, []key{"q"}, parseKeys([]byte("q")))
+	assert.Equal(t, []key{keyUp, keyDown, "j"}, parseKeys([]byte("\x1b[A\x1b[Bj")))
+	assert.Equal(t, []key{keyPageUp, keyPageDown, keyHome, keyEnd}, parseKeys([]byte("\x1b[5~\x1b[6~\x1b[H\x1b[F")))
+	assert.Equal(t, []key{keyCtrlC}, parseKeys([]byte{3}))
+	// unknown sequences like F5 are dropped
+	assert.Equal(t, []key{"r"}, parseKeys([]byte("\x1b[15~r")))
+	assert.Equal(t, []key{}, parseKeys([]byte("\x1b")))
+}
+
+func TestHandleKey(t *testing.T) {
+	ctx := context.Background()
+	ui := New(nil, nil)
+	_, plan := newTestWorkflow()
+	build := plan.Stages[0].Runs[0]
+	ui.PlanStarted(ctx, &runner.PlanEvent{Plan: plan})
+
+	cancelled := false
+	job := &runner.JobEvent{Run: build, Name: "ci/build", Cancel: func() { cancelled = true }}
+	ui.JobStarted(ctx, job)
+
+	selected := func() string {
+		j, s := ui.selectedJob()
+		if s != nil {
+			return s.name
+		}
+		return j.name
+	}
+
+	// the steps of the selected job are listed below it
+	assert.Equal(t, "ci/build", selected())
+	assert.Equal(t, actionNone, ui.handleKey(keyUp))
+	assert.Equal(t, "ci/build", selected())
+	ui.handleKey(keyDown)
+	assert.Equal(t, "make", selected())
+	ui.handleKey("j")
+	ui.handleKey("j")
+	assert.Equal(t, "ci/test", selected())
+	ui.handleKey("j")
+	assert.Equal(t, "ci/test", selected())
+	ui.handleKey("k")
+	assert.Equal(t, "ci/build", selected())
+
+	ui.handleKey("c")
+	assert.True(t, cancelled)
+	assert.Equal(t, statusCancelled, ui.jobs[0].status)
+	assert.Equal(t, "Cancelled job ci/build", ui.message)
+	ui.JobCompleted(ctx, &runner.JobEvent{Run: build, Name: "ci/build", Result: "failure"})
+	assert.Equal(t, statusCancelled, ui.jobs[0].status)
+
+	ui.handleKey("c")
+	assert.Equal(t, "The selected job is not running", ui.message)
+
+	r := &runnerMock{plans: make(chan *model.Plan, 1)}
+	ui.runner = r
+	ui.ctx = ctx
+	ui.handleKey("r")
+	assert.Equal(t, "Rerunning job ci/build", ui.message)
+	ui.wg.Wait()
+	assert.Equal(t, build, (<-r.plans).Stages[0].Runs[0])
+
+	ui.selJob = ui.jobs[1]
+	ui.handleKey("r")
+	assert.Equal(t, "Only failed and cancelled jobs can be rerun", ui.message)
+
+	assert.Equal(t, actionShell, ui.handleKey("s"))
+	ui.handleKey("p")
+	assert.True(t, ui.showGraph)
+	ui.handleKey("p")
+	assert.False(t, ui.showGraph)
+	assert.Equal(t, actionQuit, ui.handleKey("q"))
+	assert.Equal(t, actionQuit, ui.handleKey(keyCtrlC))
+}
+
+type runnerMock struct {
+	plans chan *model.Plan
+}
+
+func (r *runnerMock) NewPlanExecutor(plan *model.Plan) common.Executor {
+	return func(ctx context.Context) error {
+		r.plans <- plan
+		return nil
+	}
+}
+
+func TestShellInput(t *testing.T) {
+	input := &shellInput{}
+	assert.False(t, input.write([]byte("q")))
+
+	r := input.start()
+	go func() {
+		assert.True(t, input.write([]byte("ls\r")))
+		input.stop()
+	}()
+	b, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "ls\r", string(b))
+	assert.False(t, input.write([]byte("q")))
+}
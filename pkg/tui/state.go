@@ -0,0 +1,357 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+)
+
+type status int
+
+const (
+	statusPending status = iota
+	statusRunning
+	statusSuccess
+	statusFailure
+	statusSkipped
+	statusCancelled
+)
+
+// job is a planned job, or a run of its matrix once it started
+type job struct {
+	run       *model.Run
+	name      string // name of the job, includes the index of the matrix
+	stage     int
+	status    status
+	steps     []*step
+	logs      []logLine
+	startTime time.Time
+	duration  time.Duration
+	event     *runner.JobEvent // last event of the job, to cancel it or open a shell in its container
+	current   *step            // innermost running step, the logs of the job belong to it
+}
+
+// step is a step of a job, the steps of composite actions are nested below the step running the action
+type step struct {
+	id        string
+	name      string
+	depth     int
+	composite string // id of the step running the composite action the step belongs to
+	status    status
+	startTime time.Time
+	duration  time.Duration
+}
+
+type logLine struct {
+	step string // key of the step the line belongs to, empty for the logs of the job
+	text string
+	raw  bool // output of a container
+	err  bool // logged at error level
+}
+
+// maxLogLines is the number of log lines kept per job
+const maxLogLines = 10000
+
+// findJob returns the job with the name, the lock has to be held
+func (ui *UI) findJob(name string) *job {
+	for _, j := range ui.jobs {
+		if j.name == name {
+			return j
+		}
+	}
+	return nil
+}
+
+// startJob returns the job a run of a matrix belongs to, the planned job becomes the first run of its matrix, the lock has to be held
+func (ui *UI) startJob(event *runner.JobEvent) *job {
+	if j := ui.findJob(event.Name); j != nil {
+		return j
+	}
+	insertAt := len(ui.jobs)
+	stage := 0
+	for i, j := range ui.jobs {
+		if j.run.Workflow != event.Run.Workflow || j.run.JobID != event.Run.JobID {
+			continue
+		}
+		if j.status == statusPending && j.name == plannedName(j.run) {
+			j.name = event.Name
+			return j
+		}
+		insertAt = i + 1
+		stage = j.stage
+	}
+	j := &job{run: event.Run, name: event.Name, stage: stage}
+	ui.jobs = append(ui.jobs[:insertAt], append([]*job{j}, ui.jobs[insertAt:]...)...)
+	return j
+}
+
+// plannedName returns the name of a job before the runs of its matrix are known
+func plannedName(run *model.Run) string {
+	return fmt.Sprintf("%s/%s", run.Workflow.Name, run.String())
+}
+
+func (j *job) findStep(id string, composite string) *step {
+	for _, s := range j.steps {
+		if s.id == id && s.composite == composite {
+			return s
+		}
+	}
+	return nil
+}
+
+// addStep adds a step of a composite action below the step running the action and its previous steps
+func (j *job) addStep(event *runner.StepEvent) *step {
+	s := &step{
+		id:        event.Step.ID,
+		name:      event.Step.String(),
+		composite: event.Composite,
+		depth:     strings.Count(event.Composite, "-composite-") + 1,
+	}
+	insertAt := len(j.steps)
+	for i, parent := range j.steps {
+		if parent.key() != event.Composite {
+			continue
+		}
+		for insertAt = i + 1; insertAt < len(j.steps) && j.steps[insertAt].depth > parent.depth; insertAt++ {
+		}
+		break
+	}
+	j.steps = append(j.steps[:insertAt], append([]*step{s}, j.steps[insertAt:]...)...)
+	return s
+}
+
+// key returns the id the steps of the composite action run by the step refer to
+func (s *step) key() string {
+	if s.composite == "" {
+		return s.id
+	}
+	return s.composite + "-composite-" + s.id
+}
+
+// findStepByKey returns the step the steps of a composite action refer to, nil for an empty key
+func (j *job) findStepByKey(key string) *step {
+	for _, s := range j.steps {
+		if key != "" && s.key() == key {
+			return s
+		}
+	}
+	return nil
+}
+
+// addLog adds a line to the log of the job, it belongs to the running step
+func (j *job) addLog(line logLine) {
+	if j.current != nil {
+		line.step = j.current.key()
+	}
+	j.logs = append(j.logs, line)
+	if len(j.logs) > maxLogLines {
+		j.logs = j.logs[len(j.logs)-maxLogLines:]
+	}
+}
+
+// PlanStarted adds the jobs of the plan, the jobs of chained plans are added as further stages
+func (ui *UI) PlanStarted(ctx context.Context, event *runner.PlanEvent) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	defer ui.changed()
+
+	ui.running++
+	offset := 0
+	for _, j := range ui.jobs {
+		if j.stage >= offset {
+			offset = j.stage + 1
+		}
+	}
+	for i, stage := range event.Plan.Stages {
+		for _, run := range stage.Runs {
+			planned := false
+			for _, j := range ui.jobs {
+				planned = planned || j.run.Workflow == run.Workflow && j.run.JobID == run.JobID
+			}
+			if !planned {
+				ui.jobs = append(ui.jobs, &job{run: run, name: plannedName(run), stage: offset + i})
+			}
+		}
+	}
+}
+
+// PlanCompleted marks the jobs that did not run as skipped
+func (ui *UI) PlanCompleted(ctx context.Context, event *runner.PlanEvent) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	defer ui.changed()
+
+	ui.running--
+	if ui.running > 0 {
+		return
+	}
+	for _, j := range ui.jobs {
+		if j.status == statusPending {
+			j.status = statusSkipped
+		}
+	}
+}
+
+// JobStarted shows the job as running with its steps pending
+func (ui *UI) JobStarted(ctx context.Context, event *runner.JobEvent) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	defer ui.changed()
+
+	j := ui.startJob(event)
+	j.status = statusRunning
+	j.startTime = time.Now()
+	j.duration = 0
+	j.event = event
+	j.logs = nil
+	j.steps = nil
+	j.current = nil
+	for _, s := range event.Run.Job().Steps {
+		j.steps = append(j.steps, &step{id: s.ID, name: s.String()})
+	}
+}
+
+// JobCompleted shows the result of the job
+func (ui *UI) JobCompleted(ctx context.Context, event *runner.JobEvent) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	defer ui.changed()
+
+	j := ui.startJob(event)
+	j.duration = event.Duration
+	j.event = nil
+	j.current = nil
+	switch {
+	case j.status == statusCancelled:
+	case event.Result == "success":
+		j.status = statusSuccess
+	case event.Result == "cancelled":
+		j.status = statusCancelled
+	default:
+		j.status = statusFailure
+	}
+	for _, s := range j.steps {
+		if s.status == statusPending || s.status == statusRunning {
+			s.status = statusSkipped
+		}
+	}
+}
+
+// StepStarted shows the step as running
+func (ui *UI) StepStarted(ctx context.Context, event *runner.StepEvent) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	defer ui.changed()
+
+	j := ui.startJob(event.Job)
+	if j.event != nil {
+		j.event.Container = event.Job.Container
+	}
+	s := j.findStep(event.Step.ID, event.Composite)
+	if s == nil {
+		s = j.addStep(event)
+	}
+	s.status = statusRunning
+	s.startTime = time.Now()
+	j.current = s
+}
+
+// StepCompleted shows the conclusion of the step
+func (ui *UI) StepCompleted(ctx context.Context, event *runner.StepEvent) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	defer ui.changed()
+
+	j := ui.startJob(event.Job)
+	s := j.findStep(event.Step.ID, event.Composite)
+	if s == nil {
+		s = j.addStep(event)
+	}
+	s.duration = event.Duration
+	j.current = j.findStepByKey(event.Composite)
+	switch event.Result.Conclusion {
+	case model.StepStatusSuccess:
+		s.status = statusSuccess
+		if event.Result.Outcome == model.StepStatusFailure {
+			s.status = statusFailure
+		}
+	case model.StepStatusSkipped:
+		s.status = statusSkipped
+	default:
+		s.status = statusFailure
+	}
+}
+
+// Output adds a line printed by a container to the log of its job
+func (ui *UI) Output(ctx context.Context, event *runner.OutputEvent) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	defer ui.changed()
+
+	line := logLine{text: event.Line, raw: true}
+	if j := ui.findJob(event.Job.Name); j != nil {
+		j.addLog(line)
+	} else {
+		ui.addLog(line)
+	}
+}
+
+// logWriter collects the json logs of a job, or the logs of act if job is empty
+type logWriter struct {
+	ui  *UI
+	job string // name of the job
+	buf []byte
+}
+
+// JobWriter returns the writer for the logs of a job
+func (w *logWriter) JobWriter(jobName string) io.Writer {
+	return &logWriter{ui: w.ui, job: jobName}
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.ui.mu.Lock()
+	defer w.ui.mu.Unlock()
+	defer w.ui.changed()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := w.buf[:i]
+		w.buf = w.buf[i+1:]
+
+		logLine := logLine{text: string(line)}
+		entry := runner.LogEntry{}
+		if err := json.Unmarshal(line, &entry); err == nil {
+			if entry.RawOutput {
+				// the lines of the containers are added by Output
+				continue
+			}
+			logLine.text = strings.TrimSuffix(entry.Message, "\n")
+			logLine.err = entry.Level == "error" || entry.Level == "fatal"
+		}
+		if j := w.ui.findJob(w.job); j != nil {
+			j.addLog(logLine)
+		} else {
+			w.ui.addLog(logLine)
+		}
+	}
+	return len(p), nil
+}
+
+// addLog adds a line to the log of act itself, the lock has to be held
+func (ui *UI) addLog(line logLine) {
+	ui.logs = append(ui.logs, line)
+	if len(ui.logs) > maxLogLines {
+		ui.logs = ui.logs[len(ui.logs)-maxLogLines:]
+	}
+}
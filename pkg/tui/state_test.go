@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+)
+
+func newTestWorkflow() (*model.Workflow, *model.Plan) {
+	workflow := &model.Workflow{
+		Name: "ci",
+		Jobs: map[string]*model.Job{
+			"build": {Steps: []*model.Step{{ID: "0", Run: "make"}, {ID: "action", Uses: "./composite"}}},
+			"test":  {RawNeeds: yaml.Node{Kind: yaml.ScalarNode, Value: "build"}},
+		},
+	}
+	plan := &model.Plan{Stages: []*model.Stage{
+		{Runs: []*model.Run{{Workflow: workflow, JobID: "build"}}},
+		{Runs: []*model.Run{{Workflow: workflow, JobID: "test"}}},
+	}}
+	return workflow, plan
+}
+
+func jobNames(ui *UI) []string {
+	names := make([]string, 0)
+	for _, j := range ui.jobs {
+		names = append(names, fmt.Sprintf("%d %s %d", j.stage, j.name, j.status))
+	}
+	return names
+}
+
+func TestUIJobs(t *testing.T) {
+	ctx := context.Background()
+	ui := New(nil, nil)
+	_, plan := newTestWorkflow()
+	build := plan.Stages[0].Runs[0]
+
+	ui.PlanStarted(ctx, &runner.PlanEvent{Plan: plan})
+	assert.Equal(t, []string{"0 ci/build 0", "1 ci/test 0"}, jobNames(ui))
+
+	// the runs of a matrix replace the planned job
+	leg1 := &runner.JobEvent{Run: build, Name: "ci/build-1"}
+	leg2 := &runner.JobEvent{Run: build, Name: "ci/build-2"}
+	ui.JobStarted(ctx, leg1)
+	ui.JobStarted(ctx, leg2)
+	assert.Equal(t, []string{"0 ci/build-1 1", "0 ci/build-2 1", "1 ci/test 0"}, jobNames(ui))
+
+	ui.JobCompleted(ctx, &runner.JobEvent{Run: build, Name: "ci/build-1", Result: "success"})
+	ui.JobCompleted(ctx, &runner.JobEvent{Run: build, Name: "ci/build-2", Result: "failure"})
+	ui.PlanCompleted(ctx, &runner.PlanEvent{Plan: plan})
+	assert.Equal(t, []string{"0 ci/build-1 2", "0 ci/build-2 3", "1 ci/test 4"}, jobNames(ui))
+
+	// a rerun starts the job again
+	ui.PlanStarted(ctx, &runner.PlanEvent{Plan: &model.Plan{Stages: []*model.Stage{{Runs: []*model.Run{build}}}}})
+	ui.JobStarted(ctx, leg2)
+	assert.Equal(t, []string{"0 ci/build-1 2", "0 ci/build-2 1", "1 ci/test 4"}, jobNames(ui))
+}
+
+func TestUISteps(t *testing.T) {
+	ctx := context.Background()
+	ui := New(nil, nil)
+	_, plan := newTestWorkflow()
+	build := plan.Stages[0].Runs[0]
+	job := &runner.JobEvent{Run: build, Name: "ci/build"}
+
+	ui.PlanStarted(ctx, &runner.PlanEvent{Plan: plan})
+	ui.JobStarted(ctx, job)
+	steps := build.Job().Steps
+	ui.StepStarted(ctx, &runner.StepEvent{Job: job, Step: steps[0]})
+	ui.StepCompleted(ctx, &runner.StepEvent{Job: job, Step: steps[0], Result: &model.StepResult{
+		Outcome:    model.StepStatusFailure,
+		Conclusion: model.StepStatusSuccess,
+	}})
+	ui.StepStarted(ctx, &runner.StepEvent{Job: job, Step: steps[1]})
+
+	// the steps of composite actions are nested below the step running the action
+	inner := []*model.Step{{ID: "0", Run: "echo one"}, {ID: "1", Uses: "./nested"}}
+	ui.StepStarted(ctx, &runner.StepEvent{Job: job, Step: inner[0], Composite: "action"})
+	ui.StepCompleted(ctx, &runner.StepEvent{Job: job, Step: inner[0], Composite: "action", Result: &model.StepResult{Conclusion: model.StepStatusSuccess}})
+	ui.StepStarted(ctx, &runner.StepEvent{Job: job, Step: inner[1], Composite: "action"})
+	ui.StepStarted(ctx, &runner.StepEvent{Job: job, Step: &model.Step{ID: "0", Run: "echo nested"}, Composite: "action-composite-1"})
+	ui.JobCompleted(ctx, &runner.JobEvent{Run: build, Name: "ci/build", Result: "failure"})
+
+	names := make([]string, 0)
+	for _, s := range ui.jobs[0].steps {
+		names = append(names, fmt.Sprintf("%d %s %d", s.depth, s.name, s.status))
+	}
+	assert.Equal(t, []string{
+		"0 make 3",
+		"0 ./composite 4",
+		"1 echo one 2",
+		"1 ./nested 4",
+		"2 echo nested 4",
+	}, names)
+}
+
+func TestUILogWriter(t *testing.T) {
+	ctx := context.Background()
+	ui := New(nil, nil)
+	_, plan := newTestWorkflow()
+	build := plan.Stages[0].Runs[0]
+	job := &runner.JobEvent{Run: build, Name: "ci/build"}
+	steps := build.Job().Steps
+	ui.PlanStarted(ctx, &runner.PlanEvent{Plan: plan})
+	ui.JobStarted(ctx, job)
+
+	// the lines belong to the running step, the ones of the containers come from Output
+	w := (&logWriter{ui: ui}).JobWriter("ci/build")
+	_, err := w.Write([]byte(`{"level":"info","msg":"Start image"}` + "\n"))
+	assert.NoError(t, err)
+	ui.StepStarted(ctx, &runner.StepEvent{Job: job, Step: steps[0]})
+	_, err = w.Write([]byte(`{"level":"info","msg":"Run make"}` + "\n" + `{"level":"info","msg":"ok\n","raw_output":true}` + "\n" + `{"lev`))
+	assert.NoError(t, err)
+	ui.Output(ctx, &runner.OutputEvent{Job: job, StepID: "0", Line: "ok"})
+	_, err = w.Write([]byte(`el":"error","msg":"failed"}` + "\n"))
+	assert.NoError(t, err)
+	ui.StepCompleted(ctx, &runner.StepEvent{Job: job, Step: steps[0], Result: &model.StepResult{Conclusion: model.StepStatusFailure}})
+
+	inner := &model.Step{ID: "0", Run: "echo one"}
+	ui.StepStarted(ctx, &runner.StepEvent{Job: job, Step: steps[1]})
+	ui.StepStarted(ctx, &runner.StepEvent{Job: job, Step: inner, Composite: "action"})
+	_, err = w.Write([]byte(`{"level":"info","msg":"Run echo one"}` + "\n"))
+	assert.NoError(t, err)
+	ui.StepCompleted(ctx, &runner.StepEvent{Job: job, Step: inner, Composite: "action", Result: &model.StepResult{Conclusion: model.StepStatusSuccess}})
+	_, err = w.Write([]byte(`{"level":"info","msg":"Success"}` + "\n"))
+	assert.NoError(t, err)
+
+	_, err = (&logWriter{ui: ui}).Write([]byte("not json\n"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []logLine{
+		{text: "Start image"},
+		{step: "0", text: "Run make"},
+		{step: "0", text: "ok", raw: true},
+		{step: "0", text: "failed", err: true},
+		{step: "action-composite-0", text: "Run echo one"},
+		{step: "action", text: "Success"},
+	}, ui.jobs[0].logs)
+	assert.Equal(t, []logLine{{text: "not json"}}, ui.logs)
+
+	// the step running a composite action shows the logs of its steps
+	ui.selJob = ui.jobs[0]
+	ui.selStep = ui.jobs[0].steps[1]
+	assert.Equal(t, []string{" ci/build › ./composite", "Run echo one", "Success"}, trimLines(plainFrame(strings.Join(ui.logLines(40, 4), "\r\n"))[:3]))
+}
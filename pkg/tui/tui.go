@@ -0,0 +1,247 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/term"
+
+	"github.com/nektos/act/pkg/common"
+	"github.com/nektos/act/pkg/container"
+	"github.com/nektos/act/pkg/model"
+	"github.com/nektos/act/pkg/runner"
+)
+
+// UI is an interactive terminal UI showing the jobs of a run with their steps and logs.
+// It implements runner.Hooks and runner.OutputHooks and reads the logs of the jobs from their json logger, see Attach.
+type UI struct {
+	runner.NoopHooks
+	mu      sync.Mutex
+	in      *os.File
+	out     *os.File
+	jobs    []*job    // planned jobs and the runs of their matrix, in the order of their stages
+	logs    []logLine // logs of act that don't belong to a job
+	running int       // number of plans running
+	err     error     // error of the run once it completed
+	done    bool
+
+	selJob    *job  // selected job, the first job if nil
+	selStep   *step // selected step of the selected job, nil if the job is selected
+	scroll    int   // number of lines the log is scrolled up from its end
+	logHeight int   // height of the log in the last frame
+	showGraph bool  // the plan graph is shown instead of the log
+	message   string
+	dirty     chan struct{}
+
+	runner runner.Runner
+	ctx    context.Context
+	wg     sync.WaitGroup
+}
+
+// New creates a UI reading keys from in and drawing to out, both have to be a terminal
+func New(in *os.File, out *os.File) *UI {
+	return &UI{
+		in:    in,
+		out:   out,
+		dirty: make(chan struct{}, 1),
+	}
+}
+
+// Attach sets the UI as hooks and log writer of the config, the logs of the jobs are switched to json to tell the
+// errors apart, they belong to the step running when they are written
+func (ui *UI) Attach(config *runner.Config) {
+	config.JSONLogger = true
+	config.LogWriter = &logWriter{ui: ui}
+	if config.Hooks != nil {
+		config.Hooks = runner.MultiHooks(config.Hooks, ui)
+	} else {
+		config.Hooks = ui
+	}
+}
+
+// changed redraws the UI with the next frame
+func (ui *UI) changed() {
+	select {
+	case ui.dirty <- struct{}{}:
+	default:
+	}
+}
+
+// Run shows the UI while the executor runs, it returns the error of the executor once the user quit
+func (ui *UI) Run(ctx context.Context, r runner.Runner, executor common.Executor) error {
+	if !term.IsTerminal(int(ui.in.Fd())) || !term.IsTerminal(int(ui.out.Fd())) {
+		return fmt.Errorf("the terminal UI requires a terminal")
+	}
+	state, err := term.MakeRaw(int(ui.in.Fd()))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = term.Restore(int(ui.in.Fd()), state)
+	}()
+
+	// logs of act itself would break the screen
+	logOutput := log.StandardLogger().Out
+	log.SetOutput(&logWriter{ui: ui})
+	defer log.SetOutput(logOutput)
+
+	fmt.Fprint(ui.out, enterScreen)
+	defer fmt.Fprint(ui.out, leaveScreen)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ui.runner = r
+	ui.ctx = ctx
+
+	planDone := make(chan error, 1)
+	go func() {
+		planDone <- executor(ctx)
+	}()
+
+	keys := make(chan key)
+	shellInput := &shellInput{}
+	go readKeys(ui.in, keys, shellInput)
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	quitting := false
+	for {
+		select {
+		case err := <-planDone:
+			ui.mu.Lock()
+			ui.done = true
+			ui.err = err
+			ui.mu.Unlock()
+			if quitting {
+				ui.wg.Wait()
+				return err
+			}
+			ui.draw()
+		case k := <-keys:
+			switch ui.handleKey(k) {
+			case actionQuit:
+				ui.mu.Lock()
+				done := ui.done
+				ui.message = "Cancelling the run..."
+				ui.mu.Unlock()
+				if done {
+					cancel()
+					ui.wg.Wait()
+					return ui.err
+				}
+				quitting = true
+				cancel()
+			case actionShell:
+				ui.shell(shellInput)
+			}
+			ui.draw()
+		case <-ticker.C:
+			// redraw on changes, and while jobs run to update their elapsed time
+			select {
+			case <-ui.dirty:
+				ui.draw()
+			default:
+				if ui.isRunning() {
+					ui.draw()
+				}
+			}
+		}
+	}
+}
+
+// draw renders a frame of the size of the terminal
+func (ui *UI) draw() {
+	width, height, err := term.GetSize(int(ui.out.Fd()))
+	if err != nil {
+		return
+	}
+	ui.mu.Lock()
+	frame := ui.render(width, height, time.Now())
+	ui.mu.Unlock()
+	_, _ = io.WriteString(ui.out, frame)
+}
+
+func (ui *UI) isRunning() bool {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	return ui.running > 0
+}
+
+// selectedJob returns the selected job and step, the lock has to be held
+func (ui *UI) selectedJob() (*job, *step) {
+	if ui.selJob == nil && len(ui.jobs) > 0 {
+		return ui.jobs[0], nil
+	}
+	return ui.selJob, ui.selStep
+}
+
+// cancelJob cancels the selected job if it is running
+func (ui *UI) cancelJob() {
+	j, _ := ui.selectedJob()
+	if j == nil || j.status != statusRunning || j.event == nil || j.event.Cancel == nil {
+		ui.message = "The selected job is not running"
+		return
+	}
+	j.status = statusCancelled
+	j.event.Cancel()
+	ui.message = fmt.Sprintf("Cancelled job %s", j.name)
+}
+
+// rerunJob runs the job of the selected row again, with all runs of its matrix
+func (ui *UI) rerunJob() {
+	j, _ := ui.selectedJob()
+	if j == nil || (j.status != statusFailure && j.status != statusCancelled) {
+		ui.message = "Only failed and cancelled jobs can be rerun"
+		return
+	}
+	for _, other := range ui.jobs {
+		if other.run == j.run && other.status == statusRunning {
+			ui.message = fmt.Sprintf("Job %s is still running", other.name)
+			return
+		}
+	}
+
+	run := j.run
+	plan := &model.Plan{Stages: []*model.Stage{{Runs: []*model.Run{run}}}}
+	ui.message = fmt.Sprintf("Rerunning job %s", plannedName(run))
+	ui.wg.Add(1)
+	go func() {
+		defer ui.wg.Done()
+		if err := ui.runner.NewPlanExecutor(plan)(ui.ctx); err != nil {
+			log.Errorf("Rerun of job %s failed: %v", plannedName(run), err)
+		}
+	}()
+}
+
+// shell opens an interactive shell in the container of the selected job, the UI is hidden until the shell exits
+func (ui *UI) shell(input *shellInput) {
+	ui.mu.Lock()
+	j, _ := ui.selectedJob()
+	if j == nil || j.status != statusRunning || j.event == nil || j.event.Container == nil {
+		ui.message = "The container of the selected job is not running"
+		ui.mu.Unlock()
+		return
+	}
+	name := j.name
+	jobContainer := j.event.Container
+	ui.mu.Unlock()
+
+	fmt.Fprint(ui.out, leaveScreen)
+	fmt.Fprintf(ui.out, "Opening a shell in the container of %s, exit it to return\r\n", name)
+	stdin := input.start()
+	err := jobContainer.Shell(container.DefaultShell, nil, "", "", stdin, ui.out)(ui.ctx)
+	input.stop()
+	fmt.Fprint(ui.out, enterScreen)
+
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	ui.message = fmt.Sprintf("Closed the shell in %s", name)
+	if err != nil {
+		ui.message = fmt.Sprintf("Failed to open a shell in %s: %v", name, err)
+	}
+}
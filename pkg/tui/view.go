@@ -0,0 +1,321 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l"
+	leaveScreen = "\x1b[?25h\x1b[?1049l"
+	cursorHome  = "\x1b[H"
+	clearToEOL  = "\x1b[K"
+
+	styleReset   = "\x1b[0m"
+	styleBold    = "\x1b[1m"
+	styleDim     = "\x1b[2m"
+	styleReverse = "\x1b[7m"
+	styleRed     = "\x1b[31m"
+	styleGreen   = "\x1b[32m"
+	styleYellow  = "\x1b[33m"
+	styleBlue    = "\x1b[34m"
+)
+
+const (
+	maxJobsWidth  = 48 // maximum width of the job list, the log takes the rest of the screen
+	minLogWidth   = 20 // the log is hidden on narrower screens
+	stepIndent    = 4
+	nestedIndent  = 2 // indent of the steps of composite actions per level
+	tabWidth      = 4
+	separator     = "│"
+	stepSeparator = " › "
+	helpLine      = " ↑↓ select  PgUp/PgDn/Home/End scroll  c cancel  r rerun  s shell  p graph  q quit"
+)
+
+var spinner = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;?]*[a-zA-Z]|\x1b[()][0-9A-Za-z]|\r")
+
+// row is a row of the job list, the steps of the selected job are listed below it
+type row struct {
+	job  *job
+	step *step
+}
+
+// rows returns the rows of the job list, the lock has to be held
+func (ui *UI) rows() []row {
+	selected, _ := ui.selectedJob()
+	rows := make([]row, 0, len(ui.jobs))
+	for _, j := range ui.jobs {
+		rows = append(rows, row{job: j})
+		if j == selected {
+			for _, s := range j.steps {
+				rows = append(rows, row{job: j, step: s})
+			}
+		}
+	}
+	return rows
+}
+
+// selectedRow returns the index of the selected row, the row of the job if its selected step is gone, the lock has to be held
+func (ui *UI) selectedRow(rows []row) int {
+	j, s := ui.selectedJob()
+	jobRow := 0
+	for i, r := range rows {
+		if r.job == j && r.step == nil {
+			jobRow = i
+		}
+		if r.job == j && r.step == s {
+			return i
+		}
+	}
+	return jobRow
+}
+
+func icon(s status, now time.Time) string {
+	switch s {
+	case statusRunning:
+		return styleBlue + spinner[now.UnixNano()/int64(100*time.Millisecond)%int64(len(spinner))] + styleReset
+	case statusSuccess:
+		return styleGreen + "✓" + styleReset
+	case statusFailure:
+		return styleRed + "✗" + styleReset
+	case statusSkipped:
+		return styleDim + "-" + styleReset
+	case statusCancelled:
+		return styleYellow + "⊘" + styleReset
+	default:
+		return styleDim + "·" + styleReset
+	}
+}
+
+func formatDuration(status status, start time.Time, duration time.Duration, now time.Time) string {
+	switch status {
+	case statusRunning:
+		return now.Sub(start).Truncate(time.Second).String()
+	case statusPending, statusSkipped:
+		return ""
+	default:
+		return duration.Round(time.Second).String()
+	}
+}
+
+// fit cuts s to width runes and pads it with spaces, s must not contain escape sequences
+func fit(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		if width <= 0 {
+			return ""
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
+
+// wrap splits s into lines of width runes
+func wrap(s string, width int) []string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return []string{s}
+	}
+	lines := make([]string, 0, len(runes)/width+1)
+	for len(runes) > width {
+		lines = append(lines, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(lines, string(runes))
+}
+
+// listLine renders an entry of the job list with an icon, a name and a dimmed suffix right aligned
+func listLine(indent int, icon string, name string, suffix string, width int, selected bool) string {
+	nameWidth := width - indent - 2 - len([]rune(suffix))
+	if suffix != "" {
+		nameWidth--
+	}
+	if nameWidth < 1 {
+		nameWidth = width - indent - 2
+		suffix = ""
+	}
+	text := fit(name, nameWidth)
+	if suffix != "" {
+		text += " " + styleDim + suffix + styleReset
+	}
+	if selected {
+		text = styleReverse + text + styleReset
+	}
+	return strings.Repeat(" ", indent) + icon + " " + text
+}
+
+// jobList renders the stages with their jobs and the steps of the selected job, the lock has to be held
+func (ui *UI) jobList(width int, height int, now time.Time) []string {
+	rows := ui.rows()
+	selected := ui.selectedRow(rows)
+
+	lines := make([]string, 0)
+	selectedLine := 0
+	stage := -1
+	for i, r := range rows {
+		if r.step == nil && r.job.stage != stage {
+			stage = r.job.stage
+			lines = append(lines, styleBold+fit(fmt.Sprintf(" Stage %d", stage+1), width)+styleReset)
+		}
+		if i == selected {
+			selectedLine = len(lines)
+		}
+		if r.step == nil {
+			j := r.job
+			suffix := formatDuration(j.status, j.startTime, j.duration, now)
+			if needs := j.run.Job().Needs(); len(needs) > 0 && suffix == "" {
+				suffix = "← " + strings.Join(needs, ", ")
+			}
+			lines = append(lines, listLine(1, icon(j.status, now), j.name, suffix, width, i == selected))
+			continue
+		}
+		s := r.step
+		suffix := formatDuration(s.status, s.startTime, s.duration, now)
+		lines = append(lines, listLine(stepIndent+nestedIndent*s.depth, icon(s.status, now), s.name, suffix, width, i == selected))
+	}
+
+	// keep the selected row visible
+	if len(lines) > height {
+		offset := selectedLine - height/2
+		if offset < 0 {
+			offset = 0
+		}
+		if offset > len(lines)-height {
+			offset = len(lines) - height
+		}
+		lines = lines[offset : offset+height]
+	}
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return lines
+}
+
+// logLines renders the end of the log of the selected job or step, scrolled up by ui.scroll, the lock has to be held
+func (ui *UI) logLines(width int, height int) []string {
+	j, s := ui.selectedJob()
+	title := " act"
+	logs := ui.logs
+	if j != nil {
+		title = " " + j.name
+		logs = j.logs
+	}
+	if s != nil {
+		title += stepSeparator + s.name
+		// the step running a composite action shows the logs of its steps too
+		key := s.key()
+		filtered := make([]logLine, 0)
+		for _, l := range logs {
+			if l.step == key || strings.HasPrefix(l.step, key+"-composite-") {
+				filtered = append(filtered, l)
+			}
+		}
+		logs = filtered
+	}
+
+	wrapped := make([]string, 0, len(logs))
+	for _, l := range logs {
+		text := strings.ReplaceAll(ansiPattern.ReplaceAllString(l.text, ""), "\t", strings.Repeat(" ", tabWidth))
+		prefix := ""
+		if l.raw {
+			prefix = "  "
+		}
+		for _, line := range wrap(prefix+text, width) {
+			line = fit(line, width)
+			switch {
+			case l.err:
+				line = styleRed + line + styleReset
+			case !l.raw:
+				line = styleDim + line + styleReset
+			}
+			wrapped = append(wrapped, line)
+		}
+	}
+
+	bodyHeight := height - 1
+	ui.logHeight = bodyHeight
+	if ui.scroll > len(wrapped)-bodyHeight {
+		ui.scroll = len(wrapped) - bodyHeight
+	}
+	if ui.scroll < 0 {
+		ui.scroll = 0
+	}
+	end := len(wrapped) - ui.scroll
+	start := end - bodyHeight
+	if start < 0 {
+		start = 0
+	}
+
+	if ui.scroll > 0 {
+		title += fmt.Sprintf(" (%d more lines below)", ui.scroll)
+	}
+	lines := []string{styleBold + fit(title, width) + styleReset}
+	lines = append(lines, wrapped[start:end]...)
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return lines
+}
+
+// header renders the counts of the jobs by status and the result of the run, the lock has to be held
+func (ui *UI) header(width int) string {
+	counts := make(map[status]int)
+	for _, j := range ui.jobs {
+		counts[j.status]++
+	}
+	text := fmt.Sprintf(" act  %d running  %d succeeded  %d failed  %d pending", counts[statusRunning], counts[statusSuccess], counts[statusFailure]+counts[statusCancelled], counts[statusPending])
+	if ui.done {
+		if ui.err != nil {
+			text += "  |  " + ui.err.Error()
+		} else {
+			text += "  |  completed"
+		}
+	}
+	return styleReverse + fit(text, width) + styleReset
+}
+
+// render returns a frame of the UI drawing the whole screen, the lock has to be held
+func (ui *UI) render(width int, height int, now time.Time) string {
+	if height < 3 {
+		return cursorHome
+	}
+	jobsWidth := width / 3
+	if jobsWidth > maxJobsWidth {
+		jobsWidth = maxJobsWidth
+	}
+	logWidth := width - jobsWidth - 1
+	if logWidth < minLogWidth {
+		jobsWidth = width
+		logWidth = 0
+	}
+
+	bodyHeight := height - 2
+	jobLines := ui.jobList(jobsWidth, bodyHeight, now)
+	var logLines []string
+	if logWidth > 0 && ui.showGraph {
+		logLines = ui.graphLines(logWidth, bodyHeight, now)
+	} else if logWidth > 0 {
+		logLines = ui.logLines(logWidth, bodyHeight)
+	}
+
+	b := &strings.Builder{}
+	b.WriteString(cursorHome)
+	b.WriteString(ui.header(width) + clearToEOL + "\r\n")
+	for i := 0; i < bodyHeight; i++ {
+		b.WriteString(jobLines[i])
+		if logWidth > 0 {
+			b.WriteString(styleDim + separator + styleReset + logLines[i])
+		}
+		b.WriteString(clearToEOL + "\r\n")
+	}
+	footer := helpLine
+	if ui.message != "" {
+		footer = " " + ui.message
+	}
+	b.WriteString(styleDim + fit(footer, width) + styleReset + clearToEOL)
+	return b.String()
+}
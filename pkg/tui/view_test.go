@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nektos/act/pkg/runner"
+)
+
+var stylePattern = regexp.MustCompile("\x1b\\[[0-9;?]*[a-zA-Z]")
+
+// plainFrame returns the lines of a frame without escape sequences
+func plainFrame(frame string) []string {
+	return strings.Split(stylePattern.ReplaceAllString(frame, ""), "\r\n")
+}
+
+func TestFitAndWrap(t *testing.T) {
+	assert.Equal(t, "abc  ", fit("abc", 5))
+	assert.Equal(t, "abc…", fit("abcdef", 4))
+	assert.Equal(t, "", fit("abc", 0))
+	assert.Equal(t, []string{"abc", "def", "g"}, wrap("abcdefg", 3))
+	assert.Equal(t, []string{"äö"}, wrap("äö", 2))
+}
+
+func TestRender(t *testing.T) {
+	ctx := context.Background()
+	ui := New(nil, nil)
+	_, plan := newTestWorkflow()
+	build := plan.Stages[0].Runs[0]
+	job := &runner.JobEvent{Run: build, Name: "ci/build"}
+	ui.PlanStarted(ctx, &runner.PlanEvent{Plan: plan})
+	ui.JobStarted(ctx, job)
+	ui.StepStarted(ctx, &runner.StepEvent{Job: job, Step: build.Job().Steps[0]})
+	_, _ = (&logWriter{ui: ui}).JobWriter("ci/build").Write([]byte(`{"msg":"⭐  Run make"}` + "\n"))
+	ui.Output(ctx, &runner.OutputEvent{Job: job, StepID: "0", Line: "\u001b[32mok\u001b[0m\tdone"})
+
+	ui.jobs[0].startTime = time.Unix(1000, 0)
+	ui.jobs[0].steps[0].startTime = time.Unix(1000, 0)
+	now := time.Unix(1065, 0)
+	lines := plainFrame(ui.render(60, 8, now))
+	assert.Equal(t, []string{
+		" act  1 running  0 succeeded  0 failed  1 pending           ",
+		" Stage 1            │ ci/build                              ",
+		" ⠋ ci/build     1m5s│⭐  Run make                            ",
+		"    ⠋ make      1m5s│  ok    done                           ",
+		"    · ./composite   │                                       ",
+		" Stage 2            │                                       ",
+		" · ci/test   ← build│                                       ",
+		" ↑↓ select  PgUp/PgDn/Home/End scroll  c cancel  r rerun  s…",
+	}, lines)
+}
+
+func TestRenderLogScroll(t *testing.T) {
+	ctx := context.Background()
+	ui := New(nil, nil)
+	_, plan := newTestWorkflow()
+	ui.PlanStarted(ctx, &runner.PlanEvent{Plan: plan})
+	for _, l := range []string{"one", "two", "three", "four"} {
+		ui.addLog(logLine{text: l, raw: true})
+	}
+	ui.jobs = nil
+
+	lines := plainFrame(ui.render(40, 5, time.Now()))
+	assert.Equal(t, []string{" act", "  three", "  four"}, trimLines(lines[1:4]))
+
+	ui.scroll = 1
+	lines = plainFrame(ui.render(40, 5, time.Now()))
+	assert.Equal(t, []string{" act (1 more lines below)", "  two", "  three"}, trimLines(lines[1:4]))
+
+	// the scroll stops at the first line
+	ui.scroll = 10
+	lines = plainFrame(ui.render(40, 5, time.Now()))
+	assert.Equal(t, []string{" act (2 more lines below)", "  one", "  two"}, trimLines(lines[1:4]))
+}
+
+func trimLines(lines []string) []string {
+	trimmed := make([]string, 0, len(lines))
+	for _, l := range lines {
+		trimmed = append(trimmed, strings.TrimRight(strings.TrimPrefix(l, strings.Repeat(" ", 13)+"│"), " "))
+	}
+	return trimmed
+}